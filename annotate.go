@@ -0,0 +1,89 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// waypoint is where a top-level command left the turtle, as drawn.
+type waypoint struct {
+	x, y    float64 // projected if the view is isometric
+	heading float64
+	penDown bool
+}
+
+// Colors of the annotation layer.
+var (
+	annotateStart = color.NRGBA{0x2c, 0xa0, 0x2c, 255}
+	annotateMove  = color.NRGBA{0x1f, 0x77, 0xb4, 255}
+	annotateJump  = color.NRGBA{0x99, 0x99, 0x99, 255}
+	annotateEnd   = color.NRGBA{0xd6, 0x27, 0x28, 255}
+)
+
+// SetAnnotations starts or stops noting where each top-level command
+// leaves the turtle, for Annotations. Starting forgets what was noted.
+func (t *Turtle) SetAnnotations(on bool) {
+	t.annotating, t.waypoints = on, nil
+	if on {
+		t.noteWaypoint()
+	}
+}
+
+// Annotations returns a transparent layer, the size of the canvas, that
+// explains the moves noted since SetAnnotations(true), for worked
+// examples: a green dot where the turtle started, an arrow along each move
+// with the coordinates it ended at, gray for moves with the pen up, and a
+// red arrow showing where the turtle ended and its heading. Draw it over
+// Image. Markers and labels keep their size in pixels however the drawing
+// is scaled.
+func (t *Turtle) Annotations() *image.RGBA {
+	o := newOverlay(t)
+	wp := t.waypoints
+	if len(wp) == 0 {
+		return o.image()
+	}
+	for i := 1; i < len(wp); i++ {
+		a, b := wp[i-1], wp[i]
+		if a.x == b.x && a.y == b.y {
+			continue // a turn, or a change of pen
+		}
+		c := annotateMove
+		if !b.penDown {
+			c = annotateJump
+			o.line(a.x, a.y, b.x, b.y, 1, c)
+		}
+		deg := math.Atan2(b.y-a.y, b.x-a.x) * 180 / math.Pi
+		o.arrow((a.x+b.x)/2, (a.y+b.y)/2, deg, 9, c)
+		o.dot(b.x, b.y, 2.5, c)
+		o.label(b.x, b.y, coords(b.x, b.y), c, false)
+	}
+	start, end := wp[0], wp[len(wp)-1]
+	o.dot(start.x, start.y, 4, annotateStart)
+	o.label(start.x, start.y, coords(start.x, start.y), annotateStart, true)
+
+	// The heading as drawn, which the isometric view turns.
+	cos, sin := math.Cos(end.heading*math.Pi/180), math.Sin(end.heading*math.Pi/180)
+	hx, hy := t.project(cos, sin, 0)
+	deg := math.Atan2(hy, hx) * 180 / math.Pi
+	l := 24 / t.scale
+	tx, ty := end.x+l*math.Cos(deg*math.Pi/180), end.y+l*math.Sin(deg*math.Pi/180)
+	o.line(end.x, end.y, tx, ty, 2, annotateEnd)
+	o.arrow(tx, ty, deg, 10, annotateEnd)
+	heading := math.Mod(end.heading, 360)
+	if heading < 0 {
+		heading += 360
+	}
+	o.label(end.x, end.y, coords(end.x, end.y)+" "+tenths(heading)+"°", annotateEnd, false)
+	return o.image()
+}
+
+// noteWaypoint notes where the turtle is, for Annotations, unless it is
+// where and as it was at the last.
+func (t *Turtle) noteWaypoint() {
+	x, y := t.project(t.x, t.y, t.z)
+	w := waypoint{x, y, t.headingDeg, t.penDown}
+	if n := len(t.waypoints); n == 0 || t.waypoints[n-1] != w {
+		t.waypoints = append(t.waypoints, w)
+	}
+}
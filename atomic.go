@@ -0,0 +1,22 @@
+package gotuga
+
+import (
+	"io"
+
+	"github.com/Z6dev/GoTuga/internal/atomicfile"
+)
+
+// SetSyncSaves makes the turtle's savers flush each file to disk before
+// they return, so a save that finished survives a crash or power cut, at
+// some cost in speed. Saves are atomic either way: an interrupted save
+// leaves the previous file, or none, never part of an image.
+func (t *Turtle) SetSyncSaves(on bool) { t.syncSaves = on }
+
+// SyncSaves reports whether the turtle's savers flush files to disk; see
+// SetSyncSaves.
+func (t *Turtle) SyncSaves() bool { return t.syncSaves }
+
+// writeFile saves filename atomically; see atomicfile.Write.
+func writeFile(filename string, sync bool, write func(w io.Writer) error) error {
+	return atomicfile.Write(filename, sync, write)
+}
@@ -0,0 +1,241 @@
+// Package axidraw plots turtle drawings on paper with an AxiDraw pen
+// plotter, speaking the EiBotBoard (EBB) protocol over its serial port. The
+// caller opens the port, usually /dev/ttyACM0 on Linux or a COM port on
+// Windows, and passes it to New:
+//
+//	port, err := os.OpenFile("/dev/ttyACM0", os.O_RDWR, 0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer port.Close()
+//	err = axidraw.New(port, &axidraw.Options{Scale: 0.5}).Plot(t)
+//
+// Start with the pen carriage at the top-left corner of the paper: it
+// stands for the top-left corner of the turtle's canvas.
+package axidraw
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// stepsPerMM is the AxiDraw's resolution with 1/16 microstepping.
+const stepsPerMM = 2032 / 25.4
+
+// maxMove is the longest move sent as one command, in milliseconds.
+const maxMove = 10000
+
+// Options configures a Plotter. The zero value is usable.
+type Options struct {
+	Scale      float64 // millimetres per canvas pixel, defaults to 0.25
+	SpeedDown  float64 // drawing speed in mm/s, defaults to 25
+	SpeedUp    float64 // travel speed in mm/s, defaults to 75
+	PenUpPos   int     // servo position with the pen up, defaults to 16000
+	PenDownPos int     // servo position with the pen down, defaults to 12000
+	PenDelay   int     // milliseconds to wait for the pen to rise or fall, defaults to 150
+
+	KeepOrder bool // plot paths in drawing order rather than minimizing travel
+}
+
+// Plotter drives an AxiDraw connected through rw.
+type Plotter struct {
+	rw   io.ReadWriter
+	r    *bufio.Reader
+	opts Options
+
+	x, y int  // carriage position in steps from home
+	down bool // pen is down
+}
+
+// New returns a plotter talking to an AxiDraw through rw, typically its
+// serial port.
+func New(rw io.ReadWriter, opts *Options) *Plotter {
+	p := &Plotter{rw: rw, r: bufio.NewReader(rw)}
+	if opts != nil {
+		p.opts = *opts
+	}
+	def := func(v *float64, d float64) {
+		if *v <= 0 {
+			*v = d
+		}
+	}
+	def(&p.opts.Scale, 0.25)
+	def(&p.opts.SpeedDown, 25)
+	def(&p.opts.SpeedUp, 75)
+	if p.opts.PenUpPos <= 0 {
+		p.opts.PenUpPos = 16000
+	}
+	if p.opts.PenDownPos <= 0 {
+		p.opts.PenDownPos = 12000
+	}
+	if p.opts.PenDelay <= 0 {
+		p.opts.PenDelay = 150
+	}
+	return p
+}
+
+// Plot draws the lines of t's retained paths (see gotuga.Turtle.Paths),
+// lifting the pen between them and returning home at the end. Fills are
+// plotted as their outlines. Unless KeepOrder is set, paths are reordered,
+// and reversed where that helps, to cut pen-up travel. Paths are retained
+// only with gotuga.Turtle.SetRetainPaths on, so set it before drawing.
+func (p *Plotter) Plot(t *gotuga.Turtle) error {
+	var lines [][][2]float64
+	for _, path := range t.Paths() {
+		if len(path.Points) < 2 {
+			continue
+		}
+		pts := make([][2]float64, len(path.Points))
+		for i, pt := range path.Points {
+			x, y := t.CanvasPoint(pt[0], pt[1])
+			pts[i] = [2]float64{x * p.opts.Scale, y * p.opts.Scale}
+		}
+		lines = append(lines, pts)
+	}
+	if !p.opts.KeepOrder {
+		lines = Order(lines, [2]float64{})
+	}
+
+	if err := p.setup(); err != nil {
+		return err
+	}
+	for _, pts := range lines {
+		if err := p.PenUp(); err != nil {
+			return err
+		}
+		if err := p.MoveTo(pts[0][0], pts[0][1]); err != nil {
+			return err
+		}
+		if err := p.PenDown(); err != nil {
+			return err
+		}
+		for _, pt := range pts[1:] {
+			if err := p.MoveTo(pt[0], pt[1]); err != nil {
+				return err
+			}
+		}
+	}
+	if err := p.PenUp(); err != nil {
+		return err
+	}
+	if err := p.MoveTo(0, 0); err != nil {
+		return err
+	}
+	return p.command("EM,0,0")
+}
+
+// setup enables the motors and sets the pen heights.
+func (p *Plotter) setup() error {
+	for _, c := range []string{
+		"EM,1,1",
+		fmt.Sprintf("SC,4,%d", p.opts.PenUpPos),
+		fmt.Sprintf("SC,5,%d", p.opts.PenDownPos),
+	} {
+		if err := p.command(c); err != nil {
+			return err
+		}
+	}
+	p.down = true // make sure PenUp raises it
+	return p.PenUp()
+}
+
+// PenUp raises the pen.
+func (p *Plotter) PenUp() error {
+	if !p.down {
+		return nil
+	}
+	p.down = false
+	return p.command(fmt.Sprintf("SP,1,%d", p.opts.PenDelay))
+}
+
+// PenDown lowers the pen.
+func (p *Plotter) PenDown() error {
+	if p.down {
+		return nil
+	}
+	p.down = true
+	return p.command(fmt.Sprintf("SP,0,%d", p.opts.PenDelay))
+}
+
+// MoveTo moves the carriage in a straight line to (x, y), in millimetres
+// right of and below home, at the drawing or travel speed depending on the
+// pen.
+func (p *Plotter) MoveTo(x, y float64) error {
+	tx, ty := int(math.Round(x*stepsPerMM)), int(math.Round(y*stepsPerMM))
+	dx, dy := tx-p.x, ty-p.y
+	if dx == 0 && dy == 0 {
+		return nil
+	}
+	speed := p.opts.SpeedUp
+	if p.down {
+		speed = p.opts.SpeedDown
+	}
+	ms := math.Hypot(float64(dx), float64(dy)) / stepsPerMM / speed * 1000
+	n := int(math.Ceil(ms / maxMove))
+	x0, y0 := p.x, p.y
+	for i := 1; i <= n; i++ {
+		// The AxiDraw's motors each drive a diagonal.
+		sx, sy := x0+dx*i/n, y0+dy*i/n
+		mx, my := sx-p.x, sy-p.y
+		d := max(1, int(math.Round(ms/float64(n))))
+		if err := p.command(fmt.Sprintf("SM,%d,%d,%d", d, mx+my, mx-my)); err != nil {
+			return err
+		}
+		p.x, p.y = sx, sy
+	}
+	return nil
+}
+
+// command sends an EBB command and waits for its OK.
+func (p *Plotter) command(c string) error {
+	if _, err := io.WriteString(p.rw, c+"\r"); err != nil {
+		return err
+	}
+	reply, err := p.r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("axidraw: %s: %v", c, err)
+	}
+	if reply = strings.TrimSpace(reply); reply != "OK" {
+		return fmt.Errorf("axidraw: %s: %s", c, reply)
+	}
+	return nil
+}
+
+// Order returns the polylines reordered to shorten the travel between them,
+// starting from the point start: each next one is the nearest by either
+// end, reversed if its far end is nearer.
+func Order(lines [][][2]float64, start [2]float64) [][][2]float64 {
+	left := append([][][2]float64(nil), lines...)
+	out := make([][][2]float64, 0, len(lines))
+	pos := start
+	dist := func(a, b [2]float64) float64 { return math.Hypot(a[0]-b[0], a[1]-b[1]) }
+	for len(left) > 0 {
+		best, rev, bestD := 0, false, math.Inf(1)
+		for i, l := range left {
+			if d := dist(pos, l[0]); d < bestD {
+				best, rev, bestD = i, false, d
+			}
+			if d := dist(pos, l[len(l)-1]); d < bestD {
+				best, rev, bestD = i, true, d
+			}
+		}
+		l := left[best]
+		left[best] = left[len(left)-1]
+		left = left[:len(left)-1]
+		if rev {
+			r := make([][2]float64, len(l))
+			for i, pt := range l {
+				r[len(l)-1-i] = pt
+			}
+			l = r
+		}
+		out = append(out, l)
+		pos = l[len(l)-1]
+	}
+	return out
+}
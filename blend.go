@@ -0,0 +1,123 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// SetLinearBlending chooses how translucent colors mix with what is under
+// them, in fills, images, grids and Compose. By default they blend in
+// linear light, as light physically mixes, so overlapping translucent
+// shapes and blend modes do not darken midtones. SetLinearBlending(false)
+// blends the sRGB values directly, as most image libraries do. On a
+// LinearRGB canvas (see SetColorSpace) blending is always linear.
+func (t *Turtle) SetLinearBlending(on bool) {
+	if t.refuse("linearblend") {
+		return
+	}
+	defer t.track("linearblend", boolArg(on))()
+	t.srgbBlending = !on
+}
+
+// LinearBlending reports whether colors blend in linear light.
+func (t *Turtle) LinearBlending() bool { return !t.srgbBlending }
+
+func boolArg(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// toLinear maps 8-bit sRGB values to linear light in [0, 1], and
+// fromLinear maps linear light, in steps of 1/4095, back to sRGB.
+var toLinear, fromLinear = func() (to [256]float64, from [4096]uint8) {
+	for i := range to {
+		to[i] = decodeSRGB(float64(i) / 255)
+	}
+	for i := range from {
+		from[i] = uint8(math.Round(255 * encodeSRGB(float64(i)/4095)))
+	}
+	return
+}()
+
+func decodeSRGB(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func encodeSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// linearOf returns the linear light of an 8-bit sRGB value.
+func linearOf(v uint8) float64 { return toLinear[v] }
+
+// srgbOf returns the 8-bit sRGB value of linear light v.
+func srgbOf(v float64) uint8 {
+	return fromLinear[int(math.Round(max(0, min(1, v))*4095))]
+}
+
+// over composites the color s, with its alpha scaled by coverage cov, over
+// the premultiplied pixel d, in linear light if linear is set.
+func over(d []uint8, s color.NRGBA, cov float64, linear bool) {
+	sa := float64(s.A) / 255 * cov
+	if sa <= 0 {
+		return
+	}
+	if sa >= 1 {
+		d[0], d[1], d[2], d[3] = s.R, s.G, s.B, 255
+		return
+	}
+	da := float64(d[3]) / 255
+	oa := sa + float64(da*(1-sa)) // unfused, see drawCapsule
+	for i, sc := range [3]uint8{s.R, s.G, s.B} {
+		var dc uint8 // unpremultiplied
+		if d[3] > 0 {
+			dc = uint8(min(255, math.Round(float64(d[i])/da)))
+		}
+		if !linear {
+			o := (float64(float64(sc)*sa) + float64(float64(dc)*da*(1-sa))) / oa
+			d[i] = uint8(math.Round(o * oa))
+			continue
+		}
+		o := (float64(linearOf(sc)*sa) + float64(linearOf(dc)*da*(1-sa))) / oa
+		d[i] = uint8(math.Round(float64(srgbOf(o)) * oa))
+	}
+	d[3] = uint8(math.Round(255 * oa))
+}
+
+// drawOver composites src over r of dst, scaled by mask if not nil, pixel
+// by pixel in linear light. It is the linear-light counterpart of
+// draw.DrawMask with draw.Over.
+func drawOver(dst *image.RGBA, r image.Rectangle, src image.Image, sp image.Point, mask *image.Alpha, mp image.Point) {
+	r = r.Intersect(dst.Rect)
+	u, uniform := src.(*image.Uniform)
+	var uc color.NRGBA
+	if uniform {
+		uc = color.NRGBAModel.Convert(u.C).(color.NRGBA)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cov := 1.0
+			if mask != nil {
+				a := mask.AlphaAt(x-r.Min.X+mp.X, y-r.Min.Y+mp.Y).A
+				if a == 0 {
+					continue
+				}
+				cov = float64(a) / 255
+			}
+			c := uc
+			if !uniform {
+				c = color.NRGBAModel.Convert(src.At(x-r.Min.X+sp.X, y-r.Min.Y+sp.Y)).(color.NRGBA)
+			}
+			over(dst.Pix[dst.PixOffset(x, y):][:4], c, cov, true)
+		}
+	}
+}
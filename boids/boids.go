@@ -0,0 +1,219 @@
+// Package boids moves turtles on one canvas as a flock, using Reynolds'
+// steering behaviors: separation, alignment and cohesion, plus seek and
+// flee towards or away from a point.
+//
+//	leader := gotuga.New(600, 400, color.White)
+//	turtles := []*gotuga.Turtle{leader}
+//	for i := 1; i < 30; i++ {
+//		turtles = append(turtles, leader.Spawn())
+//	}
+//	f := boids.New(turtles, rand.New(rand.NewPCG(1, 0)))
+//	f.Bounds = &boids.Rect{MinX: -300, MinY: -200, MaxX: 300, MaxY: 200}
+//	rec := leader.Record()
+//	for i := 1; i < len(turtles); i++ {
+//		rec.Follow(turtles[i])
+//	}
+//	f.Run(200)
+//
+// Every Step moves each turtle once with SetHeading and Forward, so a turtle
+// with its pen down leaves a trail, and recorders capture the flock frame by
+// frame.
+package boids
+
+import (
+	"math"
+	"math/rand/v2"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/random"
+)
+
+// Vec is a two-dimensional vector in logical units.
+type Vec struct{ X, Y float64 }
+
+func (v Vec) add(w Vec) Vec         { return Vec{v.X + w.X, v.Y + w.Y} }
+func (v Vec) sub(w Vec) Vec         { return Vec{v.X - w.X, v.Y - w.Y} }
+func (v Vec) scale(k float64) Vec   { return Vec{v.X * k, v.Y * k} }
+func (v Vec) len() float64          { return math.Hypot(v.X, v.Y) }
+func (v Vec) withLen(n float64) Vec { return v.unit().scale(n) }
+func (v Vec) limit(n float64) Vec   { return v.unit().scale(math.Min(v.len(), n)) }
+func (v Vec) unit() Vec {
+	if l := v.len(); l > 0 {
+		return v.scale(1 / l)
+	}
+	return Vec{}
+}
+
+// Rect is a region in logical coordinates.
+type Rect struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Boid is one member of a flock.
+type Boid struct {
+	T   *gotuga.Turtle
+	Vel Vec // logical units per step
+}
+
+// Pos returns the boid's position.
+func (b *Boid) Pos() Vec {
+	x, y := b.T.Position()
+	return Vec{x, y}
+}
+
+// Flock steers a group of boids. Its fields may be changed between steps.
+type Flock struct {
+	Boids []*Boid
+
+	MaxSpeed float64 // logical units per step
+	MaxForce float64 // largest change of velocity per step
+
+	Radius           float64 // how far a boid sees its neighbors
+	SeparationRadius float64 // how close neighbors may come
+
+	Separation, Alignment, Cohesion float64 // weights of the three rules
+
+	// Steer, when set, adds a force of its own to every boid each step,
+	// e.g. f.Seek(b, x, y) to follow a target.
+	Steer func(b *Boid) Vec
+
+	// Bounds, when set, wraps boids that leave it around to the opposite
+	// side, lifting the pen so trails do not streak across the canvas.
+	Bounds *Rect
+}
+
+// New makes a flock of turtles, each starting from its current position
+// with a random heading at half the maximum speed. A nil rng uses the
+// unseeded global source.
+func New(turtles []*gotuga.Turtle, rng *rand.Rand) *Flock {
+	f := &Flock{
+		MaxSpeed:         4,
+		MaxForce:         0.1,
+		Radius:           50,
+		SeparationRadius: 20,
+		Separation:       1.5,
+		Alignment:        1,
+		Cohesion:         1,
+	}
+	for _, t := range turtles {
+		a := 2 * math.Pi * random.Float64(rng)
+		v := Vec{math.Cos(a), math.Sin(a)}.scale(f.MaxSpeed / 2)
+		f.Boids = append(f.Boids, &Boid{T: t, Vel: v})
+	}
+	return f
+}
+
+// Seek returns the force steering b straight towards (x, y) at full speed.
+func (f *Flock) Seek(b *Boid, x, y float64) Vec {
+	desired := Vec{x, y}.sub(b.Pos()).withLen(f.MaxSpeed)
+	return desired.sub(b.Vel).limit(f.MaxForce)
+}
+
+// Flee returns the force steering b straight away from (x, y) at full
+// speed.
+func (f *Flock) Flee(b *Boid, x, y float64) Vec {
+	return f.Seek(b, x, y).scale(-1)
+}
+
+// neighbors returns the other boids within r of b.
+func (f *Flock) neighbors(b *Boid, r float64) []*Boid {
+	var out []*Boid
+	p := b.Pos()
+	for _, o := range f.Boids {
+		if o != b && o.Pos().sub(p).len() < r {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// SeparationForce steers b away from neighbors closer than
+// SeparationRadius, the nearest most strongly.
+func (f *Flock) SeparationForce(b *Boid) Vec {
+	var away Vec
+	p := b.Pos()
+	near := f.neighbors(b, f.SeparationRadius)
+	for _, o := range near {
+		d := p.sub(o.Pos())
+		if l := d.len(); l > 0 {
+			away = away.add(d.scale(1 / (l * l)))
+		}
+	}
+	if len(near) == 0 || away.len() == 0 {
+		return Vec{}
+	}
+	return away.withLen(f.MaxSpeed).sub(b.Vel).limit(f.MaxForce)
+}
+
+// AlignmentForce steers b towards the average heading of its neighbors.
+func (f *Flock) AlignmentForce(b *Boid) Vec {
+	near := f.neighbors(b, f.Radius)
+	if len(near) == 0 {
+		return Vec{}
+	}
+	var sum Vec
+	for _, o := range near {
+		sum = sum.add(o.Vel)
+	}
+	return sum.withLen(f.MaxSpeed).sub(b.Vel).limit(f.MaxForce)
+}
+
+// CohesionForce steers b towards the center of its neighbors.
+func (f *Flock) CohesionForce(b *Boid) Vec {
+	near := f.neighbors(b, f.Radius)
+	if len(near) == 0 {
+		return Vec{}
+	}
+	var sum Vec
+	for _, o := range near {
+		sum = sum.add(o.Pos())
+	}
+	c := sum.scale(1 / float64(len(near)))
+	return f.Seek(b, c.X, c.Y)
+}
+
+// Step updates every boid's velocity from the forces on the flock as it
+// stands, then moves each boid's turtle one step.
+func (f *Flock) Step() {
+	vel := make([]Vec, len(f.Boids))
+	for i, b := range f.Boids {
+		acc := f.SeparationForce(b).scale(f.Separation).
+			add(f.AlignmentForce(b).scale(f.Alignment)).
+			add(f.CohesionForce(b).scale(f.Cohesion))
+		if f.Steer != nil {
+			acc = acc.add(f.Steer(b))
+		}
+		vel[i] = b.Vel.add(acc).limit(f.MaxSpeed)
+	}
+	for i, b := range f.Boids {
+		b.Vel = vel[i]
+		if speed := b.Vel.len(); speed > 0 {
+			b.T.SetHeading(math.Atan2(b.Vel.Y, b.Vel.X) * 180 / math.Pi)
+			b.T.Forward(speed)
+		}
+		f.wrap(b)
+	}
+}
+
+// Run takes n steps.
+func (f *Flock) Run(n int) {
+	for i := 0; i < n; i++ {
+		f.Step()
+	}
+}
+
+// wrap moves a boid that has left Bounds around to the opposite side.
+func (f *Flock) wrap(b *Boid) {
+	if f.Bounds == nil {
+		return
+	}
+	r := *f.Bounds
+	w, h := r.MaxX-r.MinX, r.MaxY-r.MinY
+	if w <= 0 || h <= 0 {
+		return
+	}
+	p := b.Pos()
+	x := r.MinX + math.Mod(math.Mod(p.X-r.MinX, w)+w, w)
+	y := r.MinY + math.Mod(math.Mod(p.Y-r.MinY, h)+h, h)
+	b.T.JumpTo(x, y)
+}
@@ -0,0 +1,109 @@
+package gotuga
+
+import (
+	"fmt"
+	"time"
+)
+
+// BudgetError reports a command refused, or stopped partway, because the
+// turtle spent the budget set with SetStepBudget or SetTimeBudget.
+type BudgetError struct {
+	Step     int           // index in History the command would have had
+	Command  string        // the command's name, as in History
+	Commands int           // the step budget, if that was spent
+	Time     time.Duration // the time budget, if that was spent
+}
+
+func (e *BudgetError) Error() string {
+	if e.Commands > 0 {
+		return fmt.Sprintf("gotuga: step %d: %s is past the budget of %d commands", e.Step, e.Command, e.Commands)
+	}
+	return fmt.Sprintf("gotuga: step %d: %s is past the time budget of %v", e.Step, e.Command, e.Time)
+}
+
+// SetStepBudget lets the turtle run maxCommands more top-level commands,
+// protecting a server that renders untrusted scripts from infinite loops;
+// 0 or less removes the limit. The command after those is refused: it and
+// every later one do nothing, Err reports a *BudgetError and Apply returns
+// it, unless the function set with SetBudgetCallback lets them run. Go code
+// looping over commands should stop once Err is set. Steps counted with
+// CheckBudget count towards maxCommands too.
+func (t *Turtle) SetStepBudget(maxCommands int) {
+	t.stepBudget, t.stepsFrom, t.extraSteps = max(0, maxCommands), t.steps, 0
+	t.spent = nil
+}
+
+// SetTimeBudget is like SetStepBudget but limits the wall time, from now,
+// in which the turtle may run commands; 0 or less removes the limit. Long
+// commands, such as a circle or a move bouncing off the edges many times,
+// stop partway when it runs out.
+func (t *Turtle) SetTimeBudget(d time.Duration) {
+	t.timeBudget, t.deadline = d, time.Time{}
+	if d > 0 {
+		t.deadline = time.Now().Add(d)
+	}
+	t.spent = nil
+}
+
+// SetBudgetCallback sets the function called when a command is past the
+// budget. If it returns, the command runs, and it is called again for
+// every command until the budget is raised or removed.
+func (t *Turtle) SetBudgetCallback(fn func(t *Turtle, err *BudgetError)) {
+	t.budgetCallback = fn
+}
+
+// CheckBudget counts a step of work that draws nothing, such as an
+// iteration of an interpreter's loop or a procedure call, named what,
+// against the step budget, and applies the budget to it as to a command,
+// returning the *BudgetError if it is refused. Interpreters call it so
+// that a script looping without drawing is stopped like one drawing
+// forever.
+func (t *Turtle) CheckBudget(what string) error {
+	if t.stepBudget == 0 && t.deadline.IsZero() {
+		return nil
+	}
+	if t.refuse(what) {
+		return t.spent
+	}
+	t.extraSteps++
+	return nil
+}
+
+// refuse applies the budget to the top-level command name, about to run,
+// and reports whether the command must do nothing.
+func (t *Turtle) refuse(name string) bool {
+	if t.depth > 0 || t.stepBudget == 0 && t.deadline.IsZero() {
+		return false
+	}
+	var err *BudgetError
+	switch {
+	case t.stepBudget > 0 && t.steps-t.stepsFrom+t.extraSteps >= t.stepBudget:
+		err = &BudgetError{Step: t.steps, Command: name, Commands: t.stepBudget}
+	case !t.deadline.IsZero() && time.Now().After(t.deadline):
+		err = &BudgetError{Step: t.steps, Command: name, Time: t.timeBudget}
+	default:
+		return false
+	}
+	t.setErr(err)
+	if t.budgetCallback != nil {
+		t.budgetCallback(t, err)
+		return false
+	}
+	t.spent = err
+	return true
+}
+
+// outOfTime reports whether the long command name must stop partway, the
+// time budget having run out while it ran. Later commands are refused
+// then, unless a budget callback decides on them.
+func (t *Turtle) outOfTime(name string) bool {
+	if t.spent != nil {
+		return true
+	}
+	if t.deadline.IsZero() || t.budgetCallback != nil || !time.Now().After(t.deadline) {
+		return false
+	}
+	t.spent = &BudgetError{Step: t.steps, Command: name, Time: t.timeBudget}
+	t.setErr(t.spent)
+	return true
+}
@@ -0,0 +1,32 @@
+package gotuga
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// NewWithBuffer creates a turtle like New whose W×H canvas is pix itself,
+// premultiplied RGBA with stride bytes per row, as in image.RGBA. Drawing
+// changes pix in place, so an embedding application, with a shared-memory
+// framebuffer or a game engine's pixels, can show the canvas without
+// copying it every frame; read it between commands. A nil bg leaves what
+// is in pix as it is, and clearing then makes the canvas transparent.
+// With the Expand edge behavior, growing the canvas moves it off pix.
+func NewWithBuffer(pix []byte, stride, W, H int, bg color.Color) (*Turtle, error) {
+	if W <= 0 || H <= 0 || stride < 4*W {
+		return nil, fmt.Errorf("gotuga: stride %d does not fit a %d×%d canvas", stride, W, H)
+	}
+	if need := stride*(H-1) + 4*W; len(pix) < need {
+		return nil, fmt.Errorf("gotuga: buffer of %d bytes too small for a %d×%d canvas, need %d", len(pix), W, H, need)
+	}
+	t := New(0, 0, bg)
+	t.canvas = &image.RGBA{Pix: pix, Stride: stride, Rect: image.Rect(0, 0, W, H)}
+	t.W, t.H = W, H
+	t.screen.borrowed = true
+	t.screen.clear = nil
+	if bg != nil {
+		t.fillCanvas(bg)
+	}
+	return t, nil
+}
@@ -0,0 +1,90 @@
+package gotuga
+
+import "image"
+
+// SetClipMask clips everything the turtle draws from now on, lines, fills,
+// grids and stamped images, to mask, which is in canvas pixels: nothing is
+// drawn where it is transparent or outside its bounds. Lines are drawn
+// where the mask is at least half opaque; fills and images are blended by
+// its alpha. A nil mask turns clipping off. Retained paths, as returned by
+// Paths, are not clipped. Masks are recorded without their pixels, so
+// replaying the command turns clipping off.
+func (t *Turtle) SetClipMask(mask *image.Alpha) {
+	if t.refuse("clipmask") {
+		return
+	}
+	defer t.track("clipmask")()
+	t.clip = mask
+}
+
+// ClipMask returns the turtle's clip mask, or nil if drawing is not
+// clipped.
+func (t *Turtle) ClipMask() *image.Alpha { return t.clip }
+
+// visible reports whether the clip mask lets a line through at canvas
+// pixel (x, y).
+func (t *Turtle) visible(x, y int) bool {
+	return t.clip == nil || t.clip.AlphaAt(x, y).A >= 128
+}
+
+// ClipRect clips drawing to the rectangle with corners (x, y) and
+// (x+w, y+h) in logical coordinates, replacing any clip mask, so that a
+// panel of a larger composition can be drawn without spilling over.
+func (t *Turtle) ClipRect(x, y, w, h float64) {
+	if !t.finite("cliprect", &x, &y, &w, &h) {
+		return
+	}
+	if t.refuse("cliprect") {
+		return
+	}
+	defer t.track("cliprect", x, y, w, h)()
+	t.clip = t.polygonClip([][2]float64{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}})
+}
+
+// ClipPoly clips drawing to the polygon with the given vertices in logical
+// coordinates, by the turtle's fill rule, replacing any clip mask.
+func (t *Turtle) ClipPoly(pts [][2]float64) {
+	args := make([]float64, 0, 2*len(pts))
+	for _, p := range pts {
+		args = append(args, p[0], p[1])
+	}
+	ptrs := make([]*float64, len(args))
+	for i := range args {
+		ptrs[i] = &args[i]
+	}
+	if !t.finite("clippoly", ptrs...) {
+		return
+	}
+	if t.refuse("clippoly") {
+		return
+	}
+	defer t.track("clippoly", args...)()
+	// The points as finite made them, leaving the caller's alone.
+	pts = make([][2]float64, len(pts))
+	for i := range pts {
+		pts[i] = [2]float64{args[2*i], args[2*i+1]}
+	}
+	t.clip = t.polygonClip(pts)
+}
+
+// ResetClip turns clipping off.
+func (t *Turtle) ResetClip() {
+	if t.refuse("resetclip") {
+		return
+	}
+	defer t.track("resetclip")()
+	t.clip = nil
+}
+
+// polygonClip returns a mask covering the polygon pts, in logical
+// coordinates. Fewer than three points cover nothing.
+func (t *Turtle) polygonClip(pts [][2]float64) *image.Alpha {
+	px := make([][2]float64, len(pts))
+	for i, p := range pts {
+		px[i][0], px[i][1] = t.CanvasPoint(p[0], p[1])
+	}
+	if len(px) < 3 {
+		return image.NewAlpha(t.canvas.Bounds())
+	}
+	return polygonMask(t.canvas.Bounds(), px, t.fillRule)
+}
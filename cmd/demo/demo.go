@@ -6,6 +6,7 @@ import (
 	"math"
 
 	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/plot"
 )
 
 func main() {
@@ -17,15 +18,10 @@ func main() {
 	t.SetWidth(3)
 
 	// Draw axes
-	t.PenUp()
-	t.GoTo(-480, 0)
-	t.PenDown()
-	t.Forward(960)
-	t.PenUp()
-	t.GoTo(0, -340)
-	t.SetHeading(90)
-	t.PenDown()
-	t.Forward(680)
+	plot.DrawAxes(t, plot.AxesOptions{
+		XMin: -480, XMax: 480, YMin: -340, YMax: 340,
+		Tick: 40, LabelEvery: 5, TickSize: 8,
+	})
 
 	// A square
 	t.PenUp()
@@ -0,0 +1,330 @@
+// Command gotuga renders turtle drawings without writing Go code.
+//
+// Usage:
+//
+//	gotuga render [-o out.png] [commands.json]
+//	gotuga run script [-o out.png] [--size 1024x768] [--bg #ffffff]
+//	gotuga batch [-d dir] [--size 1024x768] [--bg #ffffff] [-steps n] [-time 10s] script|dir...
+//	gotuga scene [-o out.png] [scene.json]
+//
+// render reads a JSON command stream (see gotuga.RenderJSON) from the named
+// file, or from standard input, and saves the drawing as PNG.
+//
+// run executes a script, either Logo (see package logo), Python turtle (see
+// package pyturtle) or a JSON command stream, and saves the drawing as PNG.
+// Scripts ending in .json, .jsonl or .ndjson, or starting with '{' or '[',
+// are JSON; scripts ending in .py are Python; anything else is Logo. The
+// canvas is 500×500 and white unless set by flags or a JSON header.
+//
+// batch runs many scripts like run, such as an archive of Logo assignments,
+// saving each as a PNG of the same name in the directory given by -d, or
+// next to the script. Directories stand for the scripts in them: .logo,
+// .lgo, .lg, .py and JSON files. Each script may run a million steps, loop
+// iterations as well as commands, for ten seconds, as set by -steps and
+// -time. A script that fails, or whose PNG would overwrite another
+// script's, is reported and the rest still run.
+//
+// scene reads a declarative JSON scene of shapes (see package scene) from
+// the named file, or from standard input, and saves it as PNG.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/hexcolor"
+	"github.com/Z6dev/GoTuga/logo"
+	"github.com/Z6dev/GoTuga/pyturtle"
+	"github.com/Z6dev/GoTuga/scene"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "render":
+		err = render(os.Args[2:])
+	case "run":
+		err = run(os.Args[2:])
+	case "batch":
+		err = batch(os.Args[2:])
+	case "scene":
+		err = renderScene(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gotuga:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gotuga render [-o out.png] [commands.json]")
+	fmt.Fprintln(os.Stderr, "       gotuga run script [-o out.png] [--size WxH] [--bg #rrggbb]")
+	fmt.Fprintln(os.Stderr, "       gotuga batch [-d dir] [--size WxH] [--bg #rrggbb] [-steps n] [-time d] script|dir...")
+	fmt.Fprintln(os.Stderr, "       gotuga scene [-o out.png] [scene.json]")
+	os.Exit(2)
+}
+
+func render(args []string) error {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	out := fs.String("o", "out.png", "output PNG file")
+	fs.Parse(args)
+
+	in, err := openInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	t, err := gotuga.RenderJSON(in)
+	if err != nil {
+		return err
+	}
+	return t.SavePNG(*out)
+}
+
+func renderScene(args []string) error {
+	fs := flag.NewFlagSet("scene", flag.ExitOnError)
+	out := fs.String("o", "out.png", "output PNG file")
+	fs.Parse(args)
+
+	in, err := openInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	t, err := scene.Render(in)
+	if err != nil {
+		return err
+	}
+	return t.SavePNG(*out)
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	out := fs.String("o", "out.png", "output PNG file")
+	size := fs.String("size", "", "canvas size as WIDTHxHEIGHT (default 500x500)")
+	bg := fs.String("bg", "", "background color as #rrggbb or #rrggbbaa (default white)")
+	name, err := parseWithScript(fs, args)
+	if err != nil {
+		return err
+	}
+
+	t, err := runScript(name, *size, *bg, nil)
+	if err != nil {
+		return err
+	}
+	return t.SavePNG(*out)
+}
+
+func batch(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	dir := fs.String("d", "", "output directory (default: next to each script)")
+	size := fs.String("size", "", "canvas size as WIDTHxHEIGHT (default 500x500)")
+	bg := fs.String("bg", "", "background color as #rrggbb or #rrggbbaa (default white)")
+	steps := fs.Int("steps", 1000000, "steps each script may run, 0 for no limit")
+	limit := fs.Duration("time", 10*time.Second, "time each script may run, 0 for no limit")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		usage()
+	}
+
+	var scripts []string
+	for _, arg := range fs.Args() {
+		found, err := expandScripts(arg)
+		if err != nil {
+			return err
+		}
+		scripts = append(scripts, found...)
+	}
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			return err
+		}
+	}
+	budget := func(t *gotuga.Turtle) {
+		t.SetStepBudget(*steps)
+		t.SetTimeBudget(*limit)
+	}
+	failed := 0
+	outputs := make(map[string]string) // script writing each PNG
+	for _, name := range scripts {
+		out := strings.TrimSuffix(name, filepath.Ext(name)) + ".png"
+		if *dir != "" {
+			out = filepath.Join(*dir, filepath.Base(out))
+		}
+		out = filepath.Clean(out)
+		var t *gotuga.Turtle
+		var err error
+		if first, taken := outputs[out]; taken {
+			err = fmt.Errorf("%s is already the output of %s", out, first)
+		} else {
+			outputs[out] = name
+			t, err = runScript(name, *size, *bg, budget)
+		}
+		if err == nil {
+			err = t.SavePNG(out)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Println(out)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scripts failed", failed, len(scripts))
+	}
+	return nil
+}
+
+// expandScripts returns the scripts in a directory, in name order, or the
+// named file itself.
+func expandScripts(name string) ([]string, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{name}, nil
+	}
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	var scripts []string
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".logo", ".lgo", ".lg", ".py", ".json", ".jsonl", ".ndjson":
+			if !e.IsDir() {
+				scripts = append(scripts, filepath.Join(name, e.Name()))
+			}
+		}
+	}
+	return scripts, nil
+}
+
+// runScript runs the named script, or standard input, on a new canvas with
+// the given size and background flags. Unless it is nil, setup is called
+// with the canvas before the script runs; a budget it sets stops the
+// script with an error wrapping a *gotuga.BudgetError. A panic while the
+// script runs is returned as its error, so that one bad script does not
+// stop a batch.
+func runScript(name, size, bg string, setup func(t *gotuga.Turtle)) (t *gotuga.Turtle, err error) {
+	in, err := openInput(name)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	br := bufio.NewReader(in)
+
+	defer func() {
+		if r := recover(); r != nil {
+			t, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if isJSON(name, br) && size == "" && bg == "" {
+		return gotuga.RenderJSONWith(br, setup)
+	}
+	if t, err = newCanvas(size, bg); err != nil {
+		return nil, err
+	}
+	if setup != nil {
+		setup(t)
+	}
+	if isJSON(name, br) {
+		return t, t.Exec(br)
+	}
+	src, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(name), ".py") {
+		return t, pyturtle.Run(t, string(src))
+	}
+	return t, logo.Run(t, string(src))
+}
+
+// parseWithScript parses flags given before or after the script name, which
+// it returns.
+func parseWithScript(fs *flag.FlagSet, args []string) (string, error) {
+	fs.Parse(args)
+	name := fs.Arg(0)
+	if fs.NArg() > 1 {
+		fs.Parse(fs.Args()[1:])
+		if fs.NArg() > 0 {
+			return "", fmt.Errorf("unexpected argument %q", fs.Arg(0))
+		}
+	}
+	return name, nil
+}
+
+// isJSON reports whether the script is a JSON command stream, judging by its
+// name or else by its first character.
+func isJSON(name string, br *bufio.Reader) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".jsonl", ".ndjson":
+		return true
+	case ".logo", ".lgo", ".lg", ".py":
+		return false
+	}
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return false
+		}
+		switch b[0] {
+		case ' ', '\t', '\n', '\r':
+			br.ReadByte()
+			continue
+		}
+		return b[0] == '{' || b[0] == '['
+	}
+}
+
+// The largest canvas --size may ask for, the same as a JSON header may.
+const (
+	maxSide   = 1 << 20
+	maxPixels = 1 << 28
+)
+
+// newCanvas creates a turtle with the given size and background flags.
+func newCanvas(size, bg string) (*gotuga.Turtle, error) {
+	w, h := 500, 500
+	if size != "" {
+		if _, err := fmt.Sscanf(size, "%dx%d", &w, &h); err != nil || w <= 0 || h <= 0 {
+			return nil, fmt.Errorf("invalid size %q, want WIDTHxHEIGHT", size)
+		}
+		if w > maxSide || h > maxSide || int64(w)*int64(h) > maxPixels {
+			return nil, fmt.Errorf("size %q is too large, want at most %d pixels a side and %d in all", size, maxSide, maxPixels)
+		}
+	}
+	var c color.Color = color.White
+	if bg != "" {
+		n, err := hexcolor.Parse(bg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid background: %v", err)
+		}
+		c = n
+	}
+	return gotuga.New(w, h, c), nil
+}
+
+// openInput opens the named file, or standard input for "" and "-".
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "" || name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(name)
+}
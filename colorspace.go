@@ -0,0 +1,62 @@
+package gotuga
+
+import "image"
+
+// ColorSpace is the color space of the canvas's values.
+type ColorSpace int
+
+const (
+	SRGB      ColorSpace = iota // values are sRGB, as in most image files
+	LinearRGB                   // values are linear light
+)
+
+// SetColorSpace sets the color space the canvas works in: the space in
+// which pen, fill and background colors are given, and canvas pixels are
+// stored and blended. With LinearRGB, colors are taken as linear light,
+// as renderers and shader tools use them, and converted to sRGB when the
+// canvas is saved or encoded, so the same RGB values give the same image
+// as in those tools. Image still returns the canvas as stored. The canvas
+// keeps 8 bits per channel, so LinearRGB loses some precision in dark
+// colors. The canvas is not converted when the space changes; set it
+// before drawing.
+func (t *Turtle) SetColorSpace(cs ColorSpace) {
+	if t.refuse("colorspace") {
+		return
+	}
+	defer t.track("colorspace", float64(cs))()
+	t.colorSpace = cs
+}
+
+// ColorSpace returns the canvas's color space.
+func (t *Turtle) ColorSpace() ColorSpace { return t.colorSpace }
+
+// blendLinear reports whether blending must convert sRGB values to linear
+// light. A LinearRGB canvas is linear already.
+func (t *Turtle) blendLinear() bool {
+	return !t.srgbBlending && t.colorSpace == SRGB
+}
+
+// output returns the canvas as sRGB, for saving: the canvas itself, or a
+// converted copy for a LinearRGB canvas.
+func (t *Turtle) output() *image.RGBA {
+	if t.colorSpace == SRGB {
+		return t.pixels()
+	}
+	return linearToSRGB(t.pixels())
+}
+
+// linearToSRGB returns a copy of img, which holds linear light, in sRGB.
+func linearToSRGB(img *image.RGBA) *image.RGBA {
+	out := cloneRGBA(img)
+	for i := 0; i+3 < len(out.Pix); i += 4 {
+		a := out.Pix[i+3]
+		if a == 0 {
+			continue
+		}
+		fa := float64(a) / 255
+		for j := 0; j < 3; j++ {
+			out.Pix[i+j] = uint8(float64(srgbOf(float64(out.Pix[i+j])/255/fa))*fa + 0.5)
+		}
+	}
+	return out
+}
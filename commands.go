@@ -0,0 +1,117 @@
+package gotuga
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+)
+
+// commandSpec describes how to run a Command: the minimum number of
+// arguments and the method it maps to.
+type commandSpec struct {
+	args int
+	run  func(t *Turtle, a []float64)
+}
+
+var commands = map[string]commandSpec{
+	"forward":     {1, func(t *Turtle, a []float64) { t.Forward(a[0]) }},
+	"backward":    {1, func(t *Turtle, a []float64) { t.Backward(a[0]) }},
+	"left":        {1, func(t *Turtle, a []float64) { t.Left(a[0]) }},
+	"right":       {1, func(t *Turtle, a []float64) { t.Right(a[0]) }},
+	"setheading":  {1, func(t *Turtle, a []float64) { t.SetHeading(a[0]) }},
+	"goto":        {2, func(t *Turtle, a []float64) { t.GoTo(a[0], a[1]) }},
+	"home":        {0, func(t *Turtle, a []float64) { t.Home() }},
+	"penup":       {0, func(t *Turtle, a []float64) { t.PenUp() }},
+	"pendown":     {0, func(t *Turtle, a []float64) { t.PenDown() }},
+	"color":       {0, func(t *Turtle, a []float64) { t.SetColor(argColor(a)) }},
+	"width":       {1, func(t *Turtle, a []float64) { t.SetWidth(a[0]) }},
+	"linejoin":    {1, func(t *Turtle, a []float64) { t.SetLineJoin(LineJoin(math.Round(a[0]))) }},
+	"miterlimit":  {1, func(t *Turtle, a []float64) { t.SetMiterLimit(a[0]) }},
+	"clear":       {0, func(t *Turtle, a []float64) { t.Clear() }},
+	"reset":       {0, func(t *Turtle, a []float64) { t.Reset() }},
+	"rect":        {2, func(t *Turtle, a []float64) { t.Rect(a[0], a[1]) }},
+	"polygon":     {2, func(t *Turtle, a []float64) { t.Polygon(int(math.Round(a[0])), a[1]) }},
+	"circle":      {1, func(t *Turtle, a []float64) { t.Circle(a[0]) }},
+	"dot":         {1, func(t *Turtle, a []float64) { t.Dot(a[0], argColor(a[1:])) }},
+	"beginfill":   {0, func(t *Turtle, a []float64) { t.BeginFill() }},
+	"fillcolor":   {0, func(t *Turtle, a []float64) { t.FillColor(argColor(a)) }},
+	"endfill":     {0, func(t *Turtle, a []float64) { t.EndFill() }},
+	"fillrule":    {1, func(t *Turtle, a []float64) { t.SetFillRule(FillRule(math.Round(a[0]))) }},
+	"beginpoly":   {0, func(t *Turtle, a []float64) { t.BeginPoly() }},
+	"endpoly":     {0, func(t *Turtle, a []float64) { t.EndPoly() }},
+	"isometric":   {1, func(t *Turtle, a []float64) { t.SetIsometric(a[0] != 0) }},
+	"up":          {1, func(t *Turtle, a []float64) { t.Up(a[0]) }},
+	"down":        {1, func(t *Turtle, a []float64) { t.Down(a[0]) }},
+	"bgimage":     {0, func(t *Turtle, a []float64) { t.SetBackgroundImage(nil) }}, // images are not recorded
+	"stampimage":  {0, func(t *Turtle, a []float64) {}},
+	"clipmask":    {0, func(t *Turtle, a []float64) { t.SetClipMask(nil) }}, // masks are not recorded
+	"cliprect":    {4, func(t *Turtle, a []float64) { t.ClipRect(a[0], a[1], a[2], a[3]) }},
+	"clippoly":    {0, func(t *Turtle, a []float64) { t.ClipPoly(pairs(a)) }},
+	"resetclip":   {0, func(t *Turtle, a []float64) { t.ResetClip() }},
+	"compose":     {0, func(t *Turtle, a []float64) {}}, // canvases are not recorded
+	"quality":     {1, func(t *Turtle, a []float64) { t.SetQuality(Quality(math.Round(a[0]))) }},
+	"determinism": {1, func(t *Turtle, a []float64) { t.SetDeterministic(a[0] != 0) }},
+	"linearblend": {1, func(t *Turtle, a []float64) { t.SetLinearBlending(a[0] != 0) }},
+	"colorspace":  {1, func(t *Turtle, a []float64) { t.SetColorSpace(ColorSpace(math.Round(a[0]))) }},
+	"nonfinite":   {1, func(t *Turtle, a []float64) { t.SetNonFiniteBehavior(NonFiniteBehavior(math.Round(a[0]))) }},
+	"edges":       {1, func(t *Turtle, a []float64) { t.SetEdgeBehavior(EdgeBehavior(math.Round(a[0]))) }},
+	"realtime":    {1, func(t *Turtle, a []float64) { t.SetRealTime(int(a[0])) }},
+	"speed":       {2, func(t *Turtle, a []float64) { t.SetSpeed(a[0], a[1]) }},
+	"delay":       {1, func(t *Turtle, a []float64) { t.Delay(time.Duration(a[0] * float64(time.Second))) }},
+	"grid": {10, func(t *Turtle, a []float64) {
+		t.DrawGrid(a[0], int(math.Round(a[1])), argColor(a[2:6]), argColor(a[6:10]))
+	}},
+}
+
+// argColor converts four 0–255 components into a color; fewer than four
+// arguments give nil, which color commands ignore.
+func argColor(a []float64) color.Color {
+	if len(a) < 4 {
+		return nil
+	}
+	c := func(v float64) uint8 { return uint8(math.Max(0, math.Min(255, math.Round(v)))) }
+	return color.NRGBA{c(a[0]), c(a[1]), c(a[2]), c(a[3])}
+}
+
+// pairs groups arguments into points, dropping an odd one out.
+func pairs(a []float64) [][2]float64 {
+	pts := make([][2]float64, len(a)/2)
+	for i := range pts {
+		pts[i] = [2]float64{a[2*i], a[2*i+1]}
+	}
+	return pts
+}
+
+// Apply runs a command as reported by Observe, so recorded commands can be
+// replayed on another turtle. It returns the *BudgetError if the budget
+// refused the command or stopped it partway.
+func (t *Turtle) Apply(c Command) error {
+	spec, ok := commands[c.Name]
+	if !ok {
+		return fmt.Errorf("gotuga: unknown command %q", c.Name)
+	}
+	if len(c.Args) < spec.args {
+		return fmt.Errorf("gotuga: %s needs %d arguments, got %d", c.Name, spec.args, len(c.Args))
+	}
+	spec.run(t, c.Args)
+	if t.spent != nil {
+		return t.spent
+	}
+	return nil
+}
+
+// History returns every top-level command the turtle has run, in order,
+// while recording its history (see SetRecordHistory).
+func (t *Turtle) History() []Command { return t.history }
+
+// SetRecordHistory sets whether the turtle keeps the commands it runs, for
+// History, Session and HistoryHash. It is off by default, as a drawing that
+// runs for long keeps ever more of them; turn it on before the commands to
+// be kept. Turning it off forgets the commands kept so far.
+func (t *Turtle) SetRecordHistory(on bool) {
+	t.recordHistory = on
+	if !on {
+		t.history = nil
+	}
+}
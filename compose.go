@@ -0,0 +1,112 @@
+package gotuga
+
+import (
+	"image"
+	"math"
+)
+
+// BlendMode says how Compose combines colors.
+type BlendMode int
+
+const (
+	BlendOver       BlendMode = iota // paint over, as drawing does
+	BlendMultiply                    // darken by multiplying colors
+	BlendScreen                      // lighten by multiplying inverses
+	BlendAdd                         // add colors, clamping at white
+	BlendDarken                      // keep the darker of each channel
+	BlendLighten                     // keep the lighter of each channel
+	BlendDifference                  // absolute difference of the colors
+)
+
+// Compose draws other's canvas over the turtle's, combining colors with op,
+// with other's top-left corner at offset in canvas pixels. Transparent parts
+// of other leave the canvas as it is, and the turtle's clip mask applies.
+// Scenes drawn separately, say one per goroutine, can be merged this way;
+// other's canvas is copied between its commands, so it may still be drawing.
+// Colors blend in linear light unless SetLinearBlending(false) was called.
+// Retained paths are not merged. Like images, other is recorded without its
+// pixels, so replaying the command does nothing.
+func (t *Turtle) Compose(other *Turtle, op BlendMode, offset image.Point) {
+	var src *image.RGBA
+	if other.screen == t.screen {
+		src = cloneRGBA(other.pixels()) // other may be t itself
+	} else {
+		src, _ = other.copyCanvas()
+	}
+	if t.refuse("compose") {
+		return
+	}
+	defer t.track("compose", float64(op), float64(offset.X), float64(offset.Y))()
+
+	t.endStroke()
+	dst := t.pixels()
+	r := src.Bounds().Add(offset).Intersect(dst.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			s := src.Pix[src.PixOffset(x-offset.X, y-offset.Y):][:4]
+			d := dst.Pix[dst.PixOffset(x, y):][:4]
+			as := float64(s[3]) / 255
+			if t.clip != nil {
+				as *= float64(t.clip.AlphaAt(x, y).A) / 255
+			}
+			if as == 0 {
+				continue
+			}
+			blendPixel(d, s, as, op, t.blendLinear())
+			t.painted++
+		}
+	}
+}
+
+// blendPixel combines the premultiplied source pixel s, whose alpha has
+// been scaled to as, into d using the separable blend formula
+// co = cs·(1−ab) + cb·(1−as) + as·ab·B(Cs, Cb), in linear light if linear
+// is set.
+func blendPixel(d, s []uint8, as float64, op BlendMode, linear bool) {
+	sa := math.Max(float64(s[3])/255, 1.0/255)
+	ab := float64(d[3]) / 255
+	ao := as + ab - as*ab
+	if op == BlendAdd {
+		ao = math.Min(1, as+ab)
+	}
+	for i := 0; i < 3; i++ {
+		// Unpremultiplied colors, for the blend function.
+		uCs, uCb := math.Min(1, float64(s[i])/255/sa), 0.0
+		if ab > 0 {
+			uCb = math.Min(1, float64(d[i])/255/ab)
+		}
+		if linear {
+			uCs, uCb = decodeSRGB(uCs), decodeSRGB(uCb)
+		}
+		cs, cb := uCs*as, uCb*ab
+		var co float64
+		if op == BlendAdd {
+			co = cs + cb
+		} else {
+			co = cs*(1-ab) + cb*(1-as) + as*ab*blend(op, uCs, uCb)
+		}
+		co = math.Min(ao, math.Max(0, co))
+		if linear && ao > 0 {
+			co = encodeSRGB(co/ao) * ao
+		}
+		d[i] = uint8(math.Round(255 * co))
+	}
+	d[3] = uint8(math.Round(255 * ao))
+}
+
+// blend returns B(Cs, Cb) for op, on unpremultiplied channels.
+func blend(op BlendMode, cs, cb float64) float64 {
+	switch op {
+	case BlendMultiply:
+		return cs * cb
+	case BlendScreen:
+		return cs + cb - cs*cb
+	case BlendDarken:
+		return math.Min(cs, cb)
+	case BlendLighten:
+		return math.Max(cs, cb)
+	case BlendDifference:
+		return math.Abs(cs - cb)
+	}
+	return cs
+}
@@ -0,0 +1,81 @@
+package gotuga
+
+import "math"
+
+// PlotParametric traces the curve (fx(s), fy(s)) for s from s0 to s1, using
+// samples straight segments. Curve coordinates are offsets from the
+// turtle's position, to which it returns afterwards; its heading is kept.
+// The pen is lifted over points where fx or fy is not finite.
+//
+// The curve is recorded as the GoTo commands that trace it.
+func (t *Turtle) PlotParametric(fx, fy func(s float64) float64, s0, s1 float64, samples int) {
+	if samples < 1 {
+		samples = 1
+	}
+	ox, oy := t.Position()
+	down := t.IsDown()
+	lifted := true
+	for i := 0; i <= samples; i++ {
+		s := s0 + (s1-s0)*float64(i)/float64(samples)
+		x, y := fx(s), fy(s)
+		if !finite(x) || !finite(y) {
+			lifted = true
+			continue
+		}
+		if lifted {
+			t.PenUp()
+			t.GoTo(ox+x, oy+y)
+			if down {
+				t.PenDown()
+			}
+			lifted = false
+			continue
+		}
+		t.GoTo(ox+x, oy+y)
+	}
+	t.PenUp()
+	t.GoTo(ox, oy)
+	if down {
+		t.PenDown()
+	}
+}
+
+// Rose traces the rose curve r = radius·cos(n/d·θ) around the turtle's
+// position, over the full period of the curve.
+func (t *Turtle) Rose(n, d int, radius float64) {
+	if d == 0 {
+		return
+	}
+	g := gcd(n, d)
+	n, d = n/g, d/g
+	period := 2 * math.Pi * float64(d)
+	if n%2 != 0 && d%2 != 0 {
+		period /= 2
+	}
+	k := float64(n) / float64(d)
+	// Aim for segments of about 3 units, as Circle does.
+	length := period * math.Abs(radius) * math.Max(1, math.Abs(k))
+	samples := int(math.Max(64, length/3))
+	t.PlotParametric(
+		func(th float64) float64 { return radius * math.Cos(k*th) * math.Cos(th) },
+		func(th float64) float64 { return radius * math.Cos(k*th) * math.Sin(th) },
+		0, period, samples)
+}
+
+func finite(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) }
+
+func gcd(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == 0 {
+		return 1
+	}
+	return a
+}
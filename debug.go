@@ -0,0 +1,63 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Colors of the debug overlay.
+var (
+	debugLine = color.NRGBA{0xff, 0x00, 0xff, 255}
+	debugFill = color.NRGBA{0x00, 0xb4, 0xff, 255}
+	debugTick = color.NRGBA{0xff, 0x8c, 0x00, 255}
+)
+
+// DebugOverlay returns a transparent layer, the size of the canvas, with
+// the skeleton of every retained path, as Paths has them: one-pixel
+// lines through the points, in magenta for lines and blue for the outlines
+// of fills, closed, with a dot at each vertex, a bigger one at the first,
+// and an orange tick from each vertex along the way the path heads next.
+// The turtle is shown the same way. Draw it over Image to see why a fill
+// or a join looks wrong.
+func (t *Turtle) DebugOverlay() *image.RGBA {
+	o := newOverlay(t)
+	for _, p := range t.Paths() {
+		c, pts := debugLine, p.Points
+		if p.Fill != nil {
+			c = debugFill
+			if len(pts) > 1 && pts[0] != pts[len(pts)-1] {
+				pts = append(pts, pts[0])
+			}
+		}
+		for i := 1; i < len(pts); i++ {
+			o.line(pts[i-1][0], pts[i-1][1], pts[i][0], pts[i][1], 1, c)
+		}
+		for i, v := range pts {
+			if i+1 < len(pts) {
+				o.tick(v[0], v[1], pts[i+1][0], pts[i+1][1])
+			}
+			r := 1.5
+			if i == 0 {
+				r = 3
+			}
+			o.dot(v[0], v[1], r, c)
+		}
+	}
+	x, y := t.project(t.x, t.y, t.z)
+	hx, hy := t.project(t.x+math.Cos(t.headingDeg*math.Pi/180), t.y+math.Sin(t.headingDeg*math.Pi/180), t.z)
+	o.tick(x, y, hx, hy)
+	o.dot(x, y, 3, debugTick)
+	return o.image()
+}
+
+// tick draws a short line from (x0, y0) towards (x1, y1).
+func (o *overlay) tick(x0, y0, x1, y1 float64) {
+	const length = 8 // pixels
+	dx, dy := unit(x1-x0, y1-y0)
+	if dx == 0 && dy == 0 {
+		return
+	}
+	l := length / o.t.scale
+	o.line(x0, y0, x0+dx*l, y0+dy*l, 1.5, debugTick)
+}
@@ -0,0 +1,79 @@
+package gotuga
+
+import "math"
+
+// SetDeterministic makes movement give bit-identical positions on every
+// architecture, for golden-image tests and rendering one drawing across
+// machines. Headings are turned into directions with sinCosDeg rather
+// than math.Sin and math.Cos, which some architectures compute in
+// assembly, and no multiply-add is fused. Rasterization and blending avoid
+// fused multiply-adds in any mode, so the same positions always give the
+// same pixels.
+func (t *Turtle) SetDeterministic(on bool) {
+	if t.refuse("determinism") {
+		return
+	}
+	defer t.track("determinism", boolArg(on))()
+	t.strictMath = on
+}
+
+// Deterministic reports whether movement is deterministic.
+func (t *Turtle) Deterministic() bool { return t.strictMath }
+
+// direction returns the cosine and sine of the heading.
+func (t *Turtle) direction() (cos, sin float64) {
+	if t.strictMath {
+		sin, cos = sinCosDeg(t.headingDeg)
+		return cos, sin
+	}
+	rad := t.headingDeg * math.Pi / 180
+	return math.Cos(rad), math.Sin(rad)
+}
+
+// step returns the point d along the heading from (x, y).
+func (t *Turtle) step(x, y, d float64) (float64, float64) {
+	cos, sin := t.direction()
+	// The conversions keep the compiler from fusing the multiply-adds,
+	// which it does on some architectures only.
+	return x + float64(d*cos), y + float64(d*sin)
+}
+
+// sinCosDeg returns the sine and cosine of deg degrees using nothing but
+// IEEE 754 arithmetic, which rounds the same way everywhere. Multiples of
+// 90° are exact.
+func sinCosDeg(deg float64) (sin, cos float64) {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	// Reduce to [0°, 45°] by quadrant and, above 45°, the complement.
+	q := int(deg / 90)
+	r := deg - 90*float64(q)
+	swap := r > 45
+	if swap {
+		r = 90 - r
+	}
+	x := r * (math.Pi / 180)
+	x2 := float64(x * x)
+	// Taylor series, accurate to an ulp or so up to π/4.
+	s, c := 1.0/355687428096000, -1.0/6402373705728000
+	for _, k := range [...]float64{-1.0 / 1307674368000, 1.0 / 6227020800, -1.0 / 39916800, 1.0 / 362880, -1.0 / 5040, 1.0 / 120, -1.0 / 6, 1} {
+		s = float64(s*x2) + k
+	}
+	for _, k := range [...]float64{1.0 / 20922789888000, -1.0 / 87178291200, 1.0 / 479001600, -1.0 / 3628800, 1.0 / 40320, -1.0 / 720, 1.0 / 24, -1.0 / 2, 1} {
+		c = float64(c*x2) + k
+	}
+	s *= x
+	if swap {
+		s, c = c, s
+	}
+	switch q % 4 {
+	case 1:
+		s, c = c, -s
+	case 2:
+		s, c = -s, -c
+	case 3:
+		s, c = -c, s
+	}
+	return s, c
+}
@@ -0,0 +1,69 @@
+package gotuga
+
+import (
+	"hash/maphash"
+	"image"
+	"os"
+)
+
+// savedPNG is what SavePNGIfDirty last wrote to a file.
+type savedPNG struct {
+	version uint64 // screen version
+	sum     uint64 // see canvasSum
+}
+
+var canvasSeed = maphash.MakeSeed()
+
+// SavePNGIfDirty writes the canvas to a PNG file like SavePNG, unless the
+// file was last written by SavePNGIfDirty and the canvas still looks the
+// same, and reports whether it wrote. Animation loops and watch modes can
+// call it every frame without re-encoding frames that did not change. A
+// file that has gone missing is written again.
+func (t *Turtle) SavePNGIfDirty(filename string) (bool, error) {
+	version := t.canvasVersion()
+	last, ok := t.savedPNGs[filename]
+	if ok {
+		if _, err := os.Stat(filename); err != nil {
+			ok = false
+		}
+	}
+	if ok && last.version == version {
+		return false, nil
+	}
+	// Commands that leave the pixels alone, like turns, still count as
+	// changes to the version.
+	sum := t.canvasSum()
+	if ok && last.sum == sum {
+		t.savedPNGs[filename] = savedPNG{version, sum}
+		return false, nil
+	}
+	if err := t.SavePNG(filename); err != nil {
+		return false, err
+	}
+	if t.savedPNGs == nil {
+		t.savedPNGs = make(map[string]savedPNG)
+	}
+	t.savedPNGs[filename] = savedPNG{version, sum}
+	return true, nil
+}
+
+// canvasSum hashes what saving the canvas depends on: its pixels, size
+// and color space.
+func (t *Turtle) canvasSum() uint64 {
+	var h maphash.Hash
+	h.SetSeed(canvasSeed)
+	img := t.pixels()
+	writeRect(&h, img.Rect)
+	h.WriteByte(byte(t.colorSpace))
+	h.Write(img.Pix)
+	return h.Sum64()
+}
+
+// writeRect adds the corners of r to h.
+func writeRect(h *maphash.Hash, r image.Rectangle) {
+	for _, v := range [...]int{r.Min.X, r.Min.Y, r.Max.X, r.Max.Y} {
+		for i := 0; i < 64; i += 8 {
+			h.WriteByte(byte(v >> i))
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package gotuga
+
+import (
+	"image/color"
+	"math"
+)
+
+// Dot draws a filled circle of diameter size, in logical units, centered
+// on the turtle, whether or not the pen is down. A nil c uses the pen
+// color, and a size of 0 or less the pen width plus 4 or twice it,
+// whichever is larger, as Python's turtle.dot does. The turtle does not
+// move.
+func (t *Turtle) Dot(size float64, c color.Color) {
+	if !t.finite("dot", &size) {
+		return
+	}
+	if t.refuse("dot") {
+		return
+	}
+	defer t.track("dot", append([]float64{size}, colorArgs(c)...)...)()
+	if c == nil {
+		c = t.penColor
+	}
+	if size <= 0 {
+		size = math.Max(t.penWidth+4, 2*t.penWidth)
+	}
+	t.endStroke()
+	x, y := t.project(t.x, t.y, t.z)
+	px, py := t.CanvasPoint(x, y)
+	t.drawDisc(px, py, size/2*t.scale, color.NRGBAModel.Convert(c).(color.NRGBA))
+	t.endStroke()
+
+	r := size / 2
+	n := int(math.Max(12, math.Min(maxCircleSegments, 2*math.Pi*r/3)))
+	pts := make([][2]float64, n+1)
+	for i := range pts {
+		a := 2 * math.Pi * float64(i%n) / float64(n)
+		pts[i] = [2]float64{x + r*math.Cos(a), y + r*math.Sin(a)}
+	}
+	t.retainShape(&Path{Points: pts, Fill: c})
+}
+
+// drawDisc covers the pixels whose centers are within r of (cx, cy), in
+// unrounded pixel coordinates, one horizontal span per row. Each row's
+// span ends are found by stepping from the previous row's, as in the
+// midpoint circle algorithm, so no square roots are taken to find them. At Best
+// quality the disc reaches half a pixel further, where pixels are covered
+// in part.
+func (t *Turtle) drawDisc(cx, cy, r float64, col color.NRGBA) {
+	if r <= 0 {
+		return
+	}
+	aa := t.quality == Best
+	e := r
+	if aa {
+		e += 0.5
+	}
+	y0 := max(pixelCeil(cy-e-0.5), 0)
+	y1 := min(pixelFloor(cy+e-0.5), t.H-1)
+	r2 := e * e
+	// inside reports whether the center of pixel x on a row dy from cy
+	// is in the disc.
+	inside := func(x int, dy2 float64) bool {
+		dx := float64(x) + 0.5 - cx
+		return float64(dx*dx)+dy2 <= r2 // unfused, see drawCapsule
+	}
+	// Spans are kept to the canvas, so huge discs cost no more than the
+	// canvas.
+	mid := clamp(pixelFloor(cx), 0, t.W-1)
+	xl, xr := mid, mid
+	for y := y0; y <= y1; y++ {
+		dy := float64(y) + 0.5 - cy
+		dy2 := dy * dy
+		// The span widens down to the middle row and narrows below it.
+		for xl > 0 && inside(xl-1, dy2) {
+			xl--
+		}
+		for xl <= xr && !inside(xl, dy2) {
+			xl++
+		}
+		for xr < t.W-1 && inside(xr+1, dy2) {
+			xr++
+		}
+		for xr >= xl && !inside(xr, dy2) {
+			xr--
+		}
+		if xl > xr {
+			// A row grazing the disc between pixel centers.
+			xl, xr = mid, mid
+			continue
+		}
+		if !aa && t.solid(col) {
+			fillSpan(t.pixels(), xl, xr+1, y, color.RGBA{col.R, col.G, col.B, 255})
+			t.painted += xr + 1 - xl
+			continue
+		}
+		for x := xl; x <= xr; x++ {
+			if !t.visible(x, y) {
+				continue
+			}
+			if aa {
+				dx := float64(x) + 0.5 - cx
+				t.paintCoverage(x, y, col, coverage(r-math.Sqrt(float64(dx*dx)+dy2)))
+			} else {
+				t.paint(x, y, col)
+			}
+		}
+	}
+}
+
+// drawRing draws a one-pixel circle of radius r around (cx, cy), in
+// unrounded pixel coordinates, with the midpoint circle algorithm.
+func (t *Turtle) drawRing(cx, cy, r float64, col color.NRGBA) {
+	ox, oy := pixelFloor(cx), pixelFloor(cy)
+	ri := pixelRound(r)
+	plot := func(x, y int) {
+		if x >= 0 && y >= 0 && x < t.W && y < t.H && t.visible(x, y) {
+			t.paint(x, y, col)
+		}
+	}
+	x, y, d := ri, 0, 1-ri
+	for x >= y {
+		plot(ox+x, oy+y)
+		plot(ox+y, oy+x)
+		plot(ox-y, oy+x)
+		plot(ox-x, oy+y)
+		plot(ox-x, oy-y)
+		plot(ox-y, oy-x)
+		plot(ox+y, oy-x)
+		plot(ox+x, oy-y)
+		y++
+		if d < 0 {
+			d += 2*y + 1
+		} else {
+			x--
+			d += 2*(y-x) + 1
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package gotuga
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// EdgeBehavior selects what happens when the turtle would move past the
+// edge of the canvas. Bounce and Wrap change where Forward and Backward
+// take the turtle; Error, Expand and Callback apply to every move.
+type EdgeBehavior int
+
+const (
+	// Clip moves the turtle past the edge; whatever it draws off the
+	// canvas is lost.
+	Clip EdgeBehavior = iota
+	// Bounce reflects the heading off the edge like a billiard ball, so the
+	// turtle stays on the canvas for the whole distance.
+	Bounce
+	// Wrap treats the canvas as a torus: the turtle leaving one edge
+	// re-enters from the opposite one, drawing the rest of the line there.
+	Wrap
+	// Error refuses moves that end off the canvas: the turtle stays where
+	// it is and Err reports an *OutOfBoundsError.
+	Error
+	// Expand grows the canvas, evenly on opposite sides so the origin stays
+	// in the middle, until the move fits. Turtles sharing the canvas see the
+	// larger one.
+	Expand
+	// Callback calls the function set with SetEdgeCallback before a move
+	// that ends off the canvas, then moves as Clip does.
+	Callback
+)
+
+// OutOfBoundsError reports a move refused by the Error edge behavior.
+type OutOfBoundsError struct {
+	Step int     // index in History of the command that made the move
+	X, Y float64 // where the move would have ended
+}
+
+func (e *OutOfBoundsError) Error() string {
+	return fmt.Sprintf("gotuga: step %d moves off the canvas to (%g, %g)", e.Step, e.X, e.Y)
+}
+
+// SetEdgeBehavior sets what happens at the canvas edges.
+func (t *Turtle) SetEdgeBehavior(b EdgeBehavior) {
+	if t.refuse("edges") {
+		return
+	}
+	defer t.track("edges", float64(b))()
+	t.edges = b
+}
+
+// Edges returns the current edge behavior.
+func (t *Turtle) Edges() EdgeBehavior { return t.edges }
+
+// SetEdgeCallback sets the function the Callback edge behavior calls with
+// the turtle and the off-canvas point it is about to move to. The function
+// may use t, but not other turtles on the same canvas.
+func (t *Turtle) SetEdgeCallback(fn func(t *Turtle, x, y float64)) {
+	t.edgeCallback = fn
+}
+
+// OffCanvasMove describes a move that ends off the canvas, in logical
+// coordinates, projected if the view is isometric.
+type OffCanvasMove struct {
+	Step           int     // index in History of the command making the move
+	X0, Y0, X1, Y1 float64 // where the move starts and ends
+
+	// The part of the move on the canvas, from where it enters, or starts,
+	// to where it leaves; OnCanvas is false if none of it is.
+	InX0, InY0, InX1, InY1 float64
+	OnCanvas               bool
+}
+
+// SetOffCanvasCallback sets a function to call after every move that ends
+// off the canvas, whatever the edge behavior, so teaching tools can tell a
+// student that the turtle walked off the page at step 42 rather than leave
+// them wondering why a corner is empty. Moves refused by Error are
+// included; those Bounce, Wrap or Expand keep on the canvas are not. The
+// function may use t, but not other turtles on the same canvas.
+func (t *Turtle) SetOffCanvasCallback(fn func(t *Turtle, m OffCanvasMove)) {
+	t.offCanvasCallback = fn
+}
+
+// reportOffCanvas calls the off-canvas callback for the move from (ax, ay)
+// to (bx, by) if it ends off the canvas.
+func (t *Turtle) reportOffCanvas(ax, ay, bx, by float64) {
+	if t.offCanvasCallback == nil || t.onCanvas(bx, by) {
+		return
+	}
+	m := OffCanvasMove{Step: t.steps, X0: ax, Y0: ay, X1: bx, Y1: by}
+	minX, minY, maxX, maxY := t.bounds()
+	m.InX0, m.InY0, m.InX1, m.InY1, m.OnCanvas = clipSegment(ax, ay, bx, by, minX, minY, maxX, maxY)
+	t.offCanvasCallback(t, m)
+}
+
+// Err returns the first error recorded since the turtle was created or last
+// reset, or since ClearErr.
+func (t *Turtle) Err() error { return t.err }
+
+// ClearErr forgets the recorded error.
+func (t *Turtle) ClearErr() { t.err = nil }
+
+// setErr records err unless an earlier error is already recorded.
+func (t *Turtle) setErr(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// allowMove applies the edge behavior to a move ending at (x, y), reporting
+// whether the move may go ahead.
+func (t *Turtle) allowMove(x, y float64) bool {
+	if t.edges < Error || t.onCanvas(x, y) {
+		return true
+	}
+	switch t.edges {
+	case Error:
+		t.setErr(&OutOfBoundsError{Step: t.steps, X: x, Y: y})
+		return false
+	case Expand:
+		t.expandTo(x, y)
+	case Callback:
+		if t.edgeCallback != nil {
+			t.edgeCallback(t, x, y)
+		}
+	}
+	return true
+}
+
+// onCanvas reports whether (x, y) is within the canvas.
+func (t *Turtle) onCanvas(x, y float64) bool {
+	const eps = 1e-9
+	minX, minY, maxX, maxY := t.bounds()
+	return x >= minX-eps && x <= maxX+eps && y >= minY-eps && y <= maxY+eps
+}
+
+// Limits on the canvas Expand may grow, beyond which the move goes ahead
+// as with Clip and Err reports why, and on canvases made from files. A
+// canvas of maxCanvasPixels takes 1 GiB.
+const (
+	maxCanvasSide   = 1 << 20
+	maxCanvasPixels = 1 << 28
+)
+
+// checkCanvasSize returns an error unless a w×h canvas is within the
+// limits.
+func checkCanvasSize(w, h int) error {
+	if w <= 0 || h <= 0 || w > maxCanvasSide || h > maxCanvasSide || int64(w)*int64(h) > maxCanvasPixels {
+		return fmt.Errorf("gotuga: invalid canvas size %d×%d, want at most %d pixels a side and %d in all", w, h, maxCanvasSide, maxCanvasPixels)
+	}
+	return nil
+}
+
+// expandTo grows the shared canvas so that (x, y), with room for the pen,
+// is on it. Each growing side gets at least half its size again, so a
+// turtle walking off the canvas does not reallocate it at every step. The
+// new margins are painted as Clear would paint them, and the clip masks
+// and strokes of the turtles sharing the canvas move with its pixels.
+func (t *Turtle) expandTo(x, y float64) {
+	grow := func(size int, v float64) int {
+		need := math.Abs(v)*t.scale + t.penWidth*t.scale - float64(size)/2
+		if need <= 0 {
+			return 0
+		}
+		return pixelCeil(math.Max(need, float64(size)/4))
+	}
+	dx, dy := grow(t.W, x), grow(t.H, y)
+	if dx == 0 && dy == 0 {
+		return
+	}
+	w, h := t.W+2*dx, t.H+2*dy
+	if w > maxCanvasSide || h > maxCanvasSide || int64(w)*int64(h) > maxCanvasPixels {
+		t.setErr(fmt.Errorf("gotuga: cannot expand the canvas to %d×%d pixels for (%g, %g)", w, h, x, y))
+		return
+	}
+	old := t.pixels()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	fillRect(img, img.Rect, color.RGBAModel.Convert(t.bg).(color.RGBA))
+	if t.bgImage != nil {
+		drawScaled(img, 0, 0, float64(w), float64(h), t.bgImage, nil, t.blendLinear())
+	}
+	off := image.Pt(dx, dy)
+	draw.Draw(img, old.Rect.Add(off), old, image.Point{}, draw.Src)
+	for _, o := range t.screen.turtles {
+		if o.canvas != old {
+			continue
+		}
+		o.canvas, o.W, o.H = img, w, h
+		if o.clip != nil {
+			// The same pixels, moved with the drawing; the margins are
+			// outside it and so clipped.
+			c := *o.clip
+			c.Rect = c.Rect.Add(off)
+			o.clip = &c
+		}
+		if o.stroke != nil {
+			stroke := image.NewAlpha(img.Rect)
+			draw.Draw(stroke, o.strokeRect.Add(off), o.stroke, o.strokeRect.Min, draw.Src)
+			o.stroke, o.strokeRect = stroke, o.strokeRect.Add(off)
+		}
+	}
+	// The canvas is no longer the caller's buffer.
+	t.screen.borrowed = false
+}
+
+// bounds returns the logical coordinates of the canvas edges.
+func (t *Turtle) bounds() (minX, minY, maxX, maxY float64) {
+	halfW := float64(t.W) / 2 / t.scale
+	halfH := float64(t.H) / 2 / t.scale
+	return -halfW, -halfH, halfW, halfH
+}
+
+// edgeMove moves d along the heading, bouncing off or wrapping around the
+// canvas edges. A bouncing turtle that starts off the canvas just moves; a
+// wrapping one is first wrapped onto it.
+func (t *Turtle) edgeMove(d float64) {
+	const eps = 1e-9
+	minX, minY, maxX, maxY := t.bounds()
+	outside := t.x < minX-eps || t.x > maxX+eps || t.y < minY-eps || t.y > maxY+eps
+	if math.IsInf(d, 0) || math.IsNaN(d) || outside && t.edges == Bounce {
+		t.moveTo(t.step(t.x, t.y, d))
+		return
+	}
+	if outside {
+		t.x = minX + wrapMod(t.x-minX, maxX-minX)
+		t.y = minY + wrapMod(t.y-minY, maxY-minY)
+	}
+	sign := 1.0
+	if d < 0 {
+		sign, d = -1, -d
+	}
+	d = t.skipCircuits(d, sign)
+	for d > 0 && !t.outOfTime("forward") {
+		dx, dy := t.direction()
+		dx, dy = sign*dx, sign*dy
+		// Distance to the wall ahead on each axis.
+		sx, sy := math.Inf(1), math.Inf(1)
+		if dx > eps {
+			sx = (maxX - t.x) / dx
+		} else if dx < -eps {
+			sx = (minX - t.x) / dx
+		}
+		if dy > eps {
+			sy = (maxY - t.y) / dy
+		} else if dy < -eps {
+			sy = (minY - t.y) / dy
+		}
+		s := math.Max(0, math.Min(sx, sy))
+		if s >= d {
+			t.moveTo(t.x+dx*d, t.y+dy*d)
+			return
+		}
+		x := math.Max(minX, math.Min(maxX, t.x+dx*s))
+		y := math.Max(minY, math.Min(maxY, t.y+dy*s))
+		t.moveTo(x, y)
+		d -= s
+		hitX, hitY := sx <= s+eps, sy <= s+eps
+		switch t.edges {
+		case Bounce:
+			h := t.headingDeg
+			if hitX {
+				h = 180 - h
+			}
+			if hitY {
+				h = -h
+			}
+			t.headingDeg = wrapMod(h, 360)
+		case Wrap:
+			// Re-enter from the opposite edge without drawing.
+			if hitX {
+				t.x = minX + maxX - x
+			}
+			if hitY {
+				t.y = minY + maxY - y
+			}
+		}
+	}
+}
+
+// edgeCircuits is how many times a bouncing move goes back and forth
+// across the canvas at most, or a wrapping one round it, along the axis it
+// crosses slowest; a longer move skips ahead, which leaves the same lines
+// whenever its path repeats that often.
+const edgeCircuits = 16
+
+// skipCircuits moves the turtle without drawing along all but the last
+// edgeCircuits circuits of a bouncing or wrapping move d long, forward if
+// sign is 1 and backward if -1, to where the edges would take it, and
+// returns the distance left.
+func (t *Turtle) skipCircuits(d, sign float64) float64 {
+	const eps = 1e-9
+	minX, minY, maxX, maxY := t.bounds()
+	dx, dy := t.direction()
+	dx, dy = sign*dx, sign*dy
+	k := 2.0 // crossings per circuit
+	if t.edges == Wrap {
+		k = 1
+	}
+	var circuit float64
+	if math.Abs(dx) > eps {
+		circuit = math.Max(circuit, k*(maxX-minX)/math.Abs(dx))
+	}
+	if math.Abs(dy) > eps {
+		circuit = math.Max(circuit, k*(maxY-minY)/math.Abs(dy))
+	}
+	if circuit == 0 || d <= 2*edgeCircuits*circuit {
+		return d
+	}
+	left := edgeCircuits*circuit + math.Mod(d, circuit)
+	skip := d - left
+	// Wrapping on an axis is moving along it modulo its size; bouncing is
+	// the same with every other crossing mirrored.
+	fold := func(v, lo, hi, dv float64) (float64, bool) {
+		if math.Abs(dv) <= eps {
+			return math.Max(lo, math.Min(hi, v+dv*skip)), false
+		}
+		u := wrapMod(v-lo+dv*skip, k*(hi-lo))
+		if u > hi-lo {
+			return hi - (u - (hi - lo)), true
+		}
+		return lo + u, false
+	}
+	x, flipX := fold(t.x, minX, maxX, dx)
+	y, flipY := fold(t.y, minY, maxY, dy)
+	t.x, t.y = x, y
+	if flipX || flipY {
+		h := t.headingDeg
+		if flipX {
+			h = 180 - h
+		}
+		if flipY {
+			h = -h
+		}
+		t.headingDeg = wrapMod(h, 360)
+	}
+	return left
+}
+
+// wrapMod returns v modulo m, in [0, m).
+func wrapMod(v, m float64) float64 {
+	if m <= 0 {
+		return 0
+	}
+	return math.Mod(math.Mod(v, m)+m, m)
+}
@@ -0,0 +1,232 @@
+// Package embroidery turns turtle drawings into machine-embroidery files.
+// Every line of the drawing is sewn as a running stitch; fills are sewn as
+// their outlines.
+//
+//	err := embroidery.SaveDST("star.dst", t, &embroidery.Options{Scale: 0.2})
+//
+// Files are in Tajima DST format, which nearly every embroidery machine and
+// editor reads, or Brother PES. DST stores no thread colors, only where to
+// change them; PES picks the nearest from Brother's palette.
+//
+// Stitches follow the paths the turtle retains, which it does only after
+// t.SetRetainPaths(true); call that before drawing.
+package embroidery
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/atomicfile"
+)
+
+// Options configures stitching. The zero value is usable.
+type Options struct {
+	Scale        float64 // millimetres per logical unit, defaults to 0.2
+	StitchLength float64 // longest stitch in millimetres, defaults to 2.5
+	Label        string  // design name stored in the file, up to 16 characters
+}
+
+func (o *Options) withDefaults() Options {
+	out := Options{Scale: 0.2, StitchLength: 2.5}
+	if o != nil {
+		out.Label = o.Label
+		if o.Scale > 0 {
+			out.Scale = o.Scale
+		}
+		if o.StitchLength > 0 {
+			out.StitchLength = o.StitchLength
+		}
+	}
+	return out
+}
+
+// Kind says what a Stitch does.
+type Kind int
+
+const (
+	Normal      Kind = iota // sew to the point
+	Jump                    // move to the point without sewing
+	ColorChange             // stop for the next thread; the point is unchanged
+)
+
+// Stitch is one needle position, in millimetres with y up, relative to
+// the turtle's origin.
+type Stitch struct {
+	X, Y  float64
+	Kind  Kind
+	Color color.Color // thread color from this stitch on
+}
+
+// Stitches returns the stitches that sew t's retained paths (see
+// gotuga.Turtle.Paths) in drawing order, splitting lines into stitches no
+// longer than StitchLength and changing thread when the pen color changes.
+func Stitches(t *gotuga.Turtle, opts *Options) []Stitch {
+	o := opts.withDefaults()
+	var out []Stitch
+	var thread color.Color
+	x, y := 0.0, 0.0
+	for _, p := range t.Paths() {
+		col := p.Color
+		if p.Fill != nil {
+			col = p.Fill
+		}
+		if len(p.Points) < 2 || col == nil {
+			continue
+		}
+		if thread == nil {
+			thread = col
+		} else if !sameColor(col, thread) {
+			thread = col
+			out = append(out, Stitch{X: x, Y: y, Kind: ColorChange, Color: col})
+		}
+		for i, pt := range p.Points {
+			tx, ty := pt[0]*o.Scale, pt[1]*o.Scale
+			kind := Normal
+			if i == 0 {
+				if tx == x && ty == y && len(out) > 0 {
+					continue
+				}
+				kind = Jump
+			}
+			n := 1 // jumps are split by the encoder, if need be
+			if kind == Normal {
+				n = max(1, int(math.Ceil(math.Hypot(tx-x, ty-y)/o.StitchLength)))
+			}
+			for j := 1; j <= n; j++ {
+				f := float64(j) / float64(n)
+				out = append(out, Stitch{X: x + (tx-x)*f, Y: y + (ty-y)*f, Kind: kind, Color: thread})
+			}
+			x, y = tx, ty
+		}
+	}
+	return out
+}
+
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+// SaveDST writes t's drawing to a Tajima DST file; see WriteDST.
+func SaveDST(filename string, t *gotuga.Turtle, opts *Options) error {
+	return atomicfile.Write(filename, t.SyncSaves(), func(w io.Writer) error { return WriteDST(w, t, opts) })
+}
+
+// WriteDST writes the stitches for t's drawing in Tajima DST format.
+func WriteDST(w io.Writer, t *gotuga.Turtle, opts *Options) error {
+	o := opts.withDefaults()
+	stitches := Stitches(t, &o)
+
+	// DST works in tenths of a millimetre, in moves of at most 121 units:
+	// longer ones, jumps included, are split.
+	type record struct {
+		dx, dy int
+		kind   Kind
+	}
+	var recs []record
+	var x, y, minX, maxX, minY, maxY, changes int
+	for _, s := range stitches {
+		if s.Kind == ColorChange {
+			recs = append(recs, record{kind: ColorChange})
+			changes++
+			continue
+		}
+		tx, ty := int(math.Round(s.X*10)), int(math.Round(s.Y*10))
+		n := max(1, (max(abs(tx-x), abs(ty-y))+120)/121)
+		for i := 1; i <= n; i++ {
+			recs = append(recs, record{(tx-x)*i/n - (tx-x)*(i-1)/n, (ty-y)*i/n - (ty-y)*(i-1)/n, s.Kind})
+		}
+		x, y = tx, ty
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+
+	bw := bufio.NewWriter(w)
+	label := o.Label
+	if len(label) > 16 {
+		label = label[:16]
+	}
+	var h strings.Builder
+	fmt.Fprintf(&h, "LA:%-16s\r", label)
+	fmt.Fprintf(&h, "ST:%7d\r", len(recs)+1)
+	fmt.Fprintf(&h, "CO:%3d\r", changes)
+	fmt.Fprintf(&h, "+X:%5d\r-X:%5d\r+Y:%5d\r-Y:%5d\r", maxX, -minX, maxY, -minY)
+	fmt.Fprintf(&h, "AX:%c%5d\rAY:%c%5d\rMX:+%5d\rMY:+%5d\rPD:%6s\r\x1a", sign(x), abs(x), sign(y), abs(y), 0, 0, "******")
+	header := h.String() + strings.Repeat(" ", 512-h.Len())
+	bw.WriteString(header)
+
+	for _, r := range recs {
+		b := encodeDST(r.dx, r.dy)
+		switch r.kind {
+		case Jump:
+			b[2] |= 0x80
+		case ColorChange:
+			b[2] |= 0xc0
+		}
+		bw.Write(b[:])
+	}
+	bw.Write([]byte{0, 0, 0xf3})
+	return bw.Flush()
+}
+
+// dstBits holds, for each balanced-ternary digit of a move, the byte and
+// bits meaning +1 and −1 in x and in y.
+var dstBits = [5]struct{ i, xp, xm, yp, ym byte }{
+	{0, 0x01, 0x02, 0x80, 0x40}, // 1
+	{1, 0x01, 0x02, 0x80, 0x40}, // 3
+	{0, 0x04, 0x08, 0x20, 0x10}, // 9
+	{1, 0x04, 0x08, 0x20, 0x10}, // 27
+	{2, 0x04, 0x08, 0x20, 0x10}, // 81
+}
+
+// encodeDST encodes a move of at most 121 units each way as a normal
+// stitch record.
+func encodeDST(dx, dy int) [3]byte {
+	b := [3]byte{0, 0, 0x03}
+	for _, d := range dstBits {
+		switch digit(&dx) {
+		case 1:
+			b[d.i] |= d.xp
+		case -1:
+			b[d.i] |= d.xm
+		}
+		switch digit(&dy) {
+		case 1:
+			b[d.i] |= d.yp
+		case -1:
+			b[d.i] |= d.ym
+		}
+	}
+	return b
+}
+
+// digit removes the lowest balanced-ternary digit from *v and returns it.
+func digit(v *int) int {
+	d := ((*v % 3) + 3) % 3
+	if d == 2 {
+		d = -1
+	}
+	*v = (*v - d) / 3
+	return d
+}
+
+// sign returns the sign DST headers write before the absolute value of v.
+func sign(v int) byte {
+	if v < 0 {
+		return '-'
+	}
+	return '+'
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
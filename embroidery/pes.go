@@ -0,0 +1,219 @@
+package embroidery
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/atomicfile"
+)
+
+// SavePES writes t's drawing to a Brother PES file; see WritePES.
+func SavePES(filename string, t *gotuga.Turtle, opts *Options) error {
+	return atomicfile.Write(filename, t.SyncSaves(), func(w io.Writer) error { return WritePES(w, t, opts) })
+}
+
+// WritePES writes the stitches for t's drawing as a Brother PES file,
+// version 1. Brother machines sew from its PEC section, which holds the
+// stitches, a thread from Brother's palette for each color and thumbnail
+// icons; the PES design section that Brother's editor keeps is left empty,
+// as other tools write it.
+func WritePES(w io.Writer, t *gotuga.Turtle, opts *Options) error {
+	o := opts.withDefaults()
+	stitches := Stitches(t, &o)
+
+	// PEC works in tenths of a millimetre with y down, in moves of at
+	// most 2047 units: longer ones are split.
+	type record struct {
+		x, y int // where the move ends
+		kind Kind
+	}
+	var recs []record
+	var x, y, minX, maxX, minY, maxY int
+	threads := []color.Color{color.Black}
+	if len(stitches) > 0 {
+		threads[0] = stitches[0].Color
+	}
+	for _, s := range stitches {
+		if s.Kind == ColorChange {
+			recs = append(recs, record{x, y, ColorChange})
+			threads = append(threads, s.Color)
+			continue
+		}
+		tx, ty := int(math.Round(s.X*10)), -int(math.Round(s.Y*10))
+		n := max(1, (max(abs(tx-x), abs(ty-y))+2046)/2047)
+		for i := 1; i <= n; i++ {
+			recs = append(recs, record{x + (tx-x)*i/n, y + (ty-y)*i/n, s.Kind})
+		}
+		x, y = tx, ty
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+	if len(threads) > 256 {
+		return fmt.Errorf("embroidery: %d thread colors, PES holds at most 256", len(threads))
+	}
+
+	bw := bufio.NewWriter(w)
+	// The PES header, pointing at the PEC section that follows it.
+	bw.WriteString("#PES0001")
+	binary.Write(bw, binary.LittleEndian, uint32(22))
+	bw.Write(make([]byte, 10))
+
+	label := o.Label
+	if len(label) > 16 {
+		label = label[:16]
+	}
+	var h strings.Builder
+	fmt.Fprintf(&h, "LA:%-16s\r", label)
+	h.WriteString("            \xff\x00")
+	h.WriteByte(pecIconWidth / 8)
+	h.WriteByte(pecIconHeight)
+	h.WriteString("    \x64 \x00 \x00   ")
+	h.WriteByte(byte(len(threads) - 1))
+	for _, c := range threads {
+		h.WriteByte(pecThread(c))
+	}
+	h.WriteString(strings.Repeat(" ", 512-h.Len()))
+	bw.WriteString(h.String())
+
+	var block []byte
+	le := binary.LittleEndian
+	block = append(block, 0, 0, 0, 0, 0, 0x31, 0xff, 0xf0) // length at 2, filled in below
+	block = le.AppendUint16(block, uint16(maxX-minX))
+	block = le.AppendUint16(block, uint16(maxY-minY))
+	block = le.AppendUint16(block, 0x1e0)
+	block = le.AppendUint16(block, 0x1b0)
+	block = binary.BigEndian.AppendUint16(block, 0x9000|uint16(-minX)&0xfff)
+	block = binary.BigEndian.AppendUint16(block, 0x9000|uint16(-minY)&0xfff)
+	x, y = 0, 0
+	second := true // color changes alternate between 2 and 1
+	sewn := false
+	for _, r := range recs {
+		dx, dy := r.x-x, r.y-y
+		x, y = r.x, r.y
+		switch {
+		case r.kind == ColorChange:
+			b := byte(1)
+			if second {
+				b = 2
+			}
+			second = !second
+			block = append(block, 0xfe, 0xb0, b)
+		case r.kind == Jump:
+			// Jumps before the first stitch position the needle; later
+			// ones also trim the thread.
+			flag := uint16(0x1000)
+			if sewn {
+				flag = 0x2000
+			}
+			block = binary.BigEndian.AppendUint16(block, pecLong(dx)|flag)
+			block = binary.BigEndian.AppendUint16(block, pecLong(dy)|flag)
+		case dx > -64 && dx < 63 && dy > -64 && dy < 63:
+			block = append(block, byte(dx)&0x7f, byte(dy)&0x7f)
+			sewn = true
+		default:
+			block = binary.BigEndian.AppendUint16(block, pecLong(dx))
+			block = binary.BigEndian.AppendUint16(block, pecLong(dy))
+			sewn = true
+		}
+	}
+	block = append(block, 0xff)
+	block[2], block[3], block[4] = byte(len(block)), byte(len(block)>>8), byte(len(block)>>16)
+	bw.Write(block)
+
+	// Icons of the whole design and of each color's stitches.
+	icon := func(thread int) {
+		bits := pecBlankIcon()
+		w, h := float64(maxX-minX+1), float64(maxY-minY+1)
+		scale := math.Min((pecIconWidth-10)/w, (pecIconHeight-10)/h)
+		c := 0
+		for _, r := range recs {
+			if r.kind == ColorChange {
+				c++
+				continue
+			}
+			if r.kind == Jump || thread >= 0 && c != thread {
+				continue
+			}
+			ix := 5 + int(float64(r.x-minX)*scale)
+			iy := 5 + int(float64(r.y-minY)*scale)
+			bits[iy*pecIconWidth/8+ix/8] |= 1 << (ix % 8)
+		}
+		bw.Write(bits)
+	}
+	icon(-1)
+	for i := range threads {
+		icon(i)
+	}
+	return bw.Flush()
+}
+
+// The size of PEC thumbnail icons, one bit per pixel.
+const (
+	pecIconWidth  = 48
+	pecIconHeight = 38
+)
+
+// pecBlankIcon returns an empty icon with a rounded border.
+func pecBlankIcon() []byte {
+	rows := make([][6]byte, pecIconHeight)
+	rows[1] = [6]byte{0xf0, 0xff, 0xff, 0xff, 0xff, 0x0f}
+	rows[2] = [6]byte{0x08, 0, 0, 0, 0, 0x10}
+	rows[3] = [6]byte{0x04, 0, 0, 0, 0, 0x20}
+	for i := 4; i < pecIconHeight-4; i++ {
+		rows[i] = [6]byte{0x02, 0, 0, 0, 0, 0x40}
+	}
+	rows[pecIconHeight-4] = rows[3]
+	rows[pecIconHeight-3] = rows[2]
+	rows[pecIconHeight-2] = rows[1]
+	var b []byte
+	for _, r := range rows {
+		b = append(b, r[:]...)
+	}
+	return b
+}
+
+// pecLong encodes a move of at most 2047 units as the 12-bit long form.
+func pecLong(d int) uint16 {
+	return 0x8000 | uint16(d)&0xfff
+}
+
+// pecThread returns the index in pecThreads of the thread nearest c.
+func pecThread(c color.Color) byte {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	best, dist := 1, math.Inf(1)
+	for i, t := range pecThreads[1:] {
+		dr, dg, db := float64(n.R)-float64(t.R), float64(n.G)-float64(t.G), float64(n.B)-float64(t.B)
+		if d := dr*dr + dg*dg + db*db; d < dist {
+			best, dist = i+1, d
+		}
+	}
+	return byte(best)
+}
+
+// pecThreads is Brother's thread palette, which PEC files index; 0 is
+// unused.
+var pecThreads = [...]color.NRGBA{
+	{0, 0, 0, 255}, {14, 31, 124, 255}, {10, 85, 163, 255}, {0, 135, 119, 255},
+	{75, 107, 175, 255}, {237, 23, 31, 255}, {209, 92, 0, 255}, {145, 54, 151, 255},
+	{228, 154, 203, 255}, {145, 95, 172, 255}, {158, 214, 125, 255}, {232, 169, 0, 255},
+	{254, 186, 53, 255}, {255, 255, 0, 255}, {112, 188, 31, 255}, {186, 152, 0, 255},
+	{168, 168, 168, 255}, {125, 111, 0, 255}, {255, 255, 179, 255}, {79, 85, 86, 255},
+	{0, 0, 0, 255}, {11, 61, 145, 255}, {119, 1, 118, 255}, {41, 49, 51, 255},
+	{42, 19, 1, 255}, {246, 74, 138, 255}, {178, 118, 36, 255}, {252, 187, 197, 255},
+	{254, 55, 15, 255}, {240, 240, 240, 255}, {106, 28, 138, 255}, {168, 221, 196, 255},
+	{37, 132, 187, 255}, {254, 179, 67, 255}, {255, 243, 107, 255}, {208, 166, 96, 255},
+	{209, 84, 0, 255}, {102, 186, 73, 255}, {19, 74, 70, 255}, {135, 135, 135, 255},
+	{216, 204, 198, 255}, {67, 86, 7, 255}, {253, 217, 222, 255}, {249, 147, 188, 255},
+	{0, 56, 34, 255}, {178, 175, 212, 255}, {104, 106, 176, 255}, {239, 227, 185, 255},
+	{247, 56, 102, 255}, {181, 75, 100, 255}, {19, 43, 26, 255}, {199, 1, 86, 255},
+	{254, 158, 50, 255}, {168, 222, 235, 255}, {0, 103, 62, 255}, {78, 41, 144, 255},
+	{47, 126, 32, 255}, {255, 204, 204, 255}, {255, 217, 17, 255}, {9, 91, 166, 255},
+	{240, 249, 112, 255}, {227, 243, 91, 255}, {255, 153, 0, 255}, {255, 240, 141, 255},
+	{255, 200, 200, 255},
+}
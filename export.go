@@ -0,0 +1,114 @@
+package gotuga
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a file format the canvas can be saved in.
+type Format int
+
+const (
+	FormatPNG     Format = iota // .png
+	FormatJPEG                  // .jpg or .jpeg, at quality 90
+	FormatGIF                   // .gif, a single dithered frame
+	FormatSession               // .tuga, the commands rather than pixels; see Session
+)
+
+// defaultJPEGQuality is the quality SaveTo uses for JPEG.
+const defaultJPEGQuality = 90
+
+// FormatFor returns the format for a file name's extension.
+func FormatFor(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".png":
+		return FormatPNG, nil
+	case ".jpg", ".jpeg":
+		return FormatJPEG, nil
+	case ".gif":
+		return FormatGIF, nil
+	case ".tuga":
+		return FormatSession, nil
+	}
+	return 0, fmt.Errorf("gotuga: unknown image format for %q", filename)
+}
+
+// Save writes the canvas to a file in the format its extension names; see
+// FormatFor.
+func (t *Turtle) Save(filename string) error {
+	format, err := FormatFor(filename)
+	if err != nil {
+		return err
+	}
+	return writeFile(filename, t.syncSaves, func(w io.Writer) error { return t.SaveTo(w, format) })
+}
+
+// SaveTo encodes the canvas in the given format.
+func (t *Turtle) SaveTo(w io.Writer, format Format) error {
+	switch format {
+	case FormatPNG:
+		defer t.profileEncode("png", t.canvas.Rect)()
+		return png.Encode(w, t.output())
+	case FormatJPEG:
+		return t.WriteJPEG(w, defaultJPEGQuality)
+	case FormatGIF:
+		defer t.profileEncode("gif", t.canvas.Rect)()
+		return gif.Encode(w, t.output(), nil)
+	case FormatSession:
+		if !t.recordHistory {
+			return errNoHistory
+		}
+		return t.Session().Write(w)
+	}
+	return fmt.Errorf("gotuga: unknown format %d", format)
+}
+
+// SaveJPEG writes the canvas to a JPEG file with the given quality, 1 to
+// 100; see WriteJPEG.
+func (t *Turtle) SaveJPEG(filename string, quality int) error {
+	return writeFile(filename, t.syncSaves, func(w io.Writer) error { return t.WriteJPEG(w, quality) })
+}
+
+// WriteJPEG encodes the canvas as JPEG with the given quality, 1 to 100,
+// clamped. JPEG has no transparency, so the canvas is first flattened
+// against the background color, itself laid over white.
+func (t *Turtle) WriteJPEG(w io.Writer, quality int) error {
+	quality = max(1, min(100, quality))
+	defer t.profileEncode("jpeg", t.canvas.Rect)()
+	return jpeg.Encode(w, t.flatten(), &jpeg.Options{Quality: quality})
+}
+
+// flatten returns an opaque copy of the canvas over the background color,
+// itself over white, in sRGB.
+func (t *Turtle) flatten() *image.RGBA {
+	img := image.NewRGBA(t.canvas.Bounds())
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+	draw.Draw(img, img.Rect, &image.Uniform{C: t.bg}, image.Point{}, draw.Over)
+	draw.Draw(img, img.Rect, t.pixels(), t.canvas.Rect.Min, draw.Over)
+	if t.colorSpace == LinearRGB {
+		return linearToSRGB(img)
+	}
+	return img
+}
+
+// DataURI returns the canvas as a data:image/png;base64 URI, for embedding
+// in generated HTML or email.
+func (t *Turtle) DataURI() (string, error) {
+	var buf bytes.Buffer
+	end := t.profileEncode("png", t.canvas.Rect)
+	err := png.Encode(&buf, t.output())
+	end()
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
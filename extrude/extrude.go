@@ -0,0 +1,159 @@
+// Package extrude turns turtle drawings into 3D-printable solids. WriteSCAD
+// writes the closed shapes of a drawing as an OpenSCAD model, extruded to a
+// height, or as walls around their outlines for cookie cutters. WriteSTL
+// thickens and extrudes the lines into a mesh.
+//
+//	err := extrude.SaveSCAD("star.scad", t, &extrude.Options{Height: 15, Wall: 1})
+//	err = extrude.SaveSTL("star.stl", t, &extrude.Options{Height: 3, Width: 2})
+//
+// Both work from the turtle's retained paths, kept once retention is on:
+// call t.SetRetainPaths(true) before drawing.
+package extrude
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/atomicfile"
+)
+
+// Options configures the solids. The zero value is usable.
+type Options struct {
+	Scale  float64 // millimetres per logical unit, defaults to 0.25
+	Height float64 // extrusion height in mm, defaults to 5
+	Wall   float64 // for SCAD, wall thickness around the outlines in mm; 0 extrudes the shapes solid
+	Width  float64 // for STL, stroke width in mm; 0 uses each line's pen width
+}
+
+func (o *Options) withDefaults() Options {
+	out := Options{Scale: 0.25, Height: 5}
+	if o != nil {
+		out.Wall, out.Width = o.Wall, o.Width
+		if o.Scale > 0 {
+			out.Scale = o.Scale
+		}
+		if o.Height > 0 {
+			out.Height = o.Height
+		}
+	}
+	return out
+}
+
+// Outlines returns the closed shapes among t's retained paths (see
+// gotuga.Turtle.Paths), fills and lines that end where they start, in
+// millimetres, without the repeated last point. A fill and the outline
+// drawn around it count once.
+func Outlines(t *gotuga.Turtle, opts *Options) [][][2]float64 {
+	o := opts.withDefaults()
+	var out [][][2]float64
+	for _, p := range t.Paths() {
+		n := len(p.Points)
+		if n < 3 {
+			continue
+		}
+		first, last := p.Points[0], p.Points[n-1]
+		if math.Hypot(first[0]-last[0], first[1]-last[1]) < 1e-6 {
+			n--
+		} else if p.Fill == nil {
+			continue // an open line
+		}
+		if n < 3 {
+			continue
+		}
+		ring := make([][2]float64, n)
+		for i, pt := range p.Points[:n] {
+			ring[i] = [2]float64{pt[0] * o.Scale, pt[1] * o.Scale}
+		}
+		if !slices.ContainsFunc(out, func(r [][2]float64) bool { return sameRing(r, ring) }) {
+			out = append(out, ring)
+		}
+	}
+	return out
+}
+
+// sameRing reports whether a and b have the same points in the same order,
+// perhaps starting at different points.
+func sameRing(a, b [][2]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	near := func(p, q [2]float64) bool { return math.Hypot(p[0]-q[0], p[1]-q[1]) < 1e-6 }
+	for k := range b {
+		if !near(a[0], b[k]) {
+			continue
+		}
+		same := true
+		for i := range a {
+			if !near(a[i], b[(i+k)%len(b)]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveSCAD writes t's closed shapes to an OpenSCAD file; see WriteSCAD.
+func SaveSCAD(filename string, t *gotuga.Turtle, opts *Options) error {
+	return atomicfile.Write(filename, t.SyncSaves(), func(w io.Writer) error { return WriteSCAD(w, t, opts) })
+}
+
+// WriteSCAD writes t's closed shapes (see Outlines) as an OpenSCAD model:
+// one polygon, in which shapes inside others are holes, extruded to
+// Height. With a Wall, only a wall of that thickness around the outside of
+// each outline is extruded, the shape of a cookie cutter.
+func WriteSCAD(w io.Writer, t *gotuga.Turtle, opts *Options) error {
+	o := opts.withDefaults()
+	rings := Outlines(t, &o)
+	if len(rings) == 0 {
+		return fmt.Errorf("extrude: the drawing has no closed shapes")
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("// Generated by GoTuga, in millimetres.\nshape_points = [")
+	for i, ring := range rings {
+		for j, pt := range ring {
+			if i > 0 || j > 0 {
+				bw.WriteString(",")
+			}
+			fmt.Fprintf(bw, "[%s,%s]", num(pt[0]), num(pt[1]))
+		}
+	}
+	bw.WriteString("];\nshape_paths = [")
+	k := 0
+	for i, ring := range rings {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("[")
+		for j := range ring {
+			if j > 0 {
+				bw.WriteString(",")
+			}
+			bw.WriteString(strconv.Itoa(k))
+			k++
+		}
+		bw.WriteString("]")
+	}
+	bw.WriteString("];\n\n")
+	fmt.Fprintf(bw, "linear_extrude(height = %s)\n", num(o.Height))
+	if o.Wall > 0 {
+		fmt.Fprintf(bw, "difference() {\n\toffset(delta = %s) polygon(shape_points, shape_paths);\n\tpolygon(shape_points, shape_paths);\n}\n", num(o.Wall))
+	} else {
+		bw.WriteString("polygon(shape_points, shape_paths);\n")
+	}
+	return bw.Flush()
+}
+
+// num formats a length compactly, to a thousandth of a millimetre.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
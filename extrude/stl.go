@@ -0,0 +1,118 @@
+package extrude
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/atomicfile"
+	"github.com/Z6dev/GoTuga/internal/polyline"
+)
+
+type vec3 [3]float64
+
+func (a vec3) sub(b vec3) vec3    { return vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+func (a vec3) dot(b vec3) float64 { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+func (a vec3) cross(b vec3) vec3 {
+	return vec3{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+// mesh collects triangles, each wound counterclockwise seen from outside.
+type mesh [][3]vec3
+
+// quad adds the quadrilateral a b c d, given counterclockwise seen from
+// outside.
+func (m *mesh) quad(a, b, c, d vec3) {
+	*m = append(*m, [3]vec3{a, b, c}, [3]vec3{a, c, d})
+}
+
+// SaveSTL writes t's strokes to an STL file; see WriteSTL.
+func SaveSTL(filename string, t *gotuga.Turtle, opts *Options) error {
+	return atomicfile.Write(filename, t.SyncSaves(), func(w io.Writer) error { return WriteSTL(w, t, opts) })
+}
+
+// WriteSTL writes the lines among t's retained paths (see
+// gotuga.Turtle.Paths) as a binary STL mesh, each line thickened to its pen
+// width, or Width if set, with mitered corners and extruded to Height, so
+// line art can be printed as a wire sculpture. Every line is a closed,
+// watertight shell; where lines cross, the shells overlap and slicers
+// merge them. Fills are left out.
+func WriteSTL(w io.Writer, t *gotuga.Turtle, opts *Options) error {
+	o := opts.withDefaults()
+	var m mesh
+	for _, p := range t.Paths() {
+		if p.Fill != nil {
+			continue
+		}
+		width := o.Width
+		if width <= 0 {
+			width = p.Width * o.Scale
+		}
+		var pts [][2]float64
+		for _, pt := range p.Points {
+			q := [2]float64{pt[0] * o.Scale, pt[1] * o.Scale}
+			if len(pts) == 0 || math.Hypot(q[0]-pts[len(pts)-1][0], q[1]-pts[len(pts)-1][1]) > 1e-6 {
+				pts = append(pts, q)
+			}
+		}
+		if len(pts) >= 2 {
+			m.stroke(pts, width/2, o.Height)
+		}
+	}
+	if len(m) == 0 {
+		return fmt.Errorf("extrude: the drawing has no lines")
+	}
+
+	bw := bufio.NewWriter(w)
+	var header [80]byte
+	copy(header[:], "GoTuga strokes, millimetres")
+	bw.Write(header[:])
+	binary.Write(bw, binary.LittleEndian, uint32(len(m)))
+	for _, tr := range m {
+		n := tr[1].sub(tr[0]).cross(tr[2].sub(tr[0]))
+		if l := math.Sqrt(n.dot(n)); l > 0 {
+			n = vec3{n[0] / l, n[1] / l, n[2] / l}
+		}
+		var rec [50]byte
+		for i, v := range []vec3{n, tr[0], tr[1], tr[2]} {
+			for j := range v {
+				binary.LittleEndian.PutUint32(rec[12*i+4*j:], math.Float32bits(float32(v[j])))
+			}
+		}
+		bw.Write(rec[:])
+	}
+	return bw.Flush()
+}
+
+// stroke adds the polyline pts, thickened by h each side and extruded to
+// height, as a closed shell. A polyline that ends where it starts becomes
+// a ring.
+func (m *mesh) stroke(pts [][2]float64, h, height float64) {
+	n := len(pts)
+	closed := n >= 4 && math.Hypot(pts[0][0]-pts[n-1][0], pts[0][1]-pts[n-1][1]) < 1e-6
+	if closed {
+		pts = pts[:n-1]
+		n--
+	}
+	ls, rs := polyline.Offset(pts, h, closed), polyline.Offset(pts, -h, closed)
+
+	at := func(p [2]float64, z float64) vec3 { return vec3{p[0], p[1], z} }
+	segs := n - 1
+	if closed {
+		segs = n
+	}
+	for i := 0; i < segs; i++ {
+		j := (i + 1) % n
+		m.quad(at(rs[i], height), at(rs[j], height), at(ls[j], height), at(ls[i], height)) // top
+		m.quad(at(rs[i], 0), at(ls[i], 0), at(ls[j], 0), at(rs[j], 0))                     // bottom
+		m.quad(at(ls[i], 0), at(ls[i], height), at(ls[j], height), at(ls[j], 0))           // left side
+		m.quad(at(rs[i], 0), at(rs[j], 0), at(rs[j], height), at(rs[i], height))           // right side
+	}
+	if !closed {
+		m.quad(at(ls[0], 0), at(rs[0], 0), at(rs[0], height), at(ls[0], height))
+		m.quad(at(ls[n-1], 0), at(ls[n-1], height), at(rs[n-1], height), at(rs[n-1], 0))
+	}
+}
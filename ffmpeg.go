@@ -0,0 +1,119 @@
+package gotuga
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FFmpegSink is a FrameSink that pipes raw frames into an ffmpeg process
+// writing a video file. ffmpeg must be installed and on the PATH.
+type FFmpegSink struct {
+	// OutputArgs are extra ffmpeg options placed before the output path,
+	// e.g. []string{"-crf", "18"}. Set them before the first frame.
+	OutputArgs []string
+
+	path   string
+	fps    int
+	size   image.Point
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+	err    error // set once ffmpeg has failed
+}
+
+// NewFFmpegSink returns a sink encoding frames at fps into the video file at
+// path; the container and codec follow the file extension. ffmpeg is started
+// when the first frame arrives, since that fixes the video size.
+func NewFFmpegSink(path string, fps int) (*FFmpegSink, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, err
+	}
+	if fps <= 0 {
+		return nil, errors.New("gotuga: fps must be positive")
+	}
+	return &FFmpegSink{path: path, fps: fps}, nil
+}
+
+// FPS returns the frame rate of the video.
+func (s *FFmpegSink) FPS() int { return s.fps }
+
+// WriteFrame sends one frame to ffmpeg.
+func (s *FFmpegSink) WriteFrame(img *image.RGBA) error {
+	if s.err != nil {
+		return s.err
+	}
+	size := img.Bounds().Size()
+	if s.cmd == nil {
+		if err := s.start(size); err != nil {
+			return err
+		}
+	} else if size != s.size {
+		return fmt.Errorf("gotuga: frame size %v differs from video size %v", size, s.size)
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]
+		if _, err := s.stdin.Write(row); err != nil {
+			// ffmpeg has quit; wait for it so its error output is complete.
+			s.stdin.Close()
+			s.cmd.Wait()
+			s.err = s.failure(err)
+			return s.err
+		}
+	}
+	return nil
+}
+
+// Close finishes the video and waits for ffmpeg to exit.
+func (s *FFmpegSink) Close() error {
+	if s.cmd == nil || s.err != nil {
+		return s.err
+	}
+	s.stdin.Close()
+	if err := s.cmd.Wait(); err != nil {
+		return s.failure(err)
+	}
+	return nil
+}
+
+func (s *FFmpegSink) start(size image.Point) error {
+	args := []string{
+		"-y", "-loglevel", "error",
+		"-f", "rawvideo", "-pix_fmt", "rgba",
+		"-s", fmt.Sprintf("%dx%d", size.X, size.Y),
+		"-r", strconv.Itoa(s.fps),
+		"-i", "-",
+	}
+	switch strings.ToLower(filepath.Ext(s.path)) {
+	case ".mp4", ".mov", ".mkv":
+		// Widely playable H.264 needs 4:2:0 chroma and even dimensions.
+		args = append(args, "-pix_fmt", "yuv420p", "-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2")
+	}
+	args = append(args, s.OutputArgs...)
+	args = append(args, s.path)
+
+	s.cmd = exec.Command("ffmpeg", args...)
+	s.cmd.Stderr = &s.stderr
+	stdin, err := s.cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	s.stdin = stdin
+	s.size = size
+	return s.cmd.Start()
+}
+
+// failure adds ffmpeg's own error output to err.
+func (s *FFmpegSink) failure(err error) error {
+	if msg := strings.TrimSpace(s.stderr.String()); msg != "" {
+		return fmt.Errorf("gotuga: ffmpeg: %v: %s", err, msg)
+	}
+	return fmt.Errorf("gotuga: ffmpeg: %v", err)
+}
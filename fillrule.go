@@ -0,0 +1,24 @@
+package gotuga
+
+// FillRule says which parts of a self-intersecting shape are inside.
+type FillRule int
+
+const (
+	NonZero FillRule = iota // inside where the outline winds around at all
+	EvenOdd                 // inside where crossed an odd number of times
+)
+
+// SetFillRule sets how EndFill and ClipPoly decide what is inside a shape
+// whose outline crosses itself. With the default NonZero, a five-pointed
+// star drawn in one stroke fills solidly; EvenOdd leaves its center
+// pentagon empty, as the old parity fill did.
+func (t *Turtle) SetFillRule(rule FillRule) {
+	if t.refuse("fillrule") {
+		return
+	}
+	defer t.track("fillrule", float64(rule))()
+	t.fillRule = rule
+}
+
+// FillRule returns the fill rule.
+func (t *Turtle) FillRule() FillRule { return t.fillRule }
@@ -0,0 +1,135 @@
+// Package fractal draws classic fractals with a turtle.
+//
+// Every function starts at the turtle's current position and heading.
+// Curves (KochCurve, DragonCurve) leave the turtle at their far end, facing
+// the way it started, so they can be chained; closed figures and trees
+// return it to where it started.
+package fractal
+
+import (
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// KochCurve draws a Koch curve of the given overall length, with its bumps
+// to the left.
+func KochCurve(t *gotuga.Turtle, length float64, depth int) {
+	if depth <= 0 {
+		t.Forward(length)
+		return
+	}
+	l := length / 3
+	KochCurve(t, l, depth-1)
+	t.Left(60)
+	KochCurve(t, l, depth-1)
+	t.Right(120)
+	KochCurve(t, l, depth-1)
+	t.Left(60)
+	KochCurve(t, l, depth-1)
+}
+
+// KochSnowflake draws a Koch snowflake on a triangle with the given side,
+// turning clockwise from the starting corner.
+func KochSnowflake(t *gotuga.Turtle, side float64, depth int) {
+	for i := 0; i < 3; i++ {
+		KochCurve(t, side, depth)
+		t.Right(120)
+	}
+}
+
+// SierpinskiTriangle draws a Sierpinski triangle with the given side as
+// outlined triangles, turning counterclockwise from the starting corner.
+func SierpinskiTriangle(t *gotuga.Turtle, side float64, depth int) {
+	if depth <= 0 {
+		for i := 0; i < 3; i++ {
+			t.Forward(side)
+			t.Left(120)
+		}
+		return
+	}
+	half := side / 2
+	SierpinskiTriangle(t, half, depth-1)
+	move(t, half, 0)
+	SierpinskiTriangle(t, half, depth-1)
+	move(t, -half/2, half*math.Sqrt(3)/2)
+	SierpinskiTriangle(t, half, depth-1)
+	move(t, -half/2, -half*math.Sqrt(3)/2)
+}
+
+// SierpinskiCarpet draws a Sierpinski carpet with the given side as filled
+// squares, extending forward and to the left of the turtle. The squares are
+// filled with the pen color, which also becomes the fill color.
+func SierpinskiCarpet(t *gotuga.Turtle, side float64, depth int) {
+	t.FillColor(t.Color())
+	carpet(t, side, depth)
+}
+
+func carpet(t *gotuga.Turtle, side float64, depth int) {
+	if depth <= 0 {
+		t.BeginFill()
+		t.Rect(side, side)
+		t.EndFill()
+		return
+	}
+	third := side / 3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if i == 1 && j == 1 {
+				continue
+			}
+			move(t, float64(i)*third, float64(j)*third)
+			carpet(t, third, depth-1)
+			move(t, -float64(i)*third, -float64(j)*third)
+		}
+	}
+}
+
+// DragonCurve draws a Heighway dragon whose ends are the given length
+// apart.
+func DragonCurve(t *gotuga.Turtle, length float64, depth int) {
+	dragon(t, length, depth, 1)
+}
+
+func dragon(t *gotuga.Turtle, length float64, depth int, turn float64) {
+	if depth <= 0 {
+		t.Forward(length)
+		return
+	}
+	l := length / math.Sqrt2
+	t.Right(45 * turn)
+	dragon(t, l, depth-1, 1)
+	t.Left(90 * turn)
+	dragon(t, l, depth-1, -1)
+	t.Right(45 * turn)
+}
+
+// Tree draws a binary fractal tree: a trunk of the given length, then two
+// branches turned by angle degrees either way, each shrink times as long,
+// down to depth levels.
+func Tree(t *gotuga.Turtle, trunk float64, depth int, angle, shrink float64) {
+	if depth <= 0 {
+		return
+	}
+	t.Forward(trunk)
+	t.Left(angle)
+	Tree(t, trunk*shrink, depth-1, angle, shrink)
+	t.Right(2 * angle)
+	Tree(t, trunk*shrink, depth-1, angle, shrink)
+	t.Left(angle)
+	move(t, -trunk, 0)
+}
+
+// move shifts the turtle without drawing, forward by dx and left by dy,
+// keeping its heading.
+func move(t *gotuga.Turtle, dx, dy float64) {
+	down := t.IsDown()
+	t.PenUp()
+	t.Forward(dx)
+	t.Left(90)
+	t.Forward(dy)
+	t.Right(90)
+	if down {
+		t.PenDown()
+	}
+}
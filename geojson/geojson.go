@@ -0,0 +1,128 @@
+// Package geojson exports turtle drawings as GeoJSON, placing them on the
+// map through an affine transform, so generated routes, mazes and
+// labyrinths can be loaded into mapping tools.
+//
+//	// One logical unit to a metre, the origin at the Eiffel Tower.
+//	err := geojson.Save("maze.geojson", t, geojson.Meters(2.2945, 48.8584, 1))
+//
+// Lines become LineString features and fills Polygon features, styled with
+// the simplestyle properties (stroke, stroke-width, fill) many viewers
+// understand. Features come from the turtle's retained paths, so it must
+// draw with retention on, set by t.SetRetainPaths(true).
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"slices"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/atomicfile"
+)
+
+// Affine maps logical coordinates to longitude and latitude:
+//
+//	lon = A[0]·x + A[1]·y + A[2]
+//	lat = A[3]·x + A[4]·y + A[5]
+type Affine [6]float64
+
+// Identity takes logical coordinates as degrees.
+var Identity = Affine{1, 0, 0, 0, 1, 0}
+
+// Meters returns the transform placing the logical origin at (lon, lat)
+// with metersPerUnit metres to a logical unit, x east and y north. It is
+// accurate for drawings a few kilometres across.
+func Meters(lon, lat, metersPerUnit float64) Affine {
+	const metersPerDegree = 111320 // along a meridian, and the equator
+	dLat := metersPerUnit / metersPerDegree
+	dLon := dLat / math.Cos(lat*math.Pi/180)
+	return Affine{dLon, 0, lon, 0, dLat, lat}
+}
+
+// Apply maps a logical point to [lon, lat].
+func (a Affine) Apply(x, y float64) [2]float64 {
+	return [2]float64{a[0]*x + a[1]*y + a[2], a[3]*x + a[4]*y + a[5]}
+}
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string         `json:"type"`
+	Geometry   geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type geometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// Save writes t's drawing to a GeoJSON file; see Write.
+func Save(filename string, t *gotuga.Turtle, tr Affine) error {
+	return atomicfile.Write(filename, t.SyncSaves(), func(w io.Writer) error { return Write(w, t, tr) })
+}
+
+// Write writes t's retained paths (see gotuga.Turtle.Paths), mapped by tr,
+// as a GeoJSON FeatureCollection in drawing order.
+func Write(w io.Writer, t *gotuga.Turtle, tr Affine) error {
+	fc := featureCollection{Type: "FeatureCollection", Features: []feature{}}
+	for _, p := range t.Paths() {
+		if len(p.Points) < 2 {
+			continue
+		}
+		coords := make([][2]float64, len(p.Points))
+		for i, pt := range p.Points {
+			coords[i] = tr.Apply(pt[0], pt[1])
+		}
+		if p.Fill != nil {
+			if len(coords) < 3 {
+				continue
+			}
+			if coords[0] != coords[len(coords)-1] {
+				coords = append(coords, coords[0])
+			}
+			if len(coords) < 4 {
+				continue
+			}
+			if area(coords) < 0 {
+				slices.Reverse(coords) // RFC 7946 wants outer rings counterclockwise
+			}
+			hex, opacity := style(p.Fill)
+			fc.Features = append(fc.Features, feature{
+				Type:       "Feature",
+				Geometry:   geometry{"Polygon", [][][2]float64{coords}},
+				Properties: map[string]any{"fill": hex, "fill-opacity": opacity, "stroke-width": 0},
+			})
+			continue
+		}
+		hex, opacity := style(p.Color)
+		fc.Features = append(fc.Features, feature{
+			Type:       "Feature",
+			Geometry:   geometry{"LineString", coords},
+			Properties: map[string]any{"stroke": hex, "stroke-opacity": opacity, "stroke-width": p.Width},
+		})
+	}
+	return json.NewEncoder(w).Encode(fc)
+}
+
+// area returns the signed area of a closed ring, positive if it runs
+// counterclockwise.
+func area(ring [][2]float64) float64 {
+	a := 0.0
+	for i := 1; i < len(ring); i++ {
+		a += ring[i-1][0]*ring[i][1] - ring[i][0]*ring[i-1][1]
+	}
+	return a / 2
+}
+
+// style returns a color as #rrggbb and an opacity.
+func style(c color.Color) (string, float64) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B), math.Round(float64(n.A)/255*1000) / 1000
+}
@@ -0,0 +1,58 @@
+// Package ggexport hands turtle drawings to a fogleman/gg context, for
+// gg's gradients, text, clipping and transforms. The turtle's retained
+// paths (see gotuga.Turtle.Paths) are redrawn as gg vector paths, so the
+// geometry is exact at any resolution. The turtle retains paths only
+// after t.SetRetainPaths(true), so call that before drawing.
+//
+//	dc := ggexport.NewContext(t, 2) // twice the canvas resolution
+//	dc.SetRGB(0, 0, 0)
+//	dc.DrawString("Spiral", 20, 40)
+//	dc.SavePNG("spiral.png")
+package ggexport
+
+import (
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/fogleman/gg"
+)
+
+// NewContext returns a context scale times the size of t's canvas, filled
+// with its background and with t's paths drawn on it.
+func NewContext(t *gotuga.Turtle, scale float64) *gg.Context {
+	if scale <= 0 {
+		scale = 1
+	}
+	dc := gg.NewContext(int(float64(t.W)*scale+0.5), int(float64(t.H)*scale+0.5))
+	dc.SetColor(t.Background())
+	dc.Clear()
+	dc.Scale(scale, scale)
+	Draw(dc, t)
+	dc.Identity()
+	return dc
+}
+
+// Draw draws t's paths onto dc under its current transform, in canvas
+// pixel coordinates (origin at the top left, y down), so a context the
+// size of the canvas lines up with it. Lines get round caps and joins, like
+// the turtle's pen. The context's color, line width and path are changed.
+func Draw(dc *gg.Context, t *gotuga.Turtle) {
+	dc.SetLineCapRound()
+	dc.SetLineJoinRound()
+	for _, p := range t.Paths() {
+		if len(p.Points) == 0 {
+			continue
+		}
+		dc.NewSubPath()
+		for _, pt := range p.Points {
+			dc.LineTo(t.CanvasPoint(pt[0], pt[1]))
+		}
+		if p.Fill != nil {
+			dc.ClosePath()
+			dc.SetColor(p.Fill)
+			dc.Fill()
+			continue
+		}
+		dc.SetColor(p.Color)
+		dc.SetLineWidth(p.Width * t.Scale())
+		dc.Stroke()
+	}
+}
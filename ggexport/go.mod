@@ -0,0 +1,15 @@
+module github.com/Z6dev/GoTuga/ggexport
+
+go 1.24.5
+
+require (
+	github.com/Z6dev/GoTuga v0.0.0
+	github.com/fogleman/gg v1.3.0
+)
+
+require (
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
+	golang.org/x/image v0.26.0 // indirect
+)
+
+replace github.com/Z6dev/GoTuga => ../
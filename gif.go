@@ -0,0 +1,224 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// SaveGIF writes the recording to filename as an animated GIF.
+// See WriteGIF.
+func (r *Recorder) SaveGIF(filename string, opts *FrameOptions) error {
+	return writeFile(filename, r.t.syncSaves, func(w io.Writer) error { return r.WriteGIF(w, opts) })
+}
+
+// WriteGIF encodes the recording as an animated GIF. Frame delays follow the
+// recording clock; frames captured at the same instant are merged and frames
+// identical to the previous one only extend its delay.
+//
+// Only the rectangle that changed since the previous frame is stored, with
+// unchanged pixels inside it left transparent. All frames share one palette
+// when the whole recording uses at most 255 colors; otherwise each frame gets
+// its own palette, exact where possible and Plan 9 otherwise.
+func (r *Recorder) WriteGIF(w io.Writer, opts *FrameOptions) error {
+	var loop int
+	if opts != nil {
+		loop = opts.LoopCount
+	}
+	frames, delays := r.timedFrames(opts)
+	g := &gif.GIF{LoopCount: loop}
+	if len(frames) == 0 {
+		return gif.EncodeAll(w, g)
+	}
+
+	var shared color.Palette
+	if colors, ok := collectColors(frames); ok {
+		shared = colors
+	}
+
+	var prev *image.RGBA
+	for i, cur := range frames {
+		rect := cur.Bounds()
+		if prev != nil {
+			rect = changedRect(prev, cur)
+			if rect.Empty() {
+				g.Delay[len(g.Delay)-1] += delays[i]
+				continue
+			}
+			if clearsToTransparent(prev, cur, rect) {
+				// A kept frame cannot turn pixels transparent again, so
+				// the previous frame is redone in full and disposed to
+				// the background, and this one is drawn in full.
+				last := len(g.Image) - 1
+				g.Image[last] = encodeFrame(prev, nil, prev.Bounds(), shared)
+				g.Disposal[last] = gif.DisposalBackground
+				prev, rect = nil, cur.Bounds()
+			}
+		}
+		g.Image = append(g.Image, encodeFrame(cur, prev, rect, shared))
+		g.Delay = append(g.Delay, delays[i])
+		g.Disposal = append(g.Disposal, gif.DisposalNone)
+		prev = cur
+	}
+	g.Config = image.Config{Width: frames[0].Bounds().Dx(), Height: frames[0].Bounds().Dy()}
+	if shared != nil {
+		g.Config.ColorModel = shared
+	}
+	return gif.EncodeAll(w, g)
+}
+
+// timedFrames returns the frames to animate with their delays in hundredths
+// of a second, merging frames that would be shown for no time at all.
+//
+// Each frame accounts for the time since the previous one, or for opts.Delay
+// when the recording has no timing, scaled by the playback options.
+func (r *Recorder) timedFrames(opts *FrameOptions) ([]*image.RGBA, []int) {
+	n := len(r.frames)
+	at := r.playbackTimes(opts)
+	var frames []*image.RGBA
+	var delays []int
+	for i := range r.frames {
+		d := centis(opts.delay())
+		if i+1 < n {
+			// Differences of rounded times keep the total length exact.
+			d = centis(at[i+1]) - centis(at[i])
+			if d <= 0 {
+				continue
+			}
+		}
+		frames = append(frames, r.Frame(i, opts))
+		delays = append(delays, d)
+	}
+	return frames, delays
+}
+
+func centis(d time.Duration) int {
+	return int((d + 5*time.Millisecond) / (10 * time.Millisecond))
+}
+
+// gifColor maps a canvas pixel to its GIF color; ok is false for pixels
+// that are transparent.
+func gifColor(c color.RGBA) (color.RGBA, bool) {
+	if c.A == 0 {
+		return color.RGBA{}, false
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return color.RGBA{n.R, n.G, n.B, 255}, true
+}
+
+// collectColors returns a palette holding a transparent entry and every
+// color used by the frames, or false if that would exceed 256 entries.
+func collectColors(frames []*image.RGBA) (color.Palette, bool) {
+	seen := make(map[color.RGBA]bool)
+	pal := color.Palette{color.RGBA{}}
+	for _, f := range frames {
+		for i := 0; i < len(f.Pix); i += 4 {
+			c, ok := gifColor(color.RGBA{f.Pix[i], f.Pix[i+1], f.Pix[i+2], f.Pix[i+3]})
+			if !ok || seen[c] {
+				continue
+			}
+			if len(pal) == 256 {
+				return nil, false
+			}
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+	return pal, true
+}
+
+// changedRect returns the bounding box of the pixels that differ.
+func changedRect(a, b *image.RGBA) image.Rectangle {
+	bounds := b.Bounds()
+	r := image.Rectangle{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		ra := a.Pix[a.PixOffset(bounds.Min.X, y):a.PixOffset(bounds.Max.X, y)]
+		rb := b.Pix[b.PixOffset(bounds.Min.X, y):b.PixOffset(bounds.Max.X, y)]
+		if string(ra) == string(rb) {
+			continue
+		}
+		lo, hi := 0, len(ra)/4-1
+		for ; lo <= hi && string(ra[lo*4:lo*4+4]) == string(rb[lo*4:lo*4+4]); lo++ {
+		}
+		for ; hi >= lo && string(ra[hi*4:hi*4+4]) == string(rb[hi*4:hi*4+4]); hi-- {
+		}
+		r = r.Union(image.Rect(bounds.Min.X+lo, y, bounds.Min.X+hi+1, y+1))
+	}
+	return r
+}
+
+// clearsToTransparent reports whether any pixel inside rect goes from
+// visible in prev to transparent in cur.
+func clearsToTransparent(prev, cur *image.RGBA, rect image.Rectangle) bool {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if cur.RGBAAt(x, y).A == 0 && prev.RGBAAt(x, y).A != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encodeFrame converts rect of cur to a paletted image. Pixels equal to
+// prev (when given) are left transparent so the previous frame shows
+// through. Without a shared palette, one is built for this frame.
+func encodeFrame(cur, prev *image.RGBA, rect image.Rectangle, shared color.Palette) *image.Paletted {
+	pal := shared
+	if pal == nil {
+		pal = framePalette(cur, prev, rect)
+	}
+	index := make(map[color.RGBA]uint8, len(pal))
+	for i, c := range pal[1:] {
+		index[c.(color.RGBA)] = uint8(i + 1)
+	}
+
+	img := image.NewPaletted(rect, pal)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			px := cur.RGBAAt(x, y)
+			if prev != nil && prev.RGBAAt(x, y) == px {
+				continue // index 0 is transparent
+			}
+			c, ok := gifColor(px)
+			if !ok {
+				continue
+			}
+			i, found := index[c]
+			if !found {
+				i = uint8(pal[1:].Index(c) + 1)
+				index[c] = i
+			}
+			img.Pix[img.PixOffset(x, y)] = i
+		}
+	}
+	return img
+}
+
+// framePalette returns an exact palette for the pixels of rect that need
+// drawing, or the Plan 9 palette when there are too many colors.
+func framePalette(cur, prev *image.RGBA, rect image.Rectangle) color.Palette {
+	seen := make(map[color.RGBA]bool)
+	pal := color.Palette{color.RGBA{}}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			px := cur.RGBAAt(x, y)
+			if prev != nil && prev.RGBAAt(x, y) == px {
+				continue
+			}
+			c, ok := gifColor(px)
+			if !ok || seen[c] {
+				continue
+			}
+			if len(pal) == 256 {
+				return append(color.Palette{color.RGBA{}}, palette.Plan9[:255]...)
+			}
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+	return pal
+}
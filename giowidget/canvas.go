@@ -0,0 +1,106 @@
+// Package giowidget exposes a turtle canvas as a Gio widget that redraws
+// itself whenever the turtle draws. It is a separate module so the core
+// package does not depend on Gio.
+package giowidget
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/op/paint"
+	"gioui.org/widget"
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// Invalidator is implemented by *app.Window.
+type Invalidator interface {
+	Invalidate()
+}
+
+// Canvas is a Gio widget showing a turtle's canvas. The turtle may be driven
+// from any single goroutine; the widget copies the canvas after commands, at
+// most MaxFPS times per second, and asks the window to redraw.
+type Canvas struct {
+	// Fit controls how the canvas is scaled to the constraints.
+	// It defaults to widget.Contain.
+	Fit widget.Fit
+	// MaxFPS limits how often the canvas is copied for display. Zero means 60.
+	MaxFPS int
+
+	t    *gotuga.Turtle
+	inv  Invalidator
+	stop func()
+
+	mu       sync.Mutex
+	frame    *image.RGBA // latest copy of the canvas, guarded by mu
+	op       paint.ImageOp
+	fresh    bool
+	lastCopy time.Time
+}
+
+// New creates a widget for t that invalidates inv when t draws.
+// Call Close to stop tracking the turtle.
+func New(t *gotuga.Turtle, inv Invalidator) *Canvas {
+	c := &Canvas{t: t, inv: inv, Fit: widget.Contain}
+	c.Flush()
+	c.stop = t.Observe(func(gotuga.Command) {
+		c.mu.Lock()
+		due := time.Since(c.lastCopy) >= c.interval()
+		c.mu.Unlock()
+		if due {
+			c.Flush()
+		}
+	})
+	return c
+}
+
+// Flush copies the current canvas for display immediately. Call it from the
+// drawing goroutine after the last command so the final state is shown.
+func (c *Canvas) Flush() {
+	frame := image.NewRGBA(c.t.Image().Bounds())
+	copy(frame.Pix, c.t.Image().Pix)
+	c.mu.Lock()
+	c.frame = frame
+	c.fresh = true
+	c.lastCopy = time.Now()
+	c.mu.Unlock()
+	if c.inv != nil {
+		c.inv.Invalidate()
+	}
+}
+
+// Close detaches the widget from the turtle.
+func (c *Canvas) Close() {
+	if c.stop != nil {
+		c.stop()
+		c.stop = nil
+	}
+}
+
+func (c *Canvas) interval() time.Duration {
+	if c.MaxFPS <= 0 {
+		return time.Second / 60
+	}
+	return time.Second / time.Duration(c.MaxFPS)
+}
+
+// Layout draws the latest canvas copy.
+func (c *Canvas) Layout(gtx layout.Context) layout.Dimensions {
+	c.mu.Lock()
+	if c.fresh {
+		// ImageOps assume immutable images, so every copy gets a new op.
+		c.op = paint.NewImageOp(c.frame)
+		c.fresh = false
+	}
+	src := c.op
+	c.mu.Unlock()
+
+	return widget.Image{
+		Src:      src,
+		Fit:      c.Fit,
+		Position: layout.Center,
+		Scale:    1 / gtx.Metric.PxPerDp,
+	}.Layout(gtx)
+}
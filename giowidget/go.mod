@@ -0,0 +1,15 @@
+module github.com/Z6dev/GoTuga/giowidget
+
+go 1.24.5
+
+require github.com/Z6dev/GoTuga v0.0.0
+
+require (
+	gioui.org v0.10.3
+	github.com/go-text/typesetting v0.3.5 // indirect
+	golang.org/x/exp/shiny v0.0.0-20250408133849-7e4ce0ab07d0 // indirect
+	golang.org/x/image v0.26.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+)
+
+replace github.com/Z6dev/GoTuga => ../
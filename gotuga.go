@@ -1,26 +1,87 @@
 package gotuga
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"image/png"
+	"io"
 	"math"
-	"os"
+	"time"
 )
 
 type Turtle struct {
 	canvas     *image.RGBA
 	W, H       int
 	bg         color.Color
+	bgImage    image.Image // painted over bg, see SetBackgroundImage
 	x, y       float64
+	z          float64 // elevation, drawn in isometric mode
 	headingDeg float64
 	penDown    bool
 	penColor   color.Color
 	penWidth   float64
 
-	filling   bool
-	fillColor color.Color
-	fillPath  []image.Point // collected pixel coords
+	filling    bool
+	fillColor  color.Color
+	fillPoints [][2]float64 // collected logical coords
+	fillRule   FillRule
+
+	isometric    bool         // project (x, y, z) isometrically
+	edges        EdgeBehavior // what happens at the canvas edges
+	edgeCallback func(t *Turtle, x, y float64)
+	err          error // first error recorded, see Err
+
+	path *Path // the path being drawn, see Paths
+
+	clip         *image.Alpha    // see SetClipMask
+	srgbBlending bool            // blend in sRGB rather than linear light, see SetLinearBlending
+	colorSpace   ColorSpace      // see SetColorSpace
+	stroke       *image.Alpha    // pixels the current stroke has painted, see paint
+	strokeRect   image.Rectangle // bounds of those pixels
+	lineJoin     LineJoin        // see SetLineJoin
+	miterLimit   float64         // see SetMiterLimit
+	joinPrev     [4]float64      // the stroke's last segment, logical coords
+	joining      bool            // whether joinPrev is set
+	unstroked    bool            // moves leave no stroke, see Circle
+	quality      Quality         // see SetQuality
+	strictMath   bool            // see SetDeterministic
+	nonFinite    NonFiniteBehavior
+	profiler     func(Profile)       // see SetProfiler
+	profStart    time.Time           // when the running command started
+	painted      int                 // pixels painted by the running command
+	savedPNGs    map[string]savedPNG // see SavePNGIfDirty
+	syncSaves    bool                // see SetSyncSaves
+
+	stepBudget     int                               // see SetStepBudget
+	stepsFrom      int                               // steps when it was set
+	extraSteps     int                               // counted by CheckBudget since
+	timeBudget     time.Duration                     // see SetTimeBudget
+	deadline       time.Time                         // end of the time budget, zero for none
+	budgetCallback func(t *Turtle, err *BudgetError) // see SetBudgetCallback
+	spent          *BudgetError                      // refusing commands, nil if none
+
+	offCanvasCallback func(t *Turtle, m OffCanvasMove) // see SetOffCanvasCallback
+
+	annotating bool       // see SetAnnotations
+	waypoints  []waypoint // where commands left the turtle, see Annotations
+	trace      io.Writer  // see SetTrace
+
+	polying bool
+	poly    [][2]float64 // vertices captured by BeginPoly, logical coords
+
+	observers     []*observer
+	history       []Command // see SetRecordHistory
+	recordHistory bool
+	steps         int     // top-level commands run
+	depth         int     // nesting of commands currently executing
+	screen        *screen // shared with turtles spawned from this one
+
+	scale     float64       // canvas pixels per logical unit
+	fps       int           // frames per second in real-time mode, 0 when off
+	moveSpeed float64       // logical units per second in real-time mode
+	turnSpeed float64       // degrees per second in real-time mode
+	elapsed   time.Duration // motion time in real-time mode
 }
 
 // New creates a new turtle with a W×H canvas and a background color.
@@ -40,32 +101,104 @@ func New(W, H int, bg color.Color) *Turtle {
 		penDown:    true,
 		penColor:   color.Black,
 		penWidth:   2,
+		miterLimit: defaultMiterLimit,
+		screen:     new(screen),
+		scale:      1,
+		moveSpeed:  defaultMoveSpeed,
+		turnSpeed:  defaultTurnSpeed,
 	}
 	t.fillCanvas(bg)
+	t.screen.turtles = []*Turtle{t}
 	return t
 }
 
 // SavePNG writes the current canvas to a PNG file.
 func (t *Turtle) SavePNG(filename string) error {
-	f, err := os.Create(filename)
-	if err != nil {
-		return err
+	return writeFile(filename, t.syncSaves, func(w io.Writer) error {
+		defer t.profileEncode("png", t.canvas.Rect)()
+		return png.Encode(w, t.output())
+	})
+}
+
+// SaveRegionPNG writes the part of the canvas between the logical corners
+// (x, y) and (x+w, y+h) to a PNG file, for close-ups of a large drawing.
+// Pixels the region only partly covers are included; the region is cut to
+// the canvas.
+func (t *Turtle) SaveRegionPNG(filename string, x, y, w, h float64) error {
+	x0, y0 := t.CanvasPoint(math.Min(x, x+w), math.Max(y, y+h))
+	x1, y1 := t.CanvasPoint(math.Max(x, x+w), math.Min(y, y+h))
+	r := image.Rect(pixelFloor(x0), pixelFloor(y0), pixelCeil(x1), pixelCeil(y1))
+	img := t.output()
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return fmt.Errorf("gotuga: region %v×%v at (%v, %v) is off the canvas", w, h, x, y)
 	}
-	defer f.Close()
-	return png.Encode(f, t.canvas)
+	return writeFile(filename, t.syncSaves, func(w io.Writer) error {
+		defer t.profileEncode("png", r)()
+		return png.Encode(w, img.SubImage(r))
+	})
 }
 
 // Image returns the underlying RGBA canvas (read/write).
-func (t *Turtle) Image() *image.RGBA { return t.canvas }
+func (t *Turtle) Image() *image.RGBA { return t.pixels() }
+
+// Position returns the turtle's logical coordinates.
+func (t *Turtle) Position() (x, y float64) { return t.x, t.y }
+
+// Heading returns the turtle's direction in degrees, counterclockwise from east.
+func (t *Turtle) Heading() float64 { return t.headingDeg }
+
+// IsDown reports whether the pen is down.
+func (t *Turtle) IsDown() bool { return t.penDown }
+
+// Color returns the pen color.
+func (t *Turtle) Color() color.Color { return t.penColor }
+
+// Width returns the pen width.
+func (t *Turtle) Width() float64 { return t.penWidth }
+
+// Fill returns the fill color, or nil if none has been set.
+func (t *Turtle) Fill() color.Color { return t.fillColor }
+
+// Background returns the canvas background color.
+func (t *Turtle) Background() color.Color { return t.bg }
+
+// Scale returns the number of canvas pixels per logical unit.
+func (t *Turtle) Scale() float64 { return t.scale }
+
+// CanvasPoint returns where the logical point (x, y) lies on the canvas, in
+// unrounded pixel coordinates with y growing downwards.
+func (t *Turtle) CanvasPoint(x, y float64) (px, py float64) {
+	return float64(x*t.scale) + float64(t.W)/2, float64(t.H)/2 - float64(y*t.scale)
+}
 
 // Starts Drawing Mode of Turtle
-func (t *Turtle) PenUp() { t.penDown = false }
+func (t *Turtle) PenUp() {
+	if t.refuse("penup") {
+		return
+	}
+	defer t.track("penup")()
+	t.endStroke()
+	t.penDown = false
+}
 
 // Stops Drawing Mode of Turtle
-func (t *Turtle) PenDown() { t.penDown = true }
+func (t *Turtle) PenDown() {
+	if t.refuse("pendown") {
+		return
+	}
+	defer t.track("pendown")()
+	t.endStroke()
+	t.penDown = true
+}
 
 // Set pen Color to color.Color type from "image/color" package
 func (t *Turtle) SetColor(c color.Color) {
+	if t.refuse("color") {
+		return
+	}
+	defer t.trackColor("color", &t.penColor)()
+	t.endStroke()
 	if c != nil {
 		t.penColor = c
 	}
@@ -73,62 +206,158 @@ func (t *Turtle) SetColor(c color.Color) {
 
 // Sets the Thickness or Width of the Pen
 func (t *Turtle) SetWidth(w float64) {
+	if !t.finite("width", &w) {
+		return
+	}
+	if t.refuse("width") {
+		return
+	}
+	defer t.track("width", w)()
+	t.endStroke()
 	if w > 0 {
 		t.penWidth = w
 	}
 }
 
 // Set Turtle's Rotation towards (deg) Degrees
-func (t *Turtle) SetHeading(deg float64) { t.headingDeg = deg }
+func (t *Turtle) SetHeading(deg float64) {
+	if !t.finite("setheading", &deg) {
+		return
+	}
+	if t.refuse("setheading") {
+		return
+	}
+	defer t.track("setheading", deg)()
+	t.headingDeg = deg
+}
 
 // Turn Left (deg) Degrees
-func (t *Turtle) Left(deg float64) { t.headingDeg += deg }
+func (t *Turtle) Left(deg float64) {
+	if !t.finite("left", &deg) {
+		return
+	}
+	if t.refuse("left") {
+		return
+	}
+	defer t.track("left", deg)()
+	t.turn(deg)
+}
 
 // Turn Right (deg) Degrees
-func (t *Turtle) Right(deg float64) { t.headingDeg -= deg }
+func (t *Turtle) Right(deg float64) {
+	if !t.finite("right", &deg) {
+		return
+	}
+	if t.refuse("right") {
+		return
+	}
+	defer t.track("right", deg)()
+	t.turn(-deg)
+}
 
 // Go To (0, 0) and Reset Direction to 0 Degrees, Keeps the Canvas state.
-func (t *Turtle) Home() { t.GoTo(0, 0); t.headingDeg = 0 }
+func (t *Turtle) Home() {
+	if t.refuse("home") {
+		return
+	}
+	defer t.track("home")()
+	t.moveTo3(0, 0, 0)
+	t.headingDeg = 0
+}
 
 // Clear repaints the canvas with the background color but keeps turtle state.
-func (t *Turtle) Clear() { t.fillCanvas(t.bg) }
+func (t *Turtle) Clear() {
+	if t.refuse("clear") {
+		return
+	}
+	defer t.track("clear")()
+	t.fillCanvas(t.bg)
+	t.clearPaths()
+}
 
 // Reset clears the canvas and resets position/orientation/pen to defaults.
 func (t *Turtle) Reset() {
+	if t.refuse("reset") {
+		return
+	}
+	defer t.track("reset")()
 	t.fillCanvas(t.bg)
-	t.x, t.y = 0, 0
+	t.clearPaths()
+	t.x, t.y, t.z = 0, 0, 0
 	t.headingDeg = 0
 	t.penDown = true
 	t.penColor = color.Black
 	t.penWidth = 2
+	t.lineJoin = RoundJoin
+	t.miterLimit = defaultMiterLimit
+	t.err = nil
+	t.clip = nil
 }
 
 // Move Forward by (d) Steps
 func (t *Turtle) Forward(d float64) {
-	rad := t.headingDeg * math.Pi / 180
-	nx := t.x + d*math.Cos(rad)
-	ny := t.y + d*math.Sin(rad)
-	if t.penDown {
-		t.drawSegment(t.x, t.y, nx, ny, t.penWidth, t.penColor)
+	if !t.finite("forward", &d) {
+		return
 	}
-	t.recordFillVertex(nx, ny)
-	t.x, t.y = nx, ny
+	if t.refuse("forward") {
+		return
+	}
+	defer t.track("forward", d)()
+	if (t.edges == Bounce || t.edges == Wrap) && !t.isometric {
+		t.edgeMove(d)
+		return
+	}
+	t.moveTo(t.step(t.x, t.y, d))
 }
 
 // Move Backwards by (d) Steps
-func (t *Turtle) Backward(d float64) { t.Forward(-d) }
+func (t *Turtle) Backward(d float64) {
+	if !t.finite("backward", &d) {
+		return
+	}
+	if t.refuse("backward") {
+		return
+	}
+	defer t.track("backward", d)()
+	t.Forward(-d)
+}
 
 // GoTo moves to logical coords (x,y). If pen is down, draws a segment.
 func (t *Turtle) GoTo(x, y float64) {
-	if t.penDown {
-		t.drawSegment(t.x, t.y, x, y, t.penWidth, t.penColor)
+	if !t.finite("goto", &x, &y) {
+		return
+	}
+	if t.refuse("goto") {
+		return
+	}
+	defer t.track("goto", x, y)()
+	t.moveTo(x, y)
+}
+
+// JumpTo moves to logical coords (x,y) without drawing, leaving the pen up
+// or down as it was. It is recorded as the PenUp, GoTo and PenDown it
+// performs, none if the turtle is already there.
+func (t *Turtle) JumpTo(x, y float64) {
+	if t.x == x && t.y == y {
+		return
+	}
+	down := t.penDown
+	t.PenUp()
+	t.GoTo(x, y)
+	if down {
+		t.PenDown()
 	}
-	t.recordFillVertex(x, y)
-	t.x, t.y = x, y
 }
 
 // Shapes (drawn at current position/orientation)
 func (t *Turtle) Rect(w, h float64) {
+	if !t.finite("rect", &w, &h) {
+		return
+	}
+	if t.refuse("rect") {
+		return
+	}
+	defer t.track("rect", w, h)()
 	// Outline rectangle centered on the *path* starting corner (current pos)
 	// and aligned to current heading.
 	// We trace the perimeter and return to the start.
@@ -145,6 +374,13 @@ func (t *Turtle) Rect(w, h float64) {
 
 // Polygon draws an n-sided regular polygon with side length s.
 func (t *Turtle) Polygon(n int, side float64) {
+	if !t.finite("polygon", &side) {
+		return
+	}
+	if t.refuse("polygon") {
+		return
+	}
+	defer t.track("polygon", float64(n), side)()
 	if n < 3 {
 		return
 	}
@@ -157,35 +393,70 @@ func (t *Turtle) Polygon(n int, side float64) {
 	t.restoreSnapshot(orig)
 }
 
+// maxCircleSegments limits the segments of huge circles, whose chords are
+// still within a pixel of the circle up to a radius of about 10⁹ pixels.
+const maxCircleSegments = 1 << 16
+
 // Circle draws an approximate circle with radius r using small segments.
 func (t *Turtle) Circle(r float64) {
+	if !t.finite("circle", &r) {
+		return
+	}
+	if t.refuse("circle") {
+		return
+	}
+	defer t.track("circle", r)()
 	circ := 2 * math.Pi * math.Abs(r)
 	// segment length ~ 3 px (minimum 12 segments)
-	segments := int(math.Max(12, circ/3))
+	segments := int(math.Max(12, math.Min(maxCircleSegments, circ/3)))
 	angle := 360.0 / float64(segments)
 	// Shift center to the left of heading by r (turtle circle convention)
 	orig := t.stateSnapshot()
+	// A one-pixel pen draws the circle in one go after the walk, which
+	// then only moves the turtle and records the path.
+	ring := t.penDown && t.penWidth*t.scale <= 1 && t.quality != Best && !t.isometric && t.edges == Clip && t.fps == 0 &&
+		math.Abs(r)*t.scale <= float64(max(t.W, t.H))
+	if ring {
+		t.unstroked = true
+		defer func() { t.unstroked = false }()
+	}
 	// Walk the polyline approximation
 	stepLen := circ / float64(segments)
 	turn := angle
 	if r < 0 {
 		turn = -angle
 	}
-	for i := 0; i < segments; i++ {
+	stopped := false
+	for i := 0; i < segments && !stopped; i++ {
 		t.Forward(stepLen)
 		t.Left(turn)
+		stopped = t.outOfTime("circle")
 	}
 	t.restoreSnapshot(orig)
+	if ring && !stopped {
+		cos, sin := t.direction()
+		cx, cy := t.CanvasPoint(t.x-float64(r*sin), t.y+float64(r*cos))
+		t.drawRing(cx, cy, math.Abs(r)*t.scale, color.NRGBAModel.Convert(t.penColor).(color.NRGBA))
+	}
 }
 
 // BeginFill starts recording a polygon fill path
 func (t *Turtle) BeginFill() {
+	if t.refuse("beginfill") {
+		return
+	}
+	defer t.track("beginfill")()
 	t.filling = true
-	t.fillPath = nil
+	t.fillPoints = nil
+	t.recordFillVertex(t.project(t.x, t.y, t.z)) // fills start where the turtle is
 }
 
 // FillColor sets the fill color
 func (t *Turtle) FillColor(c color.Color) {
+	if t.refuse("fillcolor") {
+		return
+	}
+	defer t.trackColor("fillcolor", &t.fillColor)()
 	if c != nil {
 		t.fillColor = c
 	}
@@ -193,23 +464,32 @@ func (t *Turtle) FillColor(c color.Color) {
 
 // EndFill fills the collected polygon
 func (t *Turtle) EndFill() {
-	if !t.filling || len(t.fillPath) < 3 {
+	if t.refuse("endfill") {
+		return
+	}
+	defer t.track("endfill")()
+	if !t.filling || len(t.fillPoints) < 3 {
 		t.filling = false
-		t.fillPath = nil
+		t.fillPoints = nil
 		return
 	}
 
 	// Close polygon if needed
-	first := t.fillPath[0]
-	last := t.fillPath[len(t.fillPath)-1]
-	if first != last {
-		t.fillPath = append(t.fillPath, first)
+	pts := t.fillPoints
+	if pts[0] != pts[len(pts)-1] {
+		pts = append(pts, pts[0])
 	}
 
-	// Fill polygon
-	drawPolygon(t.canvas, t.fillPath, t.fillColor)
+	// Fill polygon, converting to pixels only now
+	px := make([][2]float64, len(pts))
+	for i, p := range pts {
+		px[i][0], px[i][1] = t.CanvasPoint(p[0], p[1])
+	}
+	t.endStroke()
+	t.painted += drawPolygon(t.pixels(), px, t.fillRule, t.fillColor, t.clip, t.blendLinear(), t.quality == Best)
+	t.retainShape(&Path{Points: pts, Fill: t.fillColor})
 
 	// Reset fill state
 	t.filling = false
-	t.fillPath = nil
+	t.fillPoints = nil
 }
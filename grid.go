@@ -0,0 +1,59 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// DrawGrid draws graph paper: one-pixel lines every spacing logical units,
+// aligned so that lines pass through the origin, with every major-th line
+// in majorColor and the rest in minorColor. A major of 0 or less draws only
+// minor lines; nil colors leave those lines out. The turtle does not move.
+func (t *Turtle) DrawGrid(spacing float64, major int, minorColor, majorColor color.Color) {
+	if !t.finite("grid", &spacing) {
+		return
+	}
+	if minorColor == nil {
+		minorColor = color.Transparent
+	}
+	if majorColor == nil {
+		majorColor = color.Transparent
+	}
+	args := append([]float64{spacing, float64(major)}, colorArgs(minorColor)...)
+	if t.refuse("grid") {
+		return
+	}
+	defer t.track("grid", append(args, colorArgs(majorColor)...)...)()
+	if spacing*t.scale < 2 {
+		return // finer than the pixels; it would fill the canvas
+	}
+	t.endStroke()
+	isMajor := func(k int) bool { return major > 0 && k%major == 0 }
+	t.gridLines(spacing, func(k int) bool { return !isMajor(k) }, minorColor)
+	t.gridLines(spacing, isMajor, majorColor)
+}
+
+// gridLines draws the vertical and horizontal lines k·spacing for which
+// keep(k) is true.
+func (t *Turtle) gridLines(spacing float64, keep func(k int) bool, col color.Color) {
+	src := &image.Uniform{C: col}
+	halfW := float64(t.W) / 2 / t.scale
+	halfH := float64(t.H) / 2 / t.scale
+	for k := int(math.Ceil(-halfW / spacing)); float64(k)*spacing <= halfW; k++ {
+		if keep(k) {
+			px, _ := t.mapToPixel(float64(k)*spacing, 0)
+			t.painted += drawClipped(t.pixels(), image.Rect(px, 0, px+1, t.H), src, image.Point{}, t.clip, t.blendLinear())
+			x := float64(k) * spacing
+			t.retainShape(&Path{Points: [][2]float64{{x, -halfH}, {x, halfH}}, Color: col, Width: 1 / t.scale})
+		}
+	}
+	for k := int(math.Ceil(-halfH / spacing)); float64(k)*spacing <= halfH; k++ {
+		if keep(k) {
+			_, py := t.mapToPixel(0, float64(k)*spacing)
+			t.painted += drawClipped(t.pixels(), image.Rect(0, py, t.W, py+1), src, image.Point{}, t.clip, t.blendLinear())
+			y := float64(k) * spacing
+			t.retainShape(&Path{Points: [][2]float64{{-halfW, y}, {halfW, y}}, Color: col, Width: 1 / t.scale})
+		}
+	}
+}
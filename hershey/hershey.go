@@ -0,0 +1,111 @@
+// Package hershey writes text in the Hershey Simplex font, a single-stroke
+// font drawn as turtle lines rather than stamped as an image, so text is
+// part of the turtle's paths and comes out of the SVG, G-code and plotter
+// exporters as lines a pen can follow.
+//
+//	w, _ := hershey.Measure("Hello", 20)
+//	t.Rect(w, 28) // a box under it
+//	hershey.Write(t, "Hello", 20, hershey.Left)
+//
+// Text runs along the turtle's heading, in the pen color and width. Only
+// printable ASCII is in the font; other characters are drawn as '?'.
+package hershey
+
+import (
+	"math"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// Font metrics in font units, for capitals 21 units tall.
+const (
+	capHeight = 21
+	ascent    = 25 // the tops of brackets and tall strokes
+	descent   = 7  // the bottoms of descenders
+	lineGap   = 32 // baseline to baseline
+)
+
+// Align places text along the heading relative to the turtle.
+type Align int
+
+const (
+	Left   Align = iota // text starts at the turtle
+	Center              // text is centered on the turtle
+	Right               // text ends at the turtle
+)
+
+// Measure returns the width of s with capitals size tall, and its height
+// from the top of the first line to the bottom of the last. Lines are
+// separated by "\n".
+func Measure(s string, size float64) (w, h float64) {
+	k := size / capHeight
+	lines := strings.Split(s, "\n")
+	for _, l := range lines {
+		w = math.Max(w, k*advance(l))
+	}
+	return w, k * (ascent + descent + float64(len(lines)-1)*lineGap)
+}
+
+// Write draws s with capitals size tall and the first line's baseline at
+// the turtle, whether or not the pen is down. Lines are separated by "\n"
+// and aligned with each other the same way. The turtle does not move.
+func Write(t *gotuga.Turtle, s string, size float64, align Align) {
+	k := size / capHeight
+	x0, y0 := t.Position()
+	heading, down := t.Heading(), t.IsDown()
+	sin, cos := math.Sincos(heading * math.Pi / 180)
+	// at places the font point (x, y), in font units, along the heading.
+	at := func(x, y float64) (float64, float64) {
+		x, y = k*x, k*y
+		return x0 + x*cos - y*sin, y0 + x*sin + y*cos
+	}
+	for i, l := range strings.Split(s, "\n") {
+		x := -advance(l) * float64(align) / 2
+		y := -float64(i) * lineGap
+		for _, r := range l {
+			g := glyph(r)
+			penUp := true
+			for j := 1; j+1 < len(g); j += 2 {
+				if g[j] == -1 && g[j+1] == -1 {
+					penUp = true
+					continue
+				}
+				if penUp {
+					t.PenUp()
+				}
+				t.GoTo(at(x+float64(g[j]), y+float64(g[j+1])))
+				if penUp {
+					t.PenDown()
+					penUp = false
+				}
+			}
+			x += float64(g[0])
+		}
+	}
+	t.PenUp()
+	t.GoTo(x0, y0)
+	t.SetHeading(heading)
+	if down {
+		t.PenDown()
+	}
+}
+
+// advance returns the width of a line of text in font units.
+func advance(l string) float64 {
+	w := 0
+	for _, r := range l {
+		w += int(glyph(r)[0])
+	}
+	return float64(w)
+}
+
+func glyph(r rune) []int8 {
+	if r == '\t' {
+		r = ' '
+	}
+	if r < ' ' || r > '~' {
+		r = '?'
+	}
+	return simplex[r-' ']
+}
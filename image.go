@@ -0,0 +1,112 @@
+package gotuga
+
+import (
+	"image"
+	"image/draw"
+	_ "image/gif" // register the decoders DecodeImage knows
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+)
+
+// LoadImage reads a PNG, JPEG or GIF file for SetBackgroundImage or
+// StampImage.
+func LoadImage(path string) (*image.RGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return DecodeImage(f)
+}
+
+// DecodeImage decodes a PNG, JPEG or GIF image (the first frame of an
+// animated GIF) for SetBackgroundImage or StampImage.
+func DecodeImage(r io.Reader) (*image.RGBA, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba, nil
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, b.Min, draw.Src)
+	return rgba, nil
+}
+
+// SetBackgroundImage clears the canvas to img, stretched to fit, and keeps
+// it as the background Clear and Reset repaint. A nil img goes back to the
+// plain background color. Images are recorded without their pixels, so
+// replaying the command only clears the canvas.
+func (t *Turtle) SetBackgroundImage(img image.Image) {
+	if t.refuse("bgimage") {
+		return
+	}
+	defer t.track("bgimage")()
+	t.bgImage = img
+	t.fillCanvas(t.bg)
+	t.clearPaths()
+}
+
+// StampImage draws img centered on the turtle, one image pixel per logical
+// unit, unrotated. Images are recorded as the image's size without its
+// pixels, so replaying the command does nothing.
+func (t *Turtle) StampImage(img image.Image) {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	if t.refuse("stampimage") {
+		return
+	}
+	defer t.track("stampimage", w, h)()
+	x, y := t.project(t.x, t.y, t.z)
+	x0, y0 := t.CanvasPoint(x-w/2, y+h/2)
+	x1, y1 := t.CanvasPoint(x+w/2, y-h/2)
+	t.endStroke()
+	t.painted += drawScaled(t.pixels(), x0, y0, x1, y1, img, t.clip, t.blendLinear())
+}
+
+// drawScaled draws src over the canvas rectangle from (x0, y0) to (x1, y1)
+// in pixels, sampling the nearest source pixel, clipped by clip if not nil,
+// blending in linear light if linear is set. It returns the number of
+// canvas pixels covered.
+func drawScaled(dst *image.RGBA, x0, y0, x1, y1 float64, src image.Image, clip *image.Alpha, linear bool) int {
+	b := src.Bounds()
+	if b.Empty() || x1 <= x0 || y1 <= y0 {
+		return 0
+	}
+	r := image.Rect(pixelRound(x0), pixelRound(y0), pixelRound(x1), pixelRound(y1))
+	if r.Dx() == b.Dx() && r.Dy() == b.Dy() {
+		return drawClipped(dst, r, src, b.Min, clip, linear)
+	}
+	r = r.Intersect(dst.Rect)
+	scaled := image.NewRGBA(r)
+	sx := float64(b.Dx()) / (x1 - x0)
+	sy := float64(b.Dy()) / (y1 - y0)
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		iy := min(b.Min.Y+int((float64(py)+0.5-y0)*sy), b.Max.Y-1)
+		for px := r.Min.X; px < r.Max.X; px++ {
+			ix := min(b.Min.X+int((float64(px)+0.5-x0)*sx), b.Max.X-1)
+			scaled.Set(px, py, src.At(ix, iy))
+		}
+	}
+	return drawClipped(dst, r, scaled, r.Min, clip, linear)
+}
+
+// drawClipped draws src over r of dst like draw.Draw, clipped by clip if
+// not nil, blending in linear light if linear is set. It returns the
+// number of pixels of dst in r.
+func drawClipped(dst *image.RGBA, r image.Rectangle, src image.Image, sp image.Point, clip *image.Alpha, linear bool) int {
+	switch {
+	case linear:
+		drawOver(dst, r, src, sp, clip, r.Min)
+	case clip == nil:
+		draw.Draw(dst, r, src, sp, draw.Over)
+	default:
+		draw.DrawMask(dst, r, src, sp, clip, r.Min, draw.Over)
+	}
+	r = r.Intersect(dst.Rect)
+	return r.Dx() * r.Dy()
+}
@@ -0,0 +1,56 @@
+// Package atomicfile saves files atomically, for gotuga and the packages
+// exporting its drawings.
+package atomicfile
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Write creates filename with what write writes, on a temporary file
+// beside it that is renamed over filename once complete. With sync, the
+// file and the rename are flushed to disk first. A file that is replaced
+// keeps its permissions.
+func Write(filename string, sync bool, write func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(filename)
+	f, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if err = write(f); err != nil {
+		return err
+	}
+	if sync {
+		if err = f.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(filename); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err = os.Chmod(f.Name(), mode); err != nil {
+		return err
+	}
+	if err = os.Rename(f.Name(), filename); err != nil {
+		return err
+	}
+	if sync {
+		// Some systems cannot sync directories; the file itself is safe.
+		if d, err := os.Open(dir); err == nil {
+			d.Sync()
+			d.Close()
+		}
+	}
+	return nil
+}
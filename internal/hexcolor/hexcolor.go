@@ -0,0 +1,25 @@
+// Package hexcolor parses colors written in hex, for gotuga's files and
+// the tools and servers reading colors from users.
+package hexcolor
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+)
+
+// Parse parses a color written as #rrggbb or #rrggbbaa, as session files
+// and command streams store them.
+func Parse(s string) (color.NRGBA, error) {
+	if len(s) == 7 || len(s) == 9 {
+		// ParseUint takes no sign or prefix in base 16, so any digit that
+		// is not hex is an error.
+		if v, err := strconv.ParseUint(s[1:], 16, 32); s[0] == '#' && err == nil {
+			if len(s) == 7 {
+				return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+			}
+			return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+		}
+	}
+	return color.NRGBA{}, fmt.Errorf("gotuga: invalid color %q, want #rrggbb or #rrggbbaa", s)
+}
@@ -0,0 +1,55 @@
+// Package polyline offsets polylines, for the exporters that cut or
+// extrude the turtle's paths.
+package polyline
+
+import "math"
+
+// Offset returns the polyline pts moved d to the left of the way
+// it runs, or to the right if d < 0: each edge is moved by d and the moved
+// edges meet in miters no longer than 4d. With closed, pts is a ring whose
+// last point is not repeated and its ends are joined too; otherwise the
+// ends are moved square to the first and last edges. Repeated points are
+// moved like their neighbours.
+func Offset(pts [][2]float64, d float64, closed bool) [][2]float64 {
+	n := len(pts)
+	if n < 2 {
+		return append([][2]float64(nil), pts...)
+	}
+	// left returns the unit normal to the left of the edge from a to b,
+	// or zero if the edge has no length.
+	left := func(a, b [2]float64) [2]float64 {
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		l := math.Hypot(dx, dy)
+		if l == 0 {
+			return [2]float64{}
+		}
+		return [2]float64{-dy / l, dx / l}
+	}
+	out := make([][2]float64, n)
+	for i, p := range pts {
+		var n1, n2 [2]float64
+		switch {
+		case closed:
+			n1, n2 = left(pts[(i+n-1)%n], p), left(p, pts[(i+1)%n])
+		case i == 0:
+			n1 = left(p, pts[1])
+		case i == n-1:
+			n1 = left(pts[i-1], p)
+		default:
+			n1, n2 = left(pts[i-1], p), left(p, pts[i+1])
+		}
+		if n1 == ([2]float64{}) {
+			n1 = n2
+		} else if n2 == ([2]float64{}) {
+			n2 = n1
+		}
+		// The miter is (n1+n2)/(1+n1·n2) offsets long, limited to 4.
+		m := [2]float64{n1[0] + n2[0], n1[1] + n2[1]}
+		f := 0.0
+		if ml := math.Hypot(m[0], m[1]); ml > 0 {
+			f = math.Min(1/(1+n1[0]*n2[0]+n1[1]*n2[1]), 4/ml)
+		}
+		out[i] = [2]float64{p[0] + m[0]*f*d, p[1] + m[1]*f*d}
+	}
+	return out
+}
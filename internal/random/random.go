@@ -0,0 +1,22 @@
+// Package random draws numbers from an optional source, for the packages
+// taking a *rand.Rand that may be nil.
+package random
+
+import "math/rand/v2"
+
+// IntN returns a random int in [0, n) from rng, or from the global source
+// if rng is nil. It panics if n <= 0.
+func IntN(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.IntN(n)
+	}
+	return rng.IntN(n)
+}
+
+// Float64 is like IntN for a float64 in [0, 1).
+func Float64(rng *rand.Rand) float64 {
+	if rng == nil {
+		return rand.Float64()
+	}
+	return rng.Float64()
+}
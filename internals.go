@@ -1,6 +1,7 @@
 package gotuga
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
@@ -21,98 +22,428 @@ func (t *Turtle) restoreSnapshot(s snapshot) {
 	t.headingDeg = s.headingDeg
 }
 
+// fillCanvas clears the canvas to c and the background image, if any.
+// The pixels are only painted when next used, see pixels, so clearing a
+// canvas that is cleared again before anything is drawn costs nothing;
+// a caller's buffer, which may be read directly, is painted at once.
 func (t *Turtle) fillCanvas(c color.Color) {
-	draw.Draw(t.canvas, t.canvas.Bounds(), &image.Uniform{C: c}, image.Point{}, draw.Src)
+	t.endStroke()
+	bgImage, linear := t.bgImage, t.blendLinear()
+	t.screen.clear = func(canvas *image.RGBA) {
+		fillRect(canvas, canvas.Bounds(), color.RGBAModel.Convert(c).(color.RGBA))
+		if bgImage != nil {
+			b := canvas.Bounds()
+			drawScaled(canvas, 0, 0, float64(b.Dx()), float64(b.Dy()), bgImage, nil, linear)
+		}
+	}
+	if t.screen.borrowed {
+		t.pixels()
+	}
+}
+
+// pixels returns the canvas for reading or drawing, first painting it if
+// it was cleared since it was last used.
+func (t *Turtle) pixels() *image.RGBA {
+	if clear := t.screen.clear; clear != nil {
+		t.screen.clear = nil
+		clear(t.canvas)
+	}
+	return t.canvas
 }
 
 // Map logical (x,y) where origin is center and +y up, to image pixel coords.
 func (t *Turtle) mapToPixel(x, y float64) (int, int) {
-	ix := int(math.Round(x + float64(t.W)/2))
-	iy := int(math.Round(float64(t.H)/2 - y))
-	return ix, iy
+	px, py := t.CanvasPoint(x, y)
+	return pixelRound(px), pixelRound(py)
 }
 
-// Draw a thick anti-aliased-ish segment by stamping discs along the path.
-// This is simple and dependency-free; good enough for turtle graphics.
+// drawSegment draws a thick segment with round ends, or flat ends and a
+// join to the previous segment for MiterJoin and BevelJoin, in logical
+// coordinates. Nothing is rounded to pixels on the way, so consecutive
+// segments meet exactly: a pixel is covered when its center is within
+// width/2 of the segment.
 func (t *Turtle) drawSegment(x0, y0, x1, y1 float64, width float64, col color.Color) {
-	dx := x1 - x0
-	dy := y1 - y0
-	dist := math.Hypot(dx, dy)
-	if dist == 0 {
-		t.stampDisc(x0, y0, width/2, col)
+	r := width / 2 * t.scale
+	if r <= 0 {
+		return
+	}
+	ax, ay := t.CanvasPoint(x0, y0)
+	bx, by := t.CanvasPoint(x1, y1)
+	// Long diagonals are drawn in pieces, so the boxes scanned stay close
+	// to the line.
+	// Only the part near the canvas is drawn, however far the segment goes.
+	m := r + 1
+	ax, ay, bx, by, ok := clipSegment(ax, ay, bx, by, -m, -m, float64(t.W)+m, float64(t.H)+m)
+	if !ok {
+		return
+	}
+	if t.quality == Fast {
+		t.stampSegment(ax, ay, bx, by, r, color.NRGBAModel.Convert(col).(color.NRGBA))
 		return
 	}
-	// One sample per pixel of distance, plus endpoints
-	steps := int(math.Ceil(dist)) + 1
-	for i := 0; i <= steps; i++ {
-		tt := float64(i) / float64(steps)
-		x := x0 + tt*dx
-		y := y0 + tt*dy
-		t.stampDisc(x, y, width/2, col)
+	dx, dy := bx-ax, by-ay
+	n := max(1, int(math.Ceil(math.Sqrt(float64(dx*dx)+float64(dy*dy))/32)))
+	for i := 0; i < n; i++ {
+		f0, f1 := float64(i)/float64(n), float64(i+1)/float64(n)
+		t.drawCapsule(ax+float64(f0*dx), ay+float64(f0*dy), ax+float64(f1*dx), ay+float64(f1*dy), r, col)
+	}
+	if t.lineJoin != RoundJoin {
+		t.joinSegment(x0, y0, x1, y1, r, col)
 	}
 }
 
-// stampDisc draws a filled circle of radius r in logical coordinates.
-func (t *Turtle) stampDisc(cx, cy, r float64, col color.Color) {
-	if r <= 0 {
-		return
+// clipSegment cuts the segment from (ax, ay) to (bx, by) to the rectangle
+// from (x0, y0) to (x1, y1) with the Liang–Barsky algorithm, reporting
+// whether any of it is left.
+func clipSegment(ax, ay, bx, by, x0, y0, x1, y1 float64) (float64, float64, float64, float64, bool) {
+	dx, dy := bx-ax, by-ay
+	t0, t1 := 0.0, 1.0
+	for _, e := range [4][2]float64{{-dx, ax - x0}, {dx, x1 - ax}, {-dy, ay - y0}, {dy, y1 - ay}} {
+		p, q := e[0], e[1]
+		if p == 0 {
+			if q < 0 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+		f := q / p
+		if p < 0 {
+			t0 = max(t0, f)
+		} else {
+			t1 = min(t1, f)
+		}
+	}
+	if t0 > t1 {
+		return 0, 0, 0, 0, false
+	}
+	if t1 < 1 {
+		bx, by = ax+float64(t1*dx), ay+float64(t1*dy)
+	}
+	if t0 > 0 {
+		ax, ay = ax+float64(t0*dx), ay+float64(t0*dy)
 	}
-	px, py := t.mapToPixel(cx, cy)
-	rr := int(math.Ceil(r))
-	minX := clamp(px-rr, 0, t.W-1)
-	maxX := clamp(px+rr, 0, t.W-1)
-	minY := clamp(py-rr, 0, t.H-1)
-	maxY := clamp(py+rr, 0, t.H-1)
+	return ax, ay, bx, by, true
+}
+
+// drawCapsule covers the pixels whose centers are within r of the segment
+// from (ax, ay) to (bx, by), in unrounded pixel coordinates. Unless the
+// line join is RoundJoin, the ends are flat: only pixels beside the
+// segment are covered. At Best quality, pixels are covered in part up to
+// half a pixel beyond r.
+func (t *Turtle) drawCapsule(ax, ay, bx, by, r float64, col color.Color) {
+	aa := t.quality == Best
+	e := r
+	if aa {
+		e += 0.5
+	}
+	minX := clamp(pixelFloor(min(ax, bx)-e), 0, t.W-1)
+	maxX := clamp(pixelCeil(max(ax, bx)+e), 0, t.W-1)
+	minY := clamp(pixelFloor(min(ay, by)-e), 0, t.H-1)
+	maxY := clamp(pixelCeil(max(ay, by)+e), 0, t.H-1)
 
-	r2 := r * r
+	nc := color.NRGBAModel.Convert(col).(color.NRGBA)
+	// Products are converted before they are added here and below, so
+	// that no architecture fuses them into multiply-adds, which round
+	// differently: coverage is the same everywhere.
+	dx, dy := bx-ax, by-ay
+	l2 := float64(dx*dx) + float64(dy*dy)
+	butt := t.lineJoin != RoundJoin
+	if butt && l2 == 0 {
+		return
+	}
+	e2 := e * e
+	// Opaque pens fill each row's pixels, which are side by side, at once.
+	solid := !aa && t.solid(nc)
 	for y := minY; y <= maxY; y++ {
+		lo, hi := maxX+1, minX-1
 		for x := minX; x <= maxX; x++ {
-			dx := float64(x-px) + 0.5
-			dy := float64(y-py) + 0.5
-			if dx*dx+dy*dy <= r2 {
-				t.canvas.Set(x, y, col)
+			// Pixel centers are nudged so that a line exactly between two
+			// rows or columns covers only one of them.
+			cx, cy := float64(x)+0.5+1e-9-ax, float64(y)+0.5+1e-9-ay
+			if l2 > 0 {
+				f := (float64(cx*dx) + float64(cy*dy)) / l2
+				if butt && (f < 0 || f > 1) {
+					continue
+				}
+				f = max(0, min(1, f))
+				cx, cy = cx-float64(f*dx), cy-float64(f*dy)
 			}
+			d2 := float64(cx*cx) + float64(cy*cy)
+			if d2 > e2 || !t.visible(x, y) {
+				continue
+			}
+			if solid {
+				lo, hi = min(lo, x), x
+			} else if aa {
+				t.paintCoverage(x, y, nc, coverage(r-math.Sqrt(d2)))
+			} else {
+				t.paint(x, y, nc)
+			}
+		}
+		if hi >= lo {
+			fillSpan(t.pixels(), lo, hi+1, y, color.RGBA{nc.R, nc.G, nc.B, 255})
+			t.painted += hi + 1 - lo
 		}
 	}
 }
 
-// Very simple polygon fill using draw.DrawMask
-func drawPolygon(img *image.RGBA, pts []image.Point, col color.Color) {
-	// Make a mask the same size
-	mask := image.NewAlpha(img.Bounds())
+// Very simple polygon fill using draw.DrawMask, clipped by clip if not nil,
+// blending in linear light if linear is set. pts are in unrounded pixel
+// coordinates, filled by rule, and anti-aliased if aa is set. It returns
+// the number of pixels painted.
+func drawPolygon(img *image.RGBA, pts [][2]float64, rule FillRule, col color.Color, clip *image.Alpha, linear, aa bool) int {
+	b := polygonBounds(pts).Intersect(img.Bounds())
+	if b.Empty() {
+		return 0
+	}
+	if c := color.RGBAModel.Convert(col).(color.RGBA); c.A == 255 && clip == nil && !aa {
+		return fillPolygon(img, b, pts, rule, c)
+	}
+	mask := getMask(b)
+	defer putMask(mask)
+	n := 0
+	if aa {
+		n = rasterizePolygonAA(mask, pts, rule)
+	} else {
+		n = rasterizePolygon(mask, pts, rule)
+	}
 
-	// Rasterize polygon edges into the mask
-	// (We’ll use a basic scanline fill here)
-	for y := mask.Bounds().Min.Y; y < mask.Bounds().Max.Y; y++ {
-		var intersections []int
-		for i := 0; i < len(pts); i++ {
-			j := (i + 1) % len(pts)
-			x0, y0 := pts[i].X, pts[i].Y
-			x1, y1 := pts[j].X, pts[j].Y
-			if (y0 <= y && y1 > y) || (y1 <= y && y0 > y) {
-				x := x0 + (y-y0)*(x1-x0)/(y1-y0)
-				intersections = append(intersections, x)
+	if clip != nil {
+		for i, a := range mask.Pix {
+			if a != 0 {
+				x, y := mask.Rect.Min.X+i%mask.Stride, mask.Rect.Min.Y+i/mask.Stride
+				mask.Pix[i] = uint8(uint32(a) * uint32(clip.AlphaAt(x, y).A) / 255)
 			}
 		}
-		// sort intersections
-		sort.Ints(intersections)
-		for i := 0; i+1 < len(intersections); i += 2 {
-			for x := intersections[i]; x <= intersections[i+1]; x++ {
-				mask.SetAlpha(x, y, color.Alpha{A: 255})
+	}
+
+	// Apply fill, only over the mask: the polygon's bounding box
+	if linear {
+		drawOver(img, b, &image.Uniform{C: col}, image.Point{}, mask, b.Min)
+		return n
+	}
+	draw.DrawMask(img, b, &image.Uniform{C: col}, image.Point{}, mask, b.Min, draw.Over)
+	return n
+}
+
+// fillPolygon sets the pixels of img in b inside the polygon pts to c, a
+// span at a time, and returns how many there are.
+func fillPolygon(img *image.RGBA, b image.Rectangle, pts [][2]float64, rule FillRule, c color.RGBA) int {
+	n := 0
+	var buf []crossing
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		buf = scanSpans(buf, pts, float64(y)+0.5, rule, func(xa, xb float64) {
+			x0 := max(pixelCeil(xa-0.5), b.Min.X)
+			x1 := min(pixelCeil(xb-0.5), b.Max.X)
+			fillSpan(img, x0, x1, y, c)
+			n += max(0, x1-x0)
+		})
+	}
+	return n
+}
+
+// polygonMask returns a mask with bounds r that is opaque inside the
+// polygon pts, in unrounded pixel coordinates, by the given fill rule. A
+// pixel is inside when its center is.
+func polygonMask(r image.Rectangle, pts [][2]float64, rule FillRule) *image.Alpha {
+	mask := image.NewAlpha(r)
+	rasterizePolygon(mask, pts, rule)
+	return mask
+}
+
+// rasterizePolygon makes the pixels of mask inside the polygon pts opaque,
+// as polygonMask describes, and returns how many there are. mask must
+// start transparent.
+func rasterizePolygon(mask *image.Alpha, pts [][2]float64, rule FillRule) int {
+	r := mask.Rect
+	n := 0
+	var buf []crossing
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		buf = scanSpans(buf, pts, float64(y)+0.5, rule, func(xa, xb float64) {
+			// Pixels whose centers lie between the crossings.
+			x0 := max(pixelCeil(xa-0.5), r.Min.X)
+			x1 := min(pixelCeil(xb-0.5), r.Max.X)
+			for x := x0; x < x1; x++ {
+				mask.Pix[mask.PixOffset(x, y)] = 255
+			}
+			n += max(0, x1-x0)
+		})
+	}
+	return n
+}
+
+// aaRows is how many lines through each pixel row rasterizePolygonAA
+// samples.
+const aaRows = 4
+
+// rasterizePolygonAA is rasterizePolygon with anti-aliasing: each pixel of
+// mask gets the fraction of it inside the polygon, measured exactly along
+// aaRows lines through it and averaged. It returns how many pixels are
+// covered at all.
+func rasterizePolygonAA(mask *image.Alpha, pts [][2]float64, rule FillRule) int {
+	r := mask.Rect
+	n := 0
+	var buf []crossing
+	acc := make([]float64, r.Dx())
+	lo, hi := float64(r.Min.X), float64(r.Max.X)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for s := 0; s < aaRows; s++ {
+			yc := float64(y) + (float64(s)+0.5)/aaRows
+			buf = scanSpans(buf, pts, yc, rule, func(xa, xb float64) {
+				xa, xb = max(xa, lo), min(xb, hi)
+				if xb <= xa {
+					return
+				}
+				ia, ib := int(math.Floor(xa)), int(math.Floor(xb))
+				if ia == ib {
+					acc[ia-r.Min.X] += xb - xa
+					return
+				}
+				acc[ia-r.Min.X] += float64(ia+1) - xa
+				for x := ia + 1; x < ib; x++ {
+					acc[x-r.Min.X]++
+				}
+				if ib < r.Max.X {
+					acc[ib-r.Min.X] += xb - float64(ib)
+				}
+			})
+		}
+		row := mask.Pix[mask.PixOffset(r.Min.X, y):][:r.Dx()]
+		for i, a := range acc {
+			row[i] = uint8(math.Round(255 * math.Min(1, a/aaRows)))
+			if row[i] != 0 {
+				n++
 			}
+			acc[i] = 0
+		}
+	}
+	return n
+}
+
+// crossing is where an edge of a polygon crosses a scanline.
+type crossing struct {
+	x   float64
+	dir int // +1 for an edge going up the canvas, -1 down
+}
+
+// scanSpans calls span with the ends of each stretch of the line y = yc
+// inside the polygon pts by the fill rule, counting how many times the
+// edges wind around it. It returns buf, reused for the crossings.
+func scanSpans(buf []crossing, pts [][2]float64, yc float64, rule FillRule, span func(x0, x1 float64)) []crossing {
+	crossings := buf[:0]
+	for i := 0; i < len(pts); i++ {
+		j := (i + 1) % len(pts)
+		x0, y0 := pts[i][0], pts[i][1]
+		x1, y1 := pts[j][0], pts[j][1]
+		if (y0 <= yc && y1 > yc) || (y1 <= yc && y0 > yc) {
+			dir := 1
+			if y1 > y0 {
+				dir = -1
+			}
+			crossings = append(crossings, crossing{x0 + (yc-y0)*(x1-x0)/(y1-y0), dir})
+		}
+	}
+	sort.Slice(crossings, func(a, b int) bool { return crossings[a].x < crossings[b].x })
+	winding := 0
+	for i := 0; i+1 < len(crossings); i++ {
+		if rule == EvenOdd {
+			winding ^= 1
+		} else {
+			winding += crossings[i].dir
+		}
+		if winding != 0 {
+			span(crossings[i].x, crossings[i+1].x)
+		}
+	}
+	return crossings
+}
+
+// moveTo moves the turtle in a straight line to (x, y), drawing if the pen
+// is down. In real-time mode the move is spread over frames.
+func (t *Turtle) moveTo(x, y float64) {
+	t.moveTo3(x, y, t.z)
+}
+
+// moveTo3 is moveTo with an elevation, which only shows in isometric mode.
+func (t *Turtle) moveTo3(x, y, z float64) {
+	ax, ay := t.project(t.x, t.y, t.z)
+	bx, by := t.project(x, y, z)
+	ok := t.allowMove(bx, by)
+	t.reportOffCanvas(ax, ay, bx, by)
+	if !ok {
+		return
+	}
+	x0, y0, z0 := t.x, t.y, t.z
+	dist := math.Sqrt((x-x0)*(x-x0) + (y-y0)*(y-y0) + (z-z0)*(z-z0))
+	t.advance(t.moveDuration(dist), func(f float64) {
+		nx, ny, nz := x0+float64(f*(x-x0)), y0+float64(f*(y-y0)), z0+float64(f*(z-z0))
+		if f == 1 {
+			nx, ny, nz = x, y, z
+		}
+		if t.penDown && !t.unstroked {
+			ax, ay := t.project(t.x, t.y, t.z)
+			bx, by := t.project(nx, ny, nz)
+			t.drawSegment(ax, ay, bx, by, t.penWidth, t.penColor)
 		}
+		t.x, t.y, t.z = nx, ny, nz
+	})
+	if t.penDown {
+		ax, ay := t.project(x0, y0, z0)
+		bx, by := t.project(x, y, z)
+		t.retain(ax, ay, bx, by)
 	}
+	t.recordFillVertex(t.project(x, y, z))
+	if t.polying {
+		t.poly = append(t.poly, [2]float64{x, y})
+	}
+}
 
-	// Apply fill
-	draw.DrawMask(img, img.Bounds(), &image.Uniform{C: col}, image.Point{}, mask, image.Point{}, draw.Over)
+// turn rotates the heading by deg (counterclockwise when positive).
+func (t *Turtle) turn(deg float64) {
+	h0 := t.headingDeg
+	t.advance(t.turnDuration(deg), func(f float64) {
+		t.headingDeg = h0 + f*deg
+	})
 }
 
-// recordFillVertex adds a vertex if filling is active
+// recordFillVertex adds a vertex, in logical coordinates, if filling is
+// active
 func (t *Turtle) recordFillVertex(x, y float64) {
 	if t.filling {
-		px, py := t.mapToPixel(x, y)
-		t.fillPath = append(t.fillPath, image.Pt(px, py))
+		t.fillPoints = append(t.fillPoints, [2]float64{x, y})
+	}
+}
+
+// cloneRGBA returns a deep copy of img.
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	c := image.NewRGBA(img.Bounds())
+	copy(c.Pix, img.Pix)
+	return c
+}
+
+// hexColor formats c as #rrggbbaa (non-premultiplied).
+func hexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+// maxPixel bounds the ints that pixel coordinates are converted to.
+// Converting a float64 beyond the range of int is undefined in Go, so
+// far-off points are pulled in to ±maxPixel first: far off any canvas,
+// yet leaving room for image.Rectangle arithmetic on 32-bit platforms.
+const maxPixel = 1 << 29
+
+// pixelFloor, pixelCeil and pixelRound convert a pixel coordinate to an
+// int no further than maxPixel from 0. NaN gives 0.
+func pixelFloor(v float64) int { return pixelInt(math.Floor(v)) }
+func pixelCeil(v float64) int  { return pixelInt(math.Ceil(v)) }
+func pixelRound(v float64) int { return pixelInt(math.Round(v)) }
+
+func pixelInt(v float64) int {
+	if math.IsNaN(v) {
+		return 0
 	}
+	return int(math.Max(-maxPixel, math.Min(maxPixel, v)))
 }
 
 func clamp(v, lo, hi int) int {
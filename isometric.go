@@ -0,0 +1,69 @@
+package gotuga
+
+// In isometric mode the turtle moves through (x, y, z) space and is drawn in
+// isometric projection: Forward and GoTo move across the ground, Up and Down
+// change the elevation z. Heading 0 (+x) runs up and to the right on the
+// canvas and heading 90 (+y) up and to the left, both 30° above horizontal;
+// +z runs straight up. Position and the edge behaviors Bounce and Wrap
+// work on the ground coordinates, which do not match the canvas, so Bounce
+// and Wrap are ignored in isometric mode.
+//
+//	t.SetIsometric(true)
+//	for i := 0; i < 5; i++ { // a staircase
+//		t.Up(20)
+//		t.Forward(20)
+//	}
+
+// SetIsometric turns isometric mode on or off. What is already drawn is not
+// changed.
+func (t *Turtle) SetIsometric(on bool) {
+	var arg float64
+	if on {
+		arg = 1
+	}
+	if t.refuse("isometric") {
+		return
+	}
+	defer t.track("isometric", arg)()
+	t.isometric = on
+}
+
+// Isometric reports whether isometric mode is on.
+func (t *Turtle) Isometric() bool { return t.isometric }
+
+// Up raises the turtle by d, drawing a vertical line if the pen is down.
+func (t *Turtle) Up(d float64) {
+	if !t.finite("up", &d) {
+		return
+	}
+	if t.refuse("up") {
+		return
+	}
+	defer t.track("up", d)()
+	t.moveTo3(t.x, t.y, t.z+d)
+}
+
+// Down lowers the turtle by d.
+func (t *Turtle) Down(d float64) {
+	if !t.finite("down", &d) {
+		return
+	}
+	if t.refuse("down") {
+		return
+	}
+	defer t.track("down", d)()
+	t.moveTo3(t.x, t.y, t.z-d)
+}
+
+// Elevation returns the turtle's z coordinate.
+func (t *Turtle) Elevation() float64 { return t.z }
+
+// project maps a point to the canvas plane: unchanged outside isometric
+// mode, where z is ignored.
+func (t *Turtle) project(x, y, z float64) (float64, float64) {
+	if !t.isometric {
+		return x, y
+	}
+	const cos30, sin30 = 0.8660254037844386, 0.5
+	return (x - y) * cos30, (x+y)*sin30 + z
+}
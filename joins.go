@@ -0,0 +1,115 @@
+package gotuga
+
+import (
+	"image/color"
+	"math"
+)
+
+// LineJoin is the shape of the corners where a stroke turns.
+type LineJoin int
+
+const (
+	RoundJoin LineJoin = iota // round corners and ends, the turtle's usual pen
+	MiterJoin                 // sharp corners, beveled past the miter limit
+	BevelJoin                 // corners cut off square
+)
+
+const defaultMiterLimit = 4 // as in SVG and the HTML canvas
+
+// SetLineJoin sets the shape of stroke corners. MiterJoin and BevelJoin
+// also give strokes flat ends that stop at their end points, like SVG's
+// default butt caps, so a square drawn with them has crisp corners.
+func (t *Turtle) SetLineJoin(j LineJoin) {
+	if t.refuse("linejoin") {
+		return
+	}
+	defer t.track("linejoin", float64(j))()
+	t.endStroke()
+	t.lineJoin = j
+}
+
+// LineJoin returns the shape of stroke corners.
+func (t *Turtle) LineJoin() LineJoin { return t.lineJoin }
+
+// SetMiterLimit sets how far a MiterJoin corner may reach, as a multiple
+// of the pen width: sharper corners, whose miters would be longer, are
+// beveled instead, so acute turns in thick strokes don't grow long spikes.
+// The default is 4, which bevels turns sharper than about 29°; limits
+// below 1 are ignored.
+func (t *Turtle) SetMiterLimit(limit float64) {
+	if t.refuse("miterlimit") {
+		return
+	}
+	defer t.track("miterlimit", limit)()
+	if limit >= 1 {
+		t.miterLimit = limit
+	}
+}
+
+// MiterLimit returns the miter limit.
+func (t *Turtle) MiterLimit() float64 { return t.miterLimit }
+
+// joinSegment draws the join between the previous segment of the stroke
+// and the one from (x0, y0) to (x1, y1), in logical coordinates, if they
+// meet, and remembers the new segment for the next join.
+func (t *Turtle) joinSegment(x0, y0, x1, y1, r float64, col color.Color) {
+	prev, ok := t.joinPrev, t.joining
+	t.joinPrev = [4]float64{x0, y0, x1, y1}
+	t.joining = x0 != x1 || y0 != y1
+	if !ok || !t.joining || prev[2] != x0 || prev[3] != y0 {
+		return
+	}
+	px, py := t.CanvasPoint(x0, y0)
+	ax, ay := t.CanvasPoint(prev[0], prev[1])
+	bx, by := t.CanvasPoint(x1, y1)
+	u1x, u1y := unit(px-ax, py-ay)
+	u2x, u2y := unit(bx-px, by-py)
+	cross := u1x*u2y - u1y*u2x
+	if math.Abs(cross) < 1e-9 && u1x*u2x+u1y*u2y > 0 {
+		return // straight on
+	}
+	// Normals on the outside of the turn.
+	s := 1.0
+	if cross > 0 {
+		s = -1
+	}
+	n1x, n1y := -s*u1y, s*u1x
+	n2x, n2y := -s*u2y, s*u2x
+	pts := [][2]float64{{px, py}, {px + r*n1x, py + r*n1y}, {px + r*n2x, py + r*n2y}}
+	if d := 1 + n1x*n2x + n1y*n2y; t.lineJoin == MiterJoin && d > 0 {
+		// The miter tip is (n1+n2)/(1+n1·n2) radii from the corner.
+		mx, my := (n1x+n2x)/d, (n1y+n2y)/d
+		if math.Hypot(mx, my) <= t.miterLimit {
+			pts = [][2]float64{pts[0], pts[1], {px + r*mx, py + r*my}, pts[2]}
+		}
+	}
+
+	b := polygonBounds(pts).Intersect(t.canvas.Rect)
+	if b.Empty() {
+		return
+	}
+	nc := color.NRGBAModel.Convert(col).(color.NRGBA)
+	mask := getMask(b)
+	defer putMask(mask)
+	if t.quality == Best {
+		rasterizePolygonAA(mask, pts, NonZero)
+	} else {
+		rasterizePolygon(mask, pts, NonZero)
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if a := mask.Pix[mask.PixOffset(x, y)]; a != 0 && t.visible(x, y) {
+				t.paintCoverage(x, y, nc, a)
+			}
+		}
+	}
+}
+
+// unit returns (x, y) scaled to length 1.
+func unit(x, y float64) (float64, float64) {
+	l := math.Hypot(x, y)
+	if l == 0 {
+		return 0, 0
+	}
+	return x / l, y / l
+}
@@ -0,0 +1,58 @@
+package gotuga
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Keyframe is the pose and pen of one recorded turtle at a point in
+// recording time. Playing keyframes back with linear interpolation of
+// position and heading reproduces the turtle's motion.
+type Keyframe struct {
+	Time    float64 `json:"t"`      // recording time in seconds
+	Turtle  int     `json:"turtle"` // 0 for the recorded turtle, then in Follow order
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Heading float64 `json:"heading"` // degrees, counterclockwise from east
+	PenDown bool    `json:"penDown"`
+	Color   string  `json:"color"` // pen color as #rrggbbaa
+	Width   float64 `json:"width"`
+}
+
+// Keyframes returns the recorded motion of every followed turtle, ordered
+// by time. A keyframe is stored whenever a turtle's pose or pen changes;
+// outside real-time mode and Advance, all keyframes share time zero.
+func (r *Recorder) Keyframes() []Keyframe { return r.keys }
+
+// WriteKeyframes writes the keyframes as a JSON array.
+func (r *Recorder) WriteKeyframes(w io.Writer) error {
+	keys := r.keys
+	if keys == nil {
+		keys = []Keyframe{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(keys)
+}
+
+// keyframe stores the current state of turtle id if it changed.
+func (r *Recorder) keyframe(id int, t *Turtle) {
+	k := Keyframe{
+		Time:    r.now.Seconds(),
+		Turtle:  id,
+		X:       t.x,
+		Y:       t.y,
+		Heading: t.headingDeg,
+		PenDown: t.penDown,
+		Color:   hexColor(t.penColor),
+		Width:   t.penWidth,
+	}
+	if r.lastKey == nil {
+		r.lastKey = make(map[int]int)
+	}
+	if i, ok := r.lastKey[id]; ok && r.keys[i] == k {
+		return
+	}
+	r.lastKey[id] = len(r.keys)
+	r.keys = append(r.keys, k)
+}
@@ -0,0 +1,264 @@
+// Package laser exports turtle drawings for laser cutters, as SVG in
+// millimetres that LightBurn, Inkscape and similar tools import with the
+// right size. Strokes are grouped into a layer per pen color, each tagged
+// as cut, score or engrave, and closed cut outlines can be offset by half
+// the beam's kerf so parts come out the size they were drawn.
+//
+//	err := laser.Save("box.svg", t, &laser.Options{
+//		Scale: 0.5, // mm per logical unit
+//		Kerf:  0.2,
+//		Layers: map[color.NRGBA]laser.Layer{
+//			{255, 0, 0, 255}: {Name: "cut", Mode: laser.Cut},
+//			{0, 0, 255, 255}: {Name: "score", Mode: laser.Score},
+//		},
+//	})
+//
+// The drawing is read from the turtle's retained paths, so turn retention
+// on with t.SetRetainPaths(true) before drawing.
+package laser
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/atomicfile"
+	"github.com/Z6dev/GoTuga/internal/polyline"
+)
+
+// Mode says what the laser does with a layer.
+type Mode int
+
+const (
+	Cut     Mode = iota // cut through along the lines
+	Score               // mark the lines without cutting through
+	Engrave             // burn the filled areas
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Score:
+		return "score"
+	case Engrave:
+		return "engrave"
+	}
+	return "cut"
+}
+
+// Layer names a pen color's layer and says what to do with it.
+type Layer struct {
+	Name string
+	Mode Mode
+}
+
+// Options configures the export. The zero value is usable: every pen color
+// becomes a cut layer named after the color, and fills are engraved.
+type Options struct {
+	Scale  float64               // millimetres per logical unit, defaults to 0.25
+	Kerf   float64               // width the beam burns away, in mm, 0 for no offsetting
+	Layers map[color.NRGBA]Layer // layer for each pen or fill color
+}
+
+// Save writes t's drawing to a file; see Write.
+func Save(filename string, t *gotuga.Turtle, opts *Options) error {
+	return atomicfile.Write(filename, t.SyncSaves(), func(w io.Writer) error { return Write(w, t, opts) })
+}
+
+// shape is a path on its way to a layer, in millimetres with y down.
+type shape struct {
+	pts    [][2]float64
+	filled bool
+}
+
+type layer struct {
+	Layer
+	color  color.NRGBA
+	shapes []shape
+}
+
+// Write writes t's retained paths (see gotuga.Turtle.Paths) as an SVG with
+// one group per layer, in the order the layers were first drawn in. Lines
+// are drawn as hairlines in their pen color, so tools that map colors to
+// laser settings see one color per layer; fills are filled areas. With a
+// kerf, closed lines on cut layers are moved outwards by half of it, or
+// inwards for holes inside other closed cut lines.
+func Write(w io.Writer, t *gotuga.Turtle, opts *Options) error {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.Scale <= 0 {
+		o.Scale = 0.25
+	}
+
+	var layers []*layer
+	byColor := make(map[color.NRGBA]*layer)
+	for _, p := range t.Paths() {
+		c, filled := p.Color, false
+		if p.Fill != nil {
+			c, filled = p.Fill, true
+		}
+		if c == nil || len(p.Points) < 2 {
+			continue
+		}
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		l := byColor[n]
+		if l == nil {
+			l = &layer{color: n}
+			if named, ok := o.Layers[n]; ok {
+				l.Layer = named
+			} else {
+				l.Name = hex(n)
+				if filled {
+					l.Mode = Engrave
+				}
+			}
+			byColor[n] = l
+			layers = append(layers, l)
+		}
+		pts := make([][2]float64, len(p.Points))
+		for i, pt := range p.Points {
+			x, y := t.CanvasPoint(pt[0], pt[1])
+			pts[i] = [2]float64{x / t.Scale() * o.Scale, y / t.Scale() * o.Scale}
+		}
+		l.shapes = append(l.shapes, shape{pts, filled})
+	}
+	if o.Kerf > 0 {
+		for _, l := range layers {
+			if l.Mode == Cut {
+				offsetOutlines(l.shapes, o.Kerf/2)
+			}
+		}
+	}
+
+	wmm, hmm := float64(t.W)/t.Scale()*o.Scale, float64(t.H)/t.Scale()*o.Scale
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape" width="%smm" height="%smm" viewBox="0 0 %s %s">`+"\n",
+		num(wmm), num(hmm), num(wmm), num(hmm))
+	ids := make(map[string]int)
+	for _, l := range layers {
+		id := xmlID(l.Name)
+		if ids[id]++; ids[id] > 1 {
+			id += "-" + strconv.Itoa(ids[id])
+		}
+		fmt.Fprintf(bw, `<g id="%s" inkscape:groupmode="layer" inkscape:label="%s" data-mode="%s">`+"\n", id, escape(l.Name), l.Mode)
+		for _, s := range l.shapes {
+			var d strings.Builder
+			for i, pt := range s.pts {
+				if i == 0 {
+					d.WriteString("M")
+				} else {
+					d.WriteString(" L")
+				}
+				d.WriteString(num(pt[0]) + " " + num(pt[1]))
+			}
+			if s.filled || l.Mode == Engrave {
+				fmt.Fprintf(bw, `<path d="%s Z" fill="%s" stroke="none"/>`+"\n", d.String(), hex(l.color))
+			} else {
+				fmt.Fprintf(bw, `<path d="%s" fill="none" stroke="%s" stroke-width="0.1"/>`+"\n", d.String(), hex(l.color))
+			}
+		}
+		bw.WriteString("</g>\n")
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// offsetOutlines moves the closed, unfilled shapes outwards by d, or
+// inwards for those inside an odd number of the others.
+func offsetOutlines(shapes []shape, d float64) {
+	closed := func(s shape) bool {
+		n := len(s.pts)
+		return !s.filled && n >= 4 && math.Hypot(s.pts[0][0]-s.pts[n-1][0], s.pts[0][1]-s.pts[n-1][1]) < 1e-6
+	}
+	var outlines []int
+	for i, s := range shapes {
+		if closed(s) {
+			outlines = append(outlines, i)
+		}
+	}
+	// Offset every outline before replacing any, so the nesting tests see
+	// the drawn geometry.
+	offset := make([][][2]float64, len(outlines))
+	for k, i := range outlines {
+		depth := 0
+		for _, j := range outlines {
+			if j != i && inside(shapes[i].pts[0], shapes[j].pts) {
+				depth++
+			}
+		}
+		dist := d
+		if depth%2 == 1 {
+			dist = -d
+		}
+		offset[k] = offsetPolygon(shapes[i].pts, dist)
+	}
+	for k, i := range outlines {
+		shapes[i].pts = offset[k]
+	}
+}
+
+// offsetPolygon moves each edge of the closed polygon pts, whose last
+// point repeats its first, outwards by d (inwards if d < 0), joining the
+// moved edges with miters no longer than 4d.
+func offsetPolygon(pts [][2]float64, d float64) [][2]float64 {
+	ring := pts[:len(pts)-1]
+	n := len(ring)
+	// With y down, a positive area means clockwise on screen, where the
+	// right of the way the ring runs is outwards.
+	area := 0.0
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%n]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+	if area < 0 {
+		d = -d
+	}
+	out := polyline.Offset(ring, -d, true)
+	return append(out, out[0])
+}
+
+// inside reports whether p is inside the polygon pts, by the even-odd rule.
+func inside(p [2]float64, pts [][2]float64) bool {
+	in := false
+	for i, j := 0, len(pts)-1; i < len(pts); j, i = i, i+1 {
+		a, b := pts[i], pts[j]
+		if (a[1] > p[1]) != (b[1] > p[1]) && p[0] < (b[0]-a[0])*(p[1]-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	return in
+}
+
+func hex(c color.NRGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+
+// xmlID turns a layer name into a usable id.
+func xmlID(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return "layer-" + b.String()
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// num formats a length compactly, to a thousandth of a millimetre.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
@@ -0,0 +1,198 @@
+package logo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	wordToken       tokenKind = iota // FORWARD, repeat, ...
+	numberToken                      // 100, -2.5
+	quotedToken                      // "name
+	variableToken                    // :name
+	infixToken                       // + - * / = < > <= >= <>
+	openToken                        // [
+	closeToken                       // ]
+	openParenToken                   // (
+	closeParenToken                  // )
+)
+
+type token struct {
+	kind tokenKind
+	text string // as written
+	num  float64
+	line int
+}
+
+// name returns the word of a quoted or variable token without its prefix.
+func (tk token) name() string {
+	if tk.kind == quotedToken || tk.kind == variableToken {
+		return tk.text[1:]
+	}
+	return tk.text
+}
+
+// delimiters end a word.
+const delimiters = "[]();+-*/=<>"
+
+// lex splits Logo source into tokens. Comments run from ';' to the end of
+// the line.
+//
+// As in Berkeley Logo, a '-' right before a number and after a space is a
+// negative sign, so "fd -50" moves backwards while ":n-1" and ":n - 1"
+// subtract.
+func lex(src string) []token {
+	var toks []token
+	line := 1
+	rs := []rune(src)
+	add := func(kind tokenKind, text string) {
+		toks = append(toks, token{kind: kind, text: text, line: line})
+	}
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == ';':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '[':
+			add(openToken, "[")
+			i++
+		case r == ']':
+			add(closeToken, "]")
+			i++
+		case r == '(':
+			add(openParenToken, "(")
+			i++
+		case r == ')':
+			add(closeParenToken, ")")
+			i++
+		case r == '-' && negativeSign(rs, i):
+			j := i + 1
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && !strings.ContainsRune(delimiters, rs[j]) {
+				j++
+			}
+			text := string(rs[i:j])
+			n, ok := parseNumber(text)
+			if !ok {
+				add(infixToken, "-")
+				i++
+				continue
+			}
+			toks = append(toks, token{kind: numberToken, text: text, num: n, line: line})
+			i = j
+		case strings.ContainsRune("+-*/=", r):
+			add(infixToken, string(r))
+			i++
+		case r == '<' || r == '>':
+			if i+1 < len(rs) && (rs[i+1] == '=' || r == '<' && rs[i+1] == '>') {
+				add(infixToken, string(rs[i:i+2]))
+				i += 2
+			} else {
+				add(infixToken, string(r))
+				i++
+			}
+		default:
+			start := i
+			i++
+			// Quoted words run to the next space or bracket only, so "a+b
+			// is one word.
+			quoted := r == '"'
+			for i < len(rs) && !unicode.IsSpace(rs[i]) {
+				if quoted && strings.ContainsRune("[]()", rs[i]) || !quoted && strings.ContainsRune(delimiters, rs[i]) {
+					break
+				}
+				i++
+			}
+			text := string(rs[start:i])
+			switch n, ok := parseNumber(text); {
+			case quoted:
+				add(quotedToken, text)
+			case r == ':':
+				add(variableToken, text)
+			case ok:
+				toks = append(toks, token{kind: numberToken, text: text, num: n, line: line})
+			default:
+				add(wordToken, text)
+			}
+		}
+	}
+	return toks
+}
+
+// parseNumber parses a decimal numeric literal such as 12, -0.5 or 1e3.
+// Words that strconv would also take, such as inf, nan and 0x10, are not
+// numbers.
+func parseNumber(text string) (float64, bool) {
+	digits := strings.TrimPrefix(text, "-")
+	if digits == "" || !(digits[0] >= '0' && digits[0] <= '9' || digits[0] == '.') ||
+		strings.ContainsFunc(digits, func(r rune) bool { return !strings.ContainsRune("0123456789.eE+-", r) }) {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	return n, err == nil
+}
+
+// negativeSign reports whether the '-' at rs[i] starts a negative number.
+func negativeSign(rs []rune, i int) bool {
+	if i+1 >= len(rs) || !(unicode.IsDigit(rs[i+1]) || rs[i+1] == '.') {
+		return false
+	}
+	return i == 0 || unicode.IsSpace(rs[i-1]) || strings.ContainsRune("[(", rs[i-1])
+}
+
+// parser reads instructions from a token list.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.toks) }
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	tk := p.toks[p.pos]
+	p.pos++
+	return tk
+}
+
+// line returns the source line of the current token, or of the last one.
+func (p *parser) line() int {
+	switch {
+	case len(p.toks) == 0:
+		return 0
+	case p.done():
+		return p.toks[len(p.toks)-1].line
+	}
+	return p.peek().line
+}
+
+// list reads the rest of a list whose '[' has been consumed.
+func (p *parser) list() ([]token, error) {
+	start := p.pos
+	depth := 0
+	for ; !p.done(); p.pos++ {
+		switch p.peek().kind {
+		case openToken:
+			depth++
+		case closeToken:
+			if depth == 0 {
+				l := p.toks[start:p.pos]
+				p.pos++
+				return l, nil
+			}
+			depth--
+		}
+	}
+	return nil, fmt.Errorf("missing ']'")
+}
@@ -0,0 +1,424 @@
+// Package logo runs Logo programs on a gotuga turtle.
+//
+//	logo.Run(t, `
+//		to square :size
+//		  repeat 4 [forward :size right 90]
+//		end
+//		repeat 36 [square 100 right 10]
+//	`)
+//
+// The dialect follows Berkeley Logo: procedures defined with TO ... END,
+// with OUTPUT and STOP; variables set with MAKE and LOCAL and read with
+// :name; infix arithmetic and comparisons; IF, IFELSE, REPEAT, REPCOUNT,
+// FOR and WHILE; and the usual turtle, arithmetic and printing primitives
+// with their abbreviations. Words are case-insensitive and comments start
+// with ';'.
+//
+// As in Logo, headings are measured clockwise from north and the turtle
+// starts out facing north.
+package logo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// maxDepth limits how deeply procedure calls nest, so runaway recursion is
+// an error rather than a crash.
+const maxDepth = 1000
+
+// Interpreter runs Logo source on a turtle. Procedures and global variables
+// persist between calls to Run.
+type Interpreter struct {
+	// Output receives the text of PRINT, TYPE and SHOW; os.Stdout by default.
+	Output io.Writer
+
+	t       *gotuga.Turtle
+	procs   map[string]*procedure
+	scopes  []map[string]any // innermost last; scopes[0] holds globals
+	repeats []int            // REPCOUNT of the enclosing REPEATs
+}
+
+// procedure is a user procedure defined with TO.
+type procedure struct {
+	name   string
+	params []string
+	body   []token
+}
+
+// New returns an interpreter driving t, and points t north.
+func New(t *gotuga.Turtle) *Interpreter {
+	t.SetHeading(90)
+	return &Interpreter{
+		Output: os.Stdout,
+		t:      t,
+		procs:  make(map[string]*procedure),
+		scopes: []map[string]any{make(map[string]any)},
+	}
+}
+
+// Run runs src on t with a new interpreter.
+func Run(t *gotuga.Turtle, src string) error {
+	return New(t).Run(src)
+}
+
+// Turtle returns the turtle the interpreter draws with.
+func (in *Interpreter) Turtle() *gotuga.Turtle { return in.t }
+
+// Run executes the instructions in src. A turtle past its step or time
+// budget stops the program with an error wrapping a *gotuga.BudgetError.
+func (in *Interpreter) Run(src string) error {
+	err := in.runList(lex(src))
+	switch err.(type) {
+	case *outputSignal:
+		return &Error{0, fmt.Errorf("can only use output inside a procedure")}
+	case stopSignal:
+		return nil // STOP at top level ends the program
+	}
+	return err
+}
+
+// Error is a Logo error with the source line it occurred on.
+type Error struct {
+	Line int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("logo: %v", e.Err)
+	}
+	return fmt.Sprintf("logo: line %d: %v", e.Line, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// outputSignal and stopSignal unwind a procedure for OUTPUT and STOP.
+type outputSignal struct{ value any }
+
+type stopSignal struct{}
+
+func (*outputSignal) Error() string { return "output outside a procedure" }
+
+func (stopSignal) Error() string { return "stop outside a procedure" }
+
+// runList runs a list of instructions.
+func (in *Interpreter) runList(toks []token) error {
+	v, err := in.runValue(toks)
+	if err == nil && v != nil {
+		return &Error{toks[len(toks)-1].line, fmt.Errorf("don't know what to do with %s", format(v))}
+	}
+	return err
+}
+
+// runValue runs a list of instructions, the last of which may be an
+// expression whose value is returned, as for IFELSE used as an operation.
+func (in *Interpreter) runValue(toks []token) (any, error) {
+	p := &parser{toks: toks}
+	for !p.done() {
+		if tk := p.peek(); tk.kind == wordToken && strings.EqualFold(tk.text, "to") {
+			if err := in.define(p); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		v, err := in.expr(p)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			if p.done() {
+				return v, nil
+			}
+			return nil, in.errorf(p, "don't know what to do with %s", format(v))
+		}
+	}
+	return nil, nil
+}
+
+// define reads a procedure definition: TO name :input ... body END.
+func (in *Interpreter) define(p *parser) error {
+	to := p.next()
+	if p.done() || p.peek().kind != wordToken {
+		return &Error{to.line, fmt.Errorf("to needs a procedure name")}
+	}
+	name := strings.ToLower(p.next().text)
+	if _, ok := lookupPrimitive(name); ok {
+		return &Error{to.line, fmt.Errorf("%s is a primitive", name)}
+	}
+	proc := &procedure{name: name}
+	for !p.done() && p.peek().kind == variableToken && p.peek().line == to.line {
+		proc.params = append(proc.params, strings.ToLower(p.next().name()))
+	}
+	start := p.pos
+	for ; !p.done(); p.pos++ {
+		if tk := p.peek(); tk.kind == wordToken && strings.EqualFold(tk.text, "end") {
+			proc.body = p.toks[start:p.pos]
+			p.pos++
+			in.procs[name] = proc
+			return nil
+		}
+	}
+	return &Error{to.line, fmt.Errorf("to %s has no end", name)}
+}
+
+// expr evaluates one expression, including infix comparisons. Commands
+// evaluate to nil.
+func (in *Interpreter) expr(p *parser) (any, error) {
+	return in.binary(p, 0)
+}
+
+// precedence lists the infix operators from loosest to tightest binding.
+var precedence = [][]string{
+	{"=", "<", ">", "<=", ">=", "<>"},
+	{"+", "-"},
+	{"*", "/"},
+}
+
+func (in *Interpreter) binary(p *parser, level int) (any, error) {
+	if level == len(precedence) {
+		return in.unary(p)
+	}
+	left, err := in.binary(p, level+1)
+	if err != nil {
+		return nil, err
+	}
+	for !p.done() && p.peek().kind == infixToken && contains(precedence[level], p.peek().text) {
+		op := p.next()
+		right, err := in.binary(p, level+1)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = infix(op.text, left, right); err != nil {
+			return nil, &Error{op.line, err}
+		}
+	}
+	return left, nil
+}
+
+func (in *Interpreter) unary(p *parser) (any, error) {
+	if !p.done() && p.peek().kind == infixToken && p.peek().text == "-" {
+		op := p.next()
+		v, err := in.unary(p)
+		if err != nil {
+			return nil, err
+		}
+		n, err := number(v)
+		if err != nil {
+			return nil, &Error{op.line, err}
+		}
+		return -n, nil
+	}
+	return in.primary(p)
+}
+
+// primary evaluates a literal, a variable, a parenthesized expression or a
+// procedure call.
+func (in *Interpreter) primary(p *parser) (any, error) {
+	if p.done() {
+		return nil, in.errorf(p, "not enough inputs")
+	}
+	tk := p.next()
+	switch tk.kind {
+	case numberToken:
+		return tk.num, nil
+	case quotedToken:
+		return tk.name(), nil
+	case variableToken:
+		v, ok := in.lookup(tk.name())
+		if !ok {
+			return nil, &Error{tk.line, fmt.Errorf("%s has no value", tk.name())}
+		}
+		return v, nil
+	case openToken:
+		l, err := p.list()
+		if err != nil {
+			return nil, &Error{tk.line, err}
+		}
+		return l, nil
+	case openParenToken:
+		v, err := in.expr(p)
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.next().kind != closeParenToken {
+			return nil, &Error{tk.line, fmt.Errorf("missing ')'")}
+		}
+		return v, nil
+	case wordToken:
+		return in.call(p, tk)
+	}
+	return nil, &Error{tk.line, fmt.Errorf("unexpected %s", tk.text)}
+}
+
+// call reads the inputs of the procedure named by tk and runs it.
+func (in *Interpreter) call(p *parser, tk token) (any, error) {
+	name := strings.ToLower(tk.text)
+	prim, isPrim := lookupPrimitive(name)
+	proc := in.procs[name]
+	if !isPrim && proc == nil {
+		return nil, &Error{tk.line, fmt.Errorf("I don't know how to %s", tk.text)}
+	}
+	n := prim.inputs
+	if !isPrim {
+		n = len(proc.params)
+	}
+	args := make([]any, n)
+	for i := range args {
+		if p.done() || p.peek().kind == closeToken || p.peek().kind == closeParenToken {
+			return nil, &Error{tk.line, fmt.Errorf("not enough inputs to %s", tk.text)}
+		}
+		v, err := in.expr(p)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, &Error{tk.line, fmt.Errorf("%s didn't get an input", tk.text)}
+		}
+		args[i] = v
+	}
+	if !isPrim {
+		if len(in.scopes) > maxDepth {
+			return nil, &Error{tk.line, fmt.Errorf("%s: procedure calls nested more than %d deep", tk.text, maxDepth)}
+		}
+		return in.invoke(proc, args)
+	}
+	v, err := prim.fn(in, args)
+	switch err.(type) {
+	case nil, *Error, *outputSignal, stopSignal:
+	default:
+		err = &Error{tk.line, fmt.Errorf("%s: %w", tk.text, err)}
+	}
+	return v, err
+}
+
+// invoke runs a user procedure with its inputs bound in a new scope.
+func (in *Interpreter) invoke(proc *procedure, args []any) (any, error) {
+	if err := in.t.CheckBudget(proc.name); err != nil {
+		return nil, err
+	}
+	scope := make(map[string]any, len(args))
+	for i, name := range proc.params {
+		scope[name] = args[i]
+	}
+	in.scopes = append(in.scopes, scope)
+	defer func() { in.scopes = in.scopes[:len(in.scopes)-1] }()
+
+	err := in.runList(proc.body)
+	switch err := err.(type) {
+	case *outputSignal:
+		return err.value, nil
+	case stopSignal:
+		return nil, nil
+	}
+	return nil, err
+}
+
+// lookup finds a variable, innermost scope first. Variables declared with
+// LOCAL but not yet assigned have no value.
+func (in *Interpreter) lookup(name string) (any, bool) {
+	name = strings.ToLower(name)
+	for i := len(in.scopes) - 1; i >= 0; i-- {
+		if v, ok := in.scopes[i][name]; ok {
+			return v, v != nil
+		}
+	}
+	return nil, false
+}
+
+// set assigns the innermost variable called name, creating a global if
+// there is none.
+func (in *Interpreter) set(name string, v any) {
+	name = strings.ToLower(name)
+	for i := len(in.scopes) - 1; i >= 0; i-- {
+		if _, ok := in.scopes[i][name]; ok {
+			in.scopes[i][name] = v
+			return
+		}
+	}
+	in.scopes[0][name] = v
+}
+
+func (in *Interpreter) errorf(p *parser, format string, args ...any) error {
+	return &Error{p.line(), fmt.Errorf(format, args...)}
+}
+
+// infix applies an infix operator.
+func infix(op string, a, b any) (any, error) {
+	if op == "=" || op == "<>" {
+		return boolWord(equal(a, b) == (op == "=")), nil
+	}
+	x, err := number(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := number(b)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return x / y, nil
+	case "<":
+		return boolWord(x < y), nil
+	case ">":
+		return boolWord(x > y), nil
+	case "<=":
+		return boolWord(x <= y), nil
+	}
+	return boolWord(x >= y), nil
+}
+
+// equal compares numbers numerically and words ignoring case.
+func equal(a, b any) bool {
+	if x, err := number(a); err == nil {
+		y, err := number(b)
+		return err == nil && x == y
+	}
+	return strings.EqualFold(format(a), format(b))
+}
+
+func boolWord(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// format renders a value the way Logo prints it.
+func format(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []token:
+		parts := make([]string, len(v))
+		for i, tk := range v {
+			parts[i] = tk.text
+		}
+		return "[" + strings.Join(parts, " ") + "]"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,586 @@
+package logo
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// primitive is a built-in procedure taking a fixed number of inputs.
+// Commands return nil; operations return their output.
+type primitive struct {
+	inputs int
+	fn     func(in *Interpreter, args []any) (any, error)
+}
+
+var primitives map[string]primitive
+
+func init() {
+	// Assigned in init because the control primitives call back into the
+	// interpreter, which looks primitives up.
+	primitives = map[string]primitive{
+		// Turtle motion and pen.
+		"forward":      {1, command1(func(in *Interpreter, n float64) { in.t.Forward(n) })},
+		"back":         {1, command1(func(in *Interpreter, n float64) { in.t.Backward(n) })},
+		"left":         {1, command1(func(in *Interpreter, n float64) { in.t.Left(n) })},
+		"right":        {1, command1(func(in *Interpreter, n float64) { in.t.Right(n) })},
+		"setheading":   {1, command1(func(in *Interpreter, n float64) { in.t.SetHeading(90 - n) })},
+		"setpensize":   {1, command1(func(in *Interpreter, n float64) { in.t.SetWidth(n) })},
+		"setxy":        {2, setxy},
+		"setx":         {1, command1(func(in *Interpreter, n float64) { _, y := in.t.Position(); in.t.GoTo(n, y) })},
+		"sety":         {1, command1(func(in *Interpreter, n float64) { x, _ := in.t.Position(); in.t.GoTo(x, n) })},
+		"penup":        {0, command0(func(in *Interpreter) { in.t.PenUp() })},
+		"pendown":      {0, command0(func(in *Interpreter) { in.t.PenDown() })},
+		"home":         {0, command0((*Interpreter).home)},
+		"clean":        {0, command0(func(in *Interpreter) { in.t.Clear() })},
+		"clearscreen":  {0, command0(func(in *Interpreter) { in.t.Clear(); in.home() })},
+		"hideturtle":   {0, command0(func(*Interpreter) {})},
+		"showturtle":   {0, command0(func(*Interpreter) {})},
+		"setpencolor":  {1, colorCommand((*gotuga.Turtle).SetColor)},
+		"setfillcolor": {1, colorCommand((*gotuga.Turtle).FillColor)},
+		"beginfill":    {0, command0(func(in *Interpreter) { in.t.BeginFill() })},
+		"endfill":      {0, command0(func(in *Interpreter) { in.t.EndFill() })},
+		"xcor":         {0, operation0(func(in *Interpreter) any { x, _ := in.t.Position(); return x })},
+		"ycor":         {0, operation0(func(in *Interpreter) any { _, y := in.t.Position(); return y })},
+		"heading":      {0, operation0(func(in *Interpreter) any { return math.Mod(math.Mod(450-in.t.Heading(), 360)+360, 360) })},
+		"pos":          {0, operation0(func(in *Interpreter) any { return numberList(in.t.Position()) })},
+		"pendownp":     {0, operation0(func(in *Interpreter) any { return boolWord(in.t.IsDown()) })},
+
+		// Control.
+		"repeat":   {2, repeat},
+		"repcount": {0, repcount},
+		"if":       {2, ifThen},
+		"ifelse":   {3, ifElse},
+		"for":      {2, forLoop},
+		"while":    {2, while},
+		"output":   {1, func(in *Interpreter, args []any) (any, error) { return nil, &outputSignal{args[0]} }},
+		"stop":     {0, func(*Interpreter, []any) (any, error) { return nil, stopSignal{} }},
+
+		// Variables.
+		"make":  {2, makeVar},
+		"local": {1, local},
+		"thing": {1, thing},
+
+		// Printing.
+		"print": {1, printer(false, "\n")},
+		"type":  {1, printer(false, "")},
+		"show":  {1, printer(true, "\n")},
+
+		// Arithmetic and logic.
+		"sum":        {2, arith(func(a, b float64) float64 { return a + b })},
+		"difference": {2, arith(func(a, b float64) float64 { return a - b })},
+		"product":    {2, arith(func(a, b float64) float64 { return a * b })},
+		"quotient":   {2, func(_ *Interpreter, args []any) (any, error) { return binaryOp("/", args) }},
+		"remainder":  {2, arith(math.Mod)},
+		"power":      {2, arith(math.Pow)},
+		"minus":      {1, math1(func(x float64) float64 { return -x })},
+		"abs":        {1, math1(math.Abs)},
+		"sqrt":       {1, math1(math.Sqrt)},
+		"int":        {1, math1(math.Trunc)},
+		"round":      {1, math1(math.Round)},
+		"sin":        {1, math1(func(x float64) float64 { return math.Sin(x * math.Pi / 180) })},
+		"cos":        {1, math1(func(x float64) float64 { return math.Cos(x * math.Pi / 180) })},
+		"tan":        {1, math1(func(x float64) float64 { return math.Tan(x * math.Pi / 180) })},
+		"arctan":     {1, math1(func(x float64) float64 { return math.Atan(x) * 180 / math.Pi })},
+		"random":     {1, random},
+		"equalp":     {2, func(_ *Interpreter, args []any) (any, error) { return binaryOp("=", args) }},
+		"lessp":      {2, func(_ *Interpreter, args []any) (any, error) { return binaryOp("<", args) }},
+		"greaterp":   {2, func(_ *Interpreter, args []any) (any, error) { return binaryOp(">", args) }},
+		"and":        {2, logic(func(a, b bool) bool { return a && b })},
+		"or":         {2, logic(func(a, b bool) bool { return a || b })},
+		"not":        {1, not},
+
+		// Words and lists.
+		"first": {1, first},
+		"last":  {1, last},
+		"item":  {2, item},
+		"count": {1, count},
+	}
+}
+
+var aliases = map[string]string{
+	"fd":       "forward",
+	"bk":       "back",
+	"lt":       "left",
+	"rt":       "right",
+	"seth":     "setheading",
+	"pu":       "penup",
+	"pd":       "pendown",
+	"cs":       "clearscreen",
+	"ht":       "hideturtle",
+	"st":       "showturtle",
+	"setpc":    "setpencolor",
+	"setfc":    "setfillcolor",
+	"setwidth": "setpensize",
+	"op":       "output",
+	"pr":       "print",
+}
+
+// lookupPrimitive finds a primitive by its lower-case name or abbreviation.
+func lookupPrimitive(name string) (primitive, bool) {
+	if full, ok := aliases[name]; ok {
+		name = full
+	}
+	p, ok := primitives[name]
+	return p, ok
+}
+
+// home returns the turtle to the origin, facing north.
+func (in *Interpreter) home() {
+	in.t.Home()
+	in.t.SetHeading(90)
+}
+
+func setxy(in *Interpreter, args []any) (any, error) {
+	x, err := number(args[0])
+	if err != nil {
+		return nil, err
+	}
+	y, err := number(args[1])
+	if err != nil {
+		return nil, err
+	}
+	in.t.GoTo(x, y)
+	return nil, nil
+}
+
+func repeat(in *Interpreter, args []any) (any, error) {
+	n, err := number(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if !(math.Abs(n) <= maxWhole) {
+		return nil, fmt.Errorf("can't repeat %v times", n)
+	}
+	body, err := list(args[1])
+	if err != nil {
+		return nil, err
+	}
+	in.repeats = append(in.repeats, 0)
+	defer func() { in.repeats = in.repeats[:len(in.repeats)-1] }()
+	for i := 1; i <= int(n); i++ {
+		if err := in.t.CheckBudget("repeat"); err != nil {
+			return nil, err
+		}
+		in.repeats[len(in.repeats)-1] = i
+		if err := in.runList(body); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func repcount(in *Interpreter, _ []any) (any, error) {
+	if len(in.repeats) == 0 {
+		return -1.0, nil
+	}
+	return float64(in.repeats[len(in.repeats)-1]), nil
+}
+
+func ifThen(in *Interpreter, args []any) (any, error) {
+	cond, err := truth(args[0])
+	if err != nil {
+		return nil, err
+	}
+	body, err := list(args[1])
+	if err != nil || !cond {
+		return nil, err
+	}
+	return in.runValue(body)
+}
+
+func ifElse(in *Interpreter, args []any) (any, error) {
+	cond, err := truth(args[0])
+	if err != nil {
+		return nil, err
+	}
+	branch := args[2]
+	if cond {
+		branch = args[1]
+	}
+	body, err := list(branch)
+	if err != nil {
+		return nil, err
+	}
+	return in.runValue(body)
+}
+
+// forLoop runs FOR [var start end step] [body]; the step defaults to 1 or
+// -1 depending on direction.
+func forLoop(in *Interpreter, args []any) (any, error) {
+	ctrl, err := list(args[0])
+	if err != nil {
+		return nil, err
+	}
+	body, err := list(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(ctrl) == 0 || ctrl[0].kind != wordToken {
+		return nil, errors.New("control list must start with a variable name")
+	}
+	p := &parser{toks: ctrl, pos: 1}
+	var bounds []float64
+	for !p.done() {
+		v, err := in.expr(p)
+		if err != nil {
+			return nil, err
+		}
+		n, err := number(v)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, n)
+	}
+	if len(bounds) < 2 || len(bounds) > 3 {
+		return nil, errors.New("control list must be [var start end] or [var start end step]")
+	}
+	start, end, step := bounds[0], bounds[1], 1.0
+	if end < start {
+		step = -1
+	}
+	if len(bounds) == 3 {
+		step = bounds[2]
+	}
+	if step == 0 {
+		return nil, errors.New("step must not be zero")
+	}
+	scope := make(map[string]any)
+	in.scopes = append(in.scopes, scope)
+	defer func() { in.scopes = in.scopes[:len(in.scopes)-1] }()
+	name := strings.ToLower(ctrl[0].text)
+	for v := start; step > 0 && v <= end || step < 0 && v >= end; v += step {
+		if err := in.t.CheckBudget("for"); err != nil {
+			return nil, err
+		}
+		scope[name] = v
+		if err := in.runList(body); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func while(in *Interpreter, args []any) (any, error) {
+	cond, err := list(args[0])
+	if err != nil {
+		return nil, err
+	}
+	body, err := list(args[1])
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if err := in.t.CheckBudget("while"); err != nil {
+			return nil, err
+		}
+		v, err := in.runValue(cond)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := truth(v)
+		if err != nil || !ok {
+			return nil, err
+		}
+		if err := in.runList(body); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func makeVar(in *Interpreter, args []any) (any, error) {
+	name, err := word(args[0])
+	if err != nil {
+		return nil, err
+	}
+	in.set(name, args[1])
+	return nil, nil
+}
+
+func local(in *Interpreter, args []any) (any, error) {
+	name, err := word(args[0])
+	if err != nil {
+		return nil, err
+	}
+	// Dynamic scoping: the variable belongs to the running procedure, which
+	// at top level means it is global.
+	in.scopes[len(in.scopes)-1][strings.ToLower(name)] = nil
+	return nil, nil
+}
+
+func thing(in *Interpreter, args []any) (any, error) {
+	name, err := word(args[0])
+	if err != nil {
+		return nil, err
+	}
+	v, ok := in.lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%s has no value", name)
+	}
+	return v, nil
+}
+
+// printer prints its input; lists keep their brackets only when
+// brackets is set.
+func printer(brackets bool, end string) func(*Interpreter, []any) (any, error) {
+	return func(in *Interpreter, args []any) (any, error) {
+		s := format(args[0])
+		if _, isList := args[0].([]token); isList && !brackets {
+			s = s[1 : len(s)-1]
+		}
+		_, err := fmt.Fprint(in.Output, s+end)
+		return nil, err
+	}
+}
+
+func random(_ *Interpreter, args []any) (any, error) {
+	n, err := number(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if !(n >= 1) {
+		return nil, fmt.Errorf("%v is not a positive number", n)
+	}
+	if n > maxWhole {
+		return nil, fmt.Errorf("%v is more than %d", n, maxWhole)
+	}
+	return float64(rand.IntN(int(n))), nil
+}
+
+// maxWhole is the largest count repeat and random take, beyond which
+// float64 no longer holds every whole number.
+const maxWhole = 1 << 53
+
+func not(_ *Interpreter, args []any) (any, error) {
+	b, err := truth(args[0])
+	return boolWord(!b), err
+}
+
+func first(_ *Interpreter, args []any) (any, error) {
+	return item(nil, []any{1.0, args[0]})
+}
+
+func last(_ *Interpreter, args []any) (any, error) {
+	n, _ := count(nil, args)
+	return item(nil, []any{n, args[0]})
+}
+
+func item(_ *Interpreter, args []any) (any, error) {
+	i, err := number(args[0])
+	if err != nil {
+		return nil, err
+	}
+	n, _ := count(nil, args[1:])
+	if !(i >= 1 && i <= n.(float64)) {
+		return nil, fmt.Errorf("%s has no item %v", format(args[1]), i)
+	}
+	if l, ok := args[1].([]token); ok {
+		return listItem(l, int(i))
+	}
+	return string([]rune(format(args[1]))[int(i)-1]), nil
+}
+
+func count(_ *Interpreter, args []any) (any, error) {
+	if l, ok := args[0].([]token); ok {
+		return float64(len(listItems(l))), nil
+	}
+	return float64(len([]rune(format(args[0])))), nil
+}
+
+// listItems splits a list into its members, keeping sublists whole.
+func listItems(l []token) [][]token {
+	var items [][]token
+	for i := 0; i < len(l); i++ {
+		start := i
+		if l[i].kind == openToken {
+			for depth := 1; depth > 0; {
+				i++
+				switch l[i].kind {
+				case openToken:
+					depth++
+				case closeToken:
+					depth--
+				}
+			}
+		}
+		items = append(items, l[start:i+1])
+	}
+	return items
+}
+
+func listItem(l []token, i int) (any, error) {
+	it := listItems(l)[i-1]
+	switch tk := it[0]; {
+	case tk.kind == openToken:
+		return it[1 : len(it)-1], nil
+	case tk.kind == numberToken:
+		return tk.num, nil
+	default:
+		return tk.text, nil
+	}
+}
+
+func command0(fn func(in *Interpreter)) func(*Interpreter, []any) (any, error) {
+	return func(in *Interpreter, _ []any) (any, error) {
+		fn(in)
+		return nil, nil
+	}
+}
+
+func command1(fn func(in *Interpreter, n float64)) func(*Interpreter, []any) (any, error) {
+	return func(in *Interpreter, args []any) (any, error) {
+		n, err := number(args[0])
+		if err != nil {
+			return nil, err
+		}
+		fn(in, n)
+		return nil, nil
+	}
+}
+
+func operation0(fn func(in *Interpreter) any) func(*Interpreter, []any) (any, error) {
+	return func(in *Interpreter, _ []any) (any, error) {
+		return fn(in), nil
+	}
+}
+
+func math1(fn func(x float64) float64) func(*Interpreter, []any) (any, error) {
+	return func(_ *Interpreter, args []any) (any, error) {
+		x, err := number(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return fn(x), nil
+	}
+}
+
+func arith(fn func(a, b float64) float64) func(*Interpreter, []any) (any, error) {
+	return func(_ *Interpreter, args []any) (any, error) {
+		a, err := number(args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := number(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return fn(a, b), nil
+	}
+}
+
+func binaryOp(op string, args []any) (any, error) {
+	return infix(op, args[0], args[1])
+}
+
+func logic(fn func(a, b bool) bool) func(*Interpreter, []any) (any, error) {
+	return func(_ *Interpreter, args []any) (any, error) {
+		a, err := truth(args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := truth(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return boolWord(fn(a, b)), nil
+	}
+}
+
+func colorCommand(set func(*gotuga.Turtle, color.Color)) func(*Interpreter, []any) (any, error) {
+	return func(in *Interpreter, args []any) (any, error) {
+		c, err := toColor(args[0])
+		if err != nil {
+			return nil, err
+		}
+		set(in.t, c)
+		return nil, nil
+	}
+}
+
+// palette holds the standard Logo colors selected by number.
+var palette = []color.NRGBA{
+	{0, 0, 0, 255}, {0, 0, 255, 255}, {0, 255, 0, 255}, {0, 255, 255, 255},
+	{255, 0, 0, 255}, {255, 0, 255, 255}, {255, 255, 0, 255}, {255, 255, 255, 255},
+	{155, 96, 59, 255}, {197, 136, 18, 255}, {100, 162, 64, 255}, {120, 187, 187, 255},
+	{255, 149, 119, 255}, {144, 113, 208, 255}, {255, 163, 0, 255}, {183, 183, 183, 255},
+}
+
+// toColor accepts a palette number or a list of red, green and blue values
+// from 0 to 255.
+func toColor(v any) (color.Color, error) {
+	if n, err := number(v); err == nil {
+		if !(n >= 0 && n < float64(len(palette))) {
+			return nil, fmt.Errorf("no color %v", n)
+		}
+		return palette[int(n)], nil
+	}
+	l, err := list(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(l) != 3 {
+		return nil, fmt.Errorf("color %s must be [red green blue]", format(v))
+	}
+	var rgb [3]uint8
+	for i, tk := range l {
+		if tk.kind != numberToken {
+			return nil, fmt.Errorf("color %s must be [red green blue]", format(v))
+		}
+		rgb[i] = uint8(math.Max(0, math.Min(255, math.Round(tk.num))))
+	}
+	return color.NRGBA{rgb[0], rgb[1], rgb[2], 255}, nil
+}
+
+// number accepts numbers and words that spell one.
+func number(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case string:
+		if n, ok := parseNumber(v); ok {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not a number", format(v))
+}
+
+func word(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s is not a word", format(v))
+	}
+	return s, nil
+}
+
+func truth(v any) (bool, error) {
+	switch s := format(v); strings.ToLower(s) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%s is not true or false", s)
+	}
+}
+
+func list(v any) ([]token, error) {
+	l, ok := v.([]token)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list", format(v))
+	}
+	return l, nil
+}
+
+// numberList makes a list value of numbers.
+func numberList(ns ...float64) []token {
+	l := make([]token, len(ns))
+	for i, n := range ns {
+		l[i] = token{kind: numberToken, text: format(n), num: n}
+	}
+	return l
+}
@@ -0,0 +1,132 @@
+// Package lsystem expands Lindenmayer systems and draws them with a turtle.
+//
+// A Koch curve:
+//
+//	koch := &lsystem.System{Axiom: "F", Rules: map[rune]string{'F': "F+F-F-F+F"}, Angle: 90, Step: 5}
+//	koch.Draw(t, 4)
+//
+// A branching plant, using the bracket stack:
+//
+//	plant := &lsystem.System{
+//		Axiom: "X",
+//		Rules: map[rune]string{'X': "F+[[X]-X]-F[-FX]+X", 'F': "FF"},
+//		Angle: 25,
+//		Step:  4,
+//	}
+//	plant.Draw(t, 6)
+package lsystem
+
+import (
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// System is an L-system with its turtle interpretation.
+type System struct {
+	Axiom string
+	Rules map[rune]string // symbols without a rule are copied unchanged
+
+	Angle float64 // degrees turned by + and -
+	Step  float64 // distance moved by F, G and f
+
+	// Actions adds to or overrides DefaultActions.
+	Actions map[rune]Action
+}
+
+// Action draws one symbol.
+type Action func(p *Pen)
+
+// DefaultActions is the usual interpretation of L-system symbols. Symbols
+// with no action, like X and Y, only take part in the expansion.
+var DefaultActions = map[rune]Action{
+	'F': func(p *Pen) { p.Forward(p.Step) },
+	'G': func(p *Pen) { p.Forward(p.Step) },
+	'f': func(p *Pen) {
+		down := p.IsDown()
+		p.PenUp()
+		p.Forward(p.Step)
+		if down {
+			p.PenDown()
+		}
+	},
+	'+': func(p *Pen) { p.Left(p.Angle) },
+	'-': func(p *Pen) { p.Right(p.Angle) },
+	'−': func(p *Pen) { p.Right(p.Angle) },
+	'|': func(p *Pen) { p.Left(180) },
+	'[': (*Pen).Push,
+	']': (*Pen).Pop,
+}
+
+// Pen is the turtle as seen by actions, with the system's angle and step
+// and a stack of saved states.
+type Pen struct {
+	*gotuga.Turtle
+	Angle, Step float64
+
+	stack []penState
+}
+
+type penState struct {
+	x, y, heading float64
+	down          bool
+}
+
+// Push saves the turtle's position, heading and pen state.
+func (p *Pen) Push() {
+	x, y := p.Position()
+	p.stack = append(p.stack, penState{x, y, p.Heading(), p.IsDown()})
+}
+
+// Pop returns the turtle to the last saved state without drawing. Popping
+// an empty stack does nothing.
+func (p *Pen) Pop() {
+	if len(p.stack) == 0 {
+		return
+	}
+	s := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	p.PenUp()
+	p.GoTo(s.x, s.y)
+	p.SetHeading(s.heading)
+	if s.down {
+		p.PenDown()
+	}
+}
+
+// Expand applies the rules n times to the axiom.
+func (s *System) Expand(n int) string {
+	cur := s.Axiom
+	for i := 0; i < n; i++ {
+		var b strings.Builder
+		for _, r := range cur {
+			if rep, ok := s.Rules[r]; ok {
+				b.WriteString(rep)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		cur = b.String()
+	}
+	return cur
+}
+
+// Draw expands the system n times and draws the result from the turtle's
+// current position and heading.
+func (s *System) Draw(t *gotuga.Turtle, n int) {
+	s.Interpret(t, s.Expand(n))
+}
+
+// Interpret draws an already expanded string.
+func (s *System) Interpret(t *gotuga.Turtle, symbols string) {
+	p := &Pen{Turtle: t, Angle: s.Angle, Step: s.Step}
+	for _, r := range symbols {
+		a, ok := s.Actions[r]
+		if !ok {
+			a = DefaultActions[r]
+		}
+		if a != nil {
+			a(p)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+)
+
+// NewMask creates a turtle for drawing masks: its W×H canvas starts fully
+// transparent and its pen is opaque, so whatever it draws becomes coverage.
+// Pen and fill colors count only by their alpha. Take the result with Mask,
+// for SetClipMask, image/draw or compositing elsewhere.
+func NewMask(W, H int) *Turtle {
+	t := New(W, H, color.Transparent)
+	t.penColor, t.fillColor = color.Opaque, color.Opaque
+	return t
+}
+
+// Mask returns the coverage of the canvas, its alpha channel, as a new
+// image. With a turtle from NewMask this is everything drawn so far.
+func (t *Turtle) Mask() *image.Alpha {
+	canvas := t.pixels()
+	b := canvas.Bounds()
+	m := image.NewAlpha(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		src := canvas.Pix[(y-b.Min.Y)*canvas.Stride:]
+		dst := m.Pix[(y-b.Min.Y)*m.Stride:]
+		for x := 0; x < b.Dx(); x++ {
+			dst[x] = src[4*x+3]
+		}
+	}
+	return m
+}
@@ -0,0 +1,45 @@
+package gotuga
+
+import (
+	"image"
+	"math"
+	"sync"
+)
+
+// maskPool holds alpha masks for fills to reuse, so that filling many
+// small shapes doesn't allocate a mask per shape.
+var maskPool sync.Pool
+
+// getMask returns a transparent mask with bounds r, reusing a pooled one
+// if it is big enough. Give it back with putMask.
+func getMask(r image.Rectangle) *image.Alpha {
+	n := r.Dx() * r.Dy()
+	if m, ok := maskPool.Get().(*image.Alpha); ok && cap(m.Pix) >= n {
+		m.Pix = m.Pix[:n]
+		clear(m.Pix)
+		m.Stride = r.Dx()
+		m.Rect = r
+		return m
+	}
+	return image.NewAlpha(r)
+}
+
+// putMask returns a mask from getMask to the pool.
+func putMask(m *image.Alpha) { maskPool.Put(m) }
+
+// polygonBounds returns the pixels whose centers may lie inside the
+// polygon pts, in unrounded pixel coordinates.
+func polygonBounds(pts [][2]float64) image.Rectangle {
+	if len(pts) == 0 {
+		return image.Rectangle{}
+	}
+	x0, y0, x1, y1 := pts[0][0], pts[0][1], pts[0][0], pts[0][1]
+	for _, p := range pts[1:] {
+		x0, x1 = math.Min(x0, p[0]), math.Max(x1, p[0])
+		y0, y1 = math.Min(y0, p[1]), math.Max(y1, p[1])
+	}
+	if math.IsNaN(x0 + x1 + y0 + y1) {
+		return image.Rect(-maxPixel, -maxPixel, maxPixel, maxPixel)
+	}
+	return image.Rect(pixelFloor(x0), pixelFloor(y0), pixelCeil(x1), pixelCeil(y1))
+}
@@ -0,0 +1,281 @@
+// Package maze generates mazes, solves them and draws both with turtles.
+//
+//	m := maze.Backtracker(20, 15, rand.New(rand.NewPCG(1, 0)))
+//	m.Draw(t, 20)
+//
+//	solver := t.Spawn()
+//	solver.SetColor(color.RGBA{255, 0, 0, 255})
+//	solver.SetRealTime(30) // watch it walk, or Record it
+//	m.DrawPath(solver, 20, m.Solve())
+//
+// Draw and DrawPath center the maze on the turtle's position, so the two
+// turtles above, both at the origin, agree on where it is.
+//
+// Mazes are perfect: there is exactly one path between any two cells. The
+// entrance is on the west side of the top-left cell and the exit on the
+// east side of the bottom-right one.
+package maze
+
+import (
+	"math/rand/v2"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/random"
+)
+
+// Cell is a cell position; X grows east and Y grows south from the
+// top-left cell.
+type Cell struct{ X, Y int }
+
+// Directions, as bits of Maze.open.
+const (
+	north = 1 << iota
+	east
+	south
+	west
+)
+
+var steps = []struct {
+	dir, opposite int
+	dx, dy        int
+}{
+	{north, south, 0, -1},
+	{east, west, 1, 0},
+	{south, north, 0, 1},
+	{west, east, -1, 0},
+}
+
+// Maze is a grid of W×H cells.
+type Maze struct {
+	W, H int
+	open [][]uint8 // open[y][x] has a bit set for every passage out
+}
+
+func newMaze(w, h int) *Maze {
+	m := &Maze{W: w, H: h, open: make([][]uint8, h)}
+	for y := range m.open {
+		m.open[y] = make([]uint8, w)
+	}
+	return m
+}
+
+func (m *Maze) inside(c Cell) bool { return c.X >= 0 && c.X < m.W && c.Y >= 0 && c.Y < m.H }
+
+// Open reports whether there is a passage from c to its neighbor dx, dy
+// away (one of them 0, the other ±1).
+func (m *Maze) Open(c Cell, dx, dy int) bool {
+	for _, s := range steps {
+		if s.dx == dx && s.dy == dy {
+			return m.inside(c) && m.open[c.Y][c.X]&uint8(s.dir) != 0
+		}
+	}
+	return false
+}
+
+func (m *Maze) carve(c Cell, s int) {
+	st := steps[s]
+	n := Cell{c.X + st.dx, c.Y + st.dy}
+	m.open[c.Y][c.X] |= uint8(st.dir)
+	m.open[n.Y][n.X] |= uint8(st.opposite)
+}
+
+// Backtracker generates a maze with the recursive backtracker (randomized
+// depth-first search), which gives long, winding corridors. A nil rng uses
+// the unseeded global source.
+func Backtracker(w, h int, rng *rand.Rand) *Maze {
+	m := newMaze(w, h)
+	if w <= 0 || h <= 0 {
+		return m
+	}
+	visited := make(map[Cell]bool)
+	stack := []Cell{{0, 0}}
+	visited[Cell{0, 0}] = true
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		var choices []int
+		for i, s := range steps {
+			n := Cell{c.X + s.dx, c.Y + s.dy}
+			if m.inside(n) && !visited[n] {
+				choices = append(choices, i)
+			}
+		}
+		if len(choices) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		s := choices[random.IntN(rng, len(choices))]
+		m.carve(c, s)
+		n := Cell{c.X + steps[s].dx, c.Y + steps[s].dy}
+		visited[n] = true
+		stack = append(stack, n)
+	}
+	return m
+}
+
+// Prim generates a maze with randomized Prim's algorithm, which gives many
+// short dead ends. A nil rng uses the unseeded global source.
+func Prim(w, h int, rng *rand.Rand) *Maze {
+	m := newMaze(w, h)
+	if w <= 0 || h <= 0 {
+		return m
+	}
+	type edge struct {
+		c Cell
+		s int
+	}
+	in := make(map[Cell]bool)
+	var frontier []edge
+	add := func(c Cell) {
+		in[c] = true
+		for i, s := range steps {
+			if n := (Cell{c.X + s.dx, c.Y + s.dy}); m.inside(n) && !in[n] {
+				frontier = append(frontier, edge{c, i})
+			}
+		}
+	}
+	add(Cell{random.IntN(rng, w), random.IntN(rng, h)})
+	for len(frontier) > 0 {
+		i := random.IntN(rng, len(frontier))
+		e := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		n := Cell{e.c.X + steps[e.s].dx, e.c.Y + steps[e.s].dy}
+		if in[n] {
+			continue
+		}
+		m.carve(e.c, e.s)
+		add(n)
+	}
+	return m
+}
+
+// Solve returns the path from the entrance cell to the exit cell, both
+// included.
+func (m *Maze) Solve() []Cell {
+	return m.Path(Cell{0, 0}, Cell{m.W - 1, m.H - 1})
+}
+
+// Path returns the path between two cells, both included, or nil if either
+// is outside the maze.
+func (m *Maze) Path(from, to Cell) []Cell {
+	if !m.inside(from) || !m.inside(to) {
+		return nil
+	}
+	prev := map[Cell]Cell{from: from}
+	queue := []Cell{from}
+	for len(queue) > 0 && queue[0] != to {
+		c := queue[0]
+		queue = queue[1:]
+		for _, s := range steps {
+			n := Cell{c.X + s.dx, c.Y + s.dy}
+			if _, seen := prev[n]; m.open[c.Y][c.X]&uint8(s.dir) != 0 && !seen {
+				prev[n] = c
+				queue = append(queue, n)
+			}
+		}
+	}
+	if _, ok := prev[to]; !ok {
+		return nil
+	}
+	var path []Cell
+	for c := to; c != from; c = prev[c] {
+		path = append(path, c)
+	}
+	path = append(path, from)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Center returns the logical coordinates of the center of cell c, for a
+// maze drawn by a turtle at (x, y) with the given cell size.
+func (m *Maze) Center(x, y, size float64, c Cell) (float64, float64) {
+	left := x - float64(m.W)*size/2
+	top := y + float64(m.H)*size/2
+	return left + (float64(c.X)+0.5)*size, top - (float64(c.Y)+0.5)*size
+}
+
+// Draw draws the maze's walls centered on the turtle, size logical units
+// per cell. The turtle ends where it started.
+func (m *Maze) Draw(t *gotuga.Turtle, size float64) {
+	x0, y0 := t.Position()
+	left := x0 - float64(m.W)*size/2
+	top := y0 + float64(m.H)*size/2
+	wall := func(ax, ay, bx, by float64) {
+		t.JumpTo(left+ax*size, top-ay*size)
+		t.GoTo(left+bx*size, top-by*size)
+	}
+
+	// Horizontal walls along the top of row y, in runs.
+	for y := 0; y <= m.H; y++ {
+		for x := 0; x < m.W; {
+			if !m.hasWall(Cell{x, y}, north) {
+				x++
+				continue
+			}
+			start := x
+			for x < m.W && m.hasWall(Cell{x, y}, north) {
+				x++
+			}
+			wall(float64(start), float64(y), float64(x), float64(y))
+		}
+	}
+	// Vertical walls along the west of column x, in runs.
+	for x := 0; x <= m.W; x++ {
+		for y := 0; y < m.H; {
+			if !m.hasWall(Cell{x, y}, west) {
+				y++
+				continue
+			}
+			start := y
+			for y < m.H && m.hasWall(Cell{x, y}, west) {
+				y++
+			}
+			wall(float64(x), float64(start), float64(x), float64(y))
+		}
+	}
+	t.JumpTo(x0, y0)
+}
+
+// hasWall reports whether there is a wall on side dir (north or west) of
+// c, which may lie one past the last row or column.
+func (m *Maze) hasWall(c Cell, dir int) bool {
+	if dir == west {
+		if c == (Cell{0, 0}) || c == (Cell{m.W, m.H - 1}) {
+			return false // entrance and exit
+		}
+		if c.X == 0 || c.X == m.W {
+			return true
+		}
+	} else if c.Y == 0 || c.Y == m.H {
+		return true
+	}
+	return m.open[c.Y][c.X]&uint8(dir) == 0
+}
+
+// DrawPath walks the turtle along a path of cells, as from Solve or Path,
+// through their centers, for a maze drawn at the turtle's starting
+// position with the given cell size. A solution also enters and leaves the
+// maze through its openings. The turtle ends where it started.
+func (m *Maze) DrawPath(t *gotuga.Turtle, size float64, path []Cell) {
+	if len(path) == 0 {
+		return
+	}
+	x0, y0 := t.Position()
+	cx, cy := m.Center(x0, y0, size, path[0])
+	if path[0] == (Cell{0, 0}) {
+		t.JumpTo(cx-size, cy)
+		t.GoTo(cx, cy)
+	} else {
+		t.JumpTo(cx, cy)
+	}
+	for _, c := range path[1:] {
+		t.GoTo(m.Center(x0, y0, size, c))
+	}
+	if last := path[len(path)-1]; last == (Cell{m.W - 1, m.H - 1}) {
+		cx, cy := m.Center(x0, y0, size, last)
+		t.GoTo(cx+size, cy)
+	}
+	t.JumpTo(x0, y0)
+}
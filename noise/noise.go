@@ -0,0 +1,129 @@
+// Package noise provides seeded Perlin noise for generative drawings, such as
+// steering a turtle through a flow field:
+//
+//	n := noise.New(42)
+//	for i := 0; i < 500; i++ {
+//		x, y := t.Position()
+//		t.SetHeading(n.Noise2(x/100, y/100) * 360)
+//		t.Forward(2)
+//	}
+//
+// The same seed always gives the same noise.
+package noise
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// Noise is a Perlin noise generator.
+type Noise struct {
+	perm [512]uint8 // a permutation of 0–255, repeated
+}
+
+// New returns a generator whose noise is determined by seed.
+func New(seed uint64) *Noise {
+	r := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	n := &Noise{}
+	for i := 0; i < 256; i++ {
+		n.perm[i] = uint8(i)
+	}
+	r.Shuffle(256, func(i, j int) { n.perm[i], n.perm[j] = n.perm[j], n.perm[i] })
+	copy(n.perm[256:], n.perm[:256])
+	return n
+}
+
+// Noise1 returns smooth noise in [-1, 1] at x. It is 0 at whole numbers
+// and varies over a distance of about 1.
+func (n *Noise) Noise1(x float64) float64 {
+	xi := int(math.Floor(x)) & 255
+	xf := x - math.Floor(x)
+	u := fade(xf)
+	a := grad1(n.perm[xi], xf)
+	b := grad1(n.perm[xi+1], xf-1)
+	// The largest possible value is 0.5.
+	return 2 * lerp(u, a, b)
+}
+
+// Noise2 returns smooth noise in [-1, 1] at (x, y). It is 0 at whole
+// numbers and varies over a distance of about 1.
+func (n *Noise) Noise2(x, y float64) float64 {
+	fx, fy := math.Floor(x), math.Floor(y)
+	xi, yi := int(fx)&255, int(fy)&255
+	xf, yf := x-fx, y-fy
+	u, v := fade(xf), fade(yf)
+	p := &n.perm
+	aa := p[int(p[xi])+yi]
+	ab := p[int(p[xi])+yi+1]
+	ba := p[int(p[xi+1])+yi]
+	bb := p[int(p[xi+1])+yi+1]
+	x1 := lerp(u, grad2(aa, xf, yf), grad2(ba, xf-1, yf))
+	x2 := lerp(u, grad2(ab, xf, yf-1), grad2(bb, xf-1, yf-1))
+	return lerp(v, x1, x2)
+}
+
+// Octaves2 sums octaves of Noise2, each at twice the frequency of the
+// previous one and persistence times its amplitude (0.5 is typical), for
+// noise with finer detail. The result is scaled back into [-1, 1].
+func (n *Noise) Octaves2(x, y float64, octaves int, persistence float64) float64 {
+	var sum, total float64
+	amp, freq := 1.0, 1.0
+	for i := 0; i < octaves; i++ {
+		sum += n.Noise2(x*freq, y*freq) * amp
+		total += amp
+		amp *= persistence
+		freq *= 2
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// Octaves1 is the one-dimensional form of Octaves2.
+func (n *Noise) Octaves1(x float64, octaves int, persistence float64) float64 {
+	var sum, total float64
+	amp, freq := 1.0, 1.0
+	for i := 0; i < octaves; i++ {
+		sum += n.Noise1(x*freq) * amp
+		total += amp
+		amp *= persistence
+		freq *= 2
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+func fade(t float64) float64 { return t * t * t * (t*(t*6-15) + 10) }
+
+func lerp(t, a, b float64) float64 { return a + t*(b-a) }
+
+func grad1(h uint8, x float64) float64 {
+	if h&1 == 0 {
+		return x
+	}
+	return -x
+}
+
+// grad2 returns the dot product of (x, y) with one of eight gradients.
+func grad2(h uint8, x, y float64) float64 {
+	switch h & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	}
+	return -y
+}
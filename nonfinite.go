@@ -0,0 +1,75 @@
+package gotuga
+
+import (
+	"fmt"
+	"math"
+)
+
+// NonFiniteBehavior selects what happens when a command is given a NaN or
+// an infinity, say a distance computed by dividing by zero. Left alone,
+// such a value would become the turtle's position or heading and spoil
+// every later move.
+type NonFiniteBehavior int
+
+const (
+	// SkipNonFinite ignores the command; it is not recorded either.
+	SkipNonFinite NonFiniteBehavior = iota
+	// ClampNonFinite runs the command with NaN taken as 0 and infinities
+	// as ±maxFinite, which keeps positions accurate to a fraction of a
+	// unit however far off the canvas they are.
+	ClampNonFinite
+	// ErrorNonFinite ignores the command, and Err reports a
+	// *NonFiniteError.
+	ErrorNonFinite
+)
+
+const maxFinite = 1e15
+
+// NonFiniteError reports a command refused by ErrorNonFinite.
+type NonFiniteError struct {
+	Step    int     // index in History the command would have had
+	Command string  // the command's name, as in History
+	Value   float64 // the NaN or infinity it was given
+}
+
+func (e *NonFiniteError) Error() string {
+	return fmt.Sprintf("gotuga: step %d: %s given %g", e.Step, e.Command, e.Value)
+}
+
+// SetNonFiniteBehavior sets what happens to commands given NaN or
+// infinite numbers. The default is SkipNonFinite.
+func (t *Turtle) SetNonFiniteBehavior(b NonFiniteBehavior) {
+	if t.refuse("nonfinite") {
+		return
+	}
+	defer t.track("nonfinite", float64(b))()
+	t.nonFinite = b
+}
+
+// NonFiniteBehavior returns what happens to commands given NaN or
+// infinite numbers.
+func (t *Turtle) NonFiniteBehavior() NonFiniteBehavior { return t.nonFinite }
+
+// finite applies the non-finite behavior to the arguments of the command
+// name, clamping them in place, and reports whether the command may run.
+func (t *Turtle) finite(name string, args ...*float64) bool {
+	for _, v := range args {
+		if !math.IsNaN(*v) && !math.IsInf(*v, 0) {
+			continue
+		}
+		switch t.nonFinite {
+		case ClampNonFinite:
+			if math.IsNaN(*v) {
+				*v = 0
+			} else {
+				*v = math.Copysign(maxFinite, *v)
+			}
+		case ErrorNonFinite:
+			t.setErr(&NonFiniteError{Step: t.steps, Command: name, Value: *v})
+			return false
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+// Package notebook shows turtle drawings inline in Go notebooks. In GoNB,
+// call Display at the end of a cell:
+//
+//	%%
+//	t := gotuga.New(400, 400, color.White)
+//	t.Circle(100)
+//	notebook.Display(t)
+//
+// In gophernotes, make an Image the cell's last value:
+//
+//	notebook.Image{Turtle: t}
+package notebook
+
+import (
+	"bytes"
+	"image/png"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/janpfeifer/gonb/gonbui"
+)
+
+// Display shows the turtle's canvas as a PNG in the current GoNB cell.
+func Display(t *gotuga.Turtle) error {
+	b, err := encode(t)
+	if err != nil {
+		return err
+	}
+	gonbui.DisplayPNG(b)
+	return nil
+}
+
+// Image is a turtle that notebooks which look for display methods, like
+// gophernotes, show as its canvas.
+type Image struct {
+	Turtle *gotuga.Turtle
+}
+
+// PNG returns the canvas encoded as PNG, or nil if encoding fails.
+func (img Image) PNG() []byte {
+	b, _ := encode(img.Turtle)
+	return b
+}
+
+// HTML returns an img element holding the canvas, for front ends that
+// prefer HTML.
+func (img Image) HTML() string {
+	uri, err := img.Turtle.DataURI()
+	if err != nil {
+		return ""
+	}
+	return `<img src="` + uri + `">`
+}
+
+func encode(t *gotuga.Turtle) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, t.Image()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
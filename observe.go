@@ -0,0 +1,144 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+	"time"
+)
+
+// Command describes a single turtle operation as seen by observers.
+// Colors are passed as four 0–255 components (non-premultiplied RGBA).
+type Command struct {
+	Name string    `json:"cmd"`
+	Args []float64 `json:"args,omitempty"`
+}
+
+type observer struct {
+	fn    func(Command)
+	frame func() // real-time frame boundary, see observeFrames
+}
+
+// Observe registers fn to be called after every top-level command the turtle
+// executes. Commands issued internally by shapes (e.g. the Forward calls made
+// by Circle) are not reported separately. The returned function detaches fn.
+func (t *Turtle) Observe(fn func(Command)) (cancel func()) {
+	return t.addObserver(&observer{fn: fn})
+}
+
+// observeFrames registers fn to be called at every frame boundary crossed
+// while moving in real-time mode.
+func (t *Turtle) observeFrames(fn func()) (cancel func()) {
+	return t.addObserver(&observer{frame: fn})
+}
+
+func (t *Turtle) addObserver(o *observer) (cancel func()) {
+	t.observers = append(t.observers, o)
+	return func() {
+		for i, other := range t.observers {
+			if other == o {
+				t.observers = append(t.observers[:i:i], t.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// track marks the start of a command and returns the function that reports it
+// once it finishes. Use as: defer t.track("forward", d)(), after checking
+// t.refuse("forward").
+//
+// The outermost command holds the canvas lock while it runs, so other
+// goroutines can copy the canvas between commands.
+func (t *Turtle) track(name string, args ...float64) func() {
+	t.enter()
+	return func() { t.leave(Command{Name: name, Args: args}) }
+}
+
+// trackColor is like track but reports the value *c holds once the command
+// has run, so ignored nil colors are reported as the color actually in effect.
+func (t *Turtle) trackColor(name string, c *color.Color) func() {
+	t.enter()
+	return func() { t.leave(Command{Name: name, Args: colorArgs(*c)}) }
+}
+
+func (t *Turtle) enter() {
+	if t.depth == 0 {
+		t.screen.mu.Lock()
+		if t.profiler != nil {
+			t.profStart, t.painted = time.Now(), 0
+		}
+	}
+	t.depth++
+}
+
+func (t *Turtle) leave(c Command) {
+	t.depth--
+	if t.depth > 0 {
+		return
+	}
+	t.screen.version++
+	t.screen.mu.Unlock()
+	t.steps++
+	if t.recordHistory {
+		t.history = append(t.history, c)
+	}
+	if t.annotating {
+		t.noteWaypoint()
+	}
+	if t.trace != nil {
+		t.writeTrace(c)
+	}
+	if t.profiler != nil && !t.profStart.IsZero() {
+		t.profiler(Profile{Name: c.Name, Duration: time.Since(t.profStart), Pixels: t.painted})
+	}
+	t.notify(c)
+}
+
+// Snapshot returns a copy of the canvas, as Image has it, taken between
+// commands. It is safe to call from any goroutine while turtles draw, say
+// to save progress or serve previews: it waits for the command running,
+// if any, and holds up drawing only while it copies the pixels.
+func (t *Turtle) Snapshot() *image.RGBA {
+	img, _ := t.copyCanvas()
+	return img
+}
+
+// copyCanvas returns a copy of the canvas and its version, taken between
+// commands. It is safe to call from any goroutine.
+func (t *Turtle) copyCanvas() (*image.RGBA, uint64) {
+	t.screen.mu.Lock()
+	defer t.screen.mu.Unlock()
+	return cloneRGBA(t.pixels()), t.screen.version
+}
+
+// canvasVersion reports how many commands have changed the turtle so far.
+// It is safe to call from any goroutine.
+func (t *Turtle) canvasVersion() uint64 {
+	t.screen.mu.Lock()
+	defer t.screen.mu.Unlock()
+	return t.screen.version
+}
+
+func (t *Turtle) notify(c Command) {
+	for _, o := range t.observers {
+		if o.fn != nil {
+			o.fn(c)
+		}
+	}
+}
+
+func (t *Turtle) notifyFrame() {
+	for _, o := range t.observers {
+		if o.frame != nil {
+			o.frame()
+		}
+	}
+}
+
+func colorArgs(c color.Color) []float64 {
+	if c == nil {
+		return nil
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return []float64{float64(n.R), float64(n.G), float64(n.B), float64(n.A)}
+}
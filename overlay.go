@@ -0,0 +1,117 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+)
+
+// overlay is a transparent layer the size of a turtle's canvas, for
+// markers and labels whose size is fixed in pixels however the drawing is
+// scaled. Positions are in the turtle's logical coordinates.
+type overlay struct {
+	t *Turtle // draws the layer, with the same coordinates
+}
+
+func newOverlay(src *Turtle) *overlay {
+	o := New(src.W, src.H, nil)
+	o.scale, o.quality = src.scale, Best
+	return &overlay{t: o}
+}
+
+// image returns the layer.
+func (o *overlay) image() *image.RGBA { return o.t.pixels() }
+
+// line draws a line w pixels wide.
+func (o *overlay) line(x0, y0, x1, y1, w float64, c color.Color) {
+	o.t.endStroke()
+	o.t.drawSegment(x0, y0, x1, y1, w/o.t.scale, c)
+}
+
+// dot draws a disc r pixels in radius.
+func (o *overlay) dot(x, y, r float64, c color.Color) {
+	o.t.endStroke()
+	px, py := o.t.CanvasPoint(x, y)
+	o.t.drawDisc(px, py, r, color.NRGBAModel.Convert(c).(color.NRGBA))
+}
+
+// arrow draws an arrowhead size pixels long with its tip at (x, y),
+// pointing deg degrees counterclockwise from east.
+func (o *overlay) arrow(x, y, deg, size float64, c color.Color) {
+	px, py := o.t.CanvasPoint(x, y)
+	sin, cos := math.Sincos(deg * math.Pi / 180)
+	// Back along the arrow, and across it; y grows downwards.
+	bx, by := -cos*size, sin*size
+	nx, ny := -by*0.4, bx*0.4
+	pts := [][2]float64{{px, py}, {px + bx + nx, py + by + ny}, {px + bx - nx, py + by - ny}}
+	o.t.endStroke()
+	drawPolygon(o.t.pixels(), pts, NonZero, c, nil, false, true)
+}
+
+// label writes s with the tiny built-in font, on a pale box, just above,
+// or below, and to the right of (x, y), or of its left if that would run
+// off the canvas. Points off the canvas get no label.
+func (o *overlay) label(x, y float64, s string, c color.Color, below bool) {
+	const k = 2 // pixels per font pixel
+	px, py := o.t.CanvasPoint(x, y)
+	if px < 0 || py < 0 || px > float64(o.t.W) || py > float64(o.t.H) {
+		return
+	}
+	w, h := k*(4*len([]rune(s))-1), k*5
+	x0, y0 := int(px)+6, int(py)-6-h
+	if below {
+		y0 = int(py) + 6
+	}
+	if x0+w+k > o.t.W {
+		x0 = int(px) - 6 - w
+	}
+	y0 = max(k, min(y0, o.t.H-h-k))
+	img := o.t.pixels()
+	box := image.Rect(x0-k, y0-k, x0+w+k, y0+h+k)
+	drawClipped(img, box, &image.Uniform{C: color.NRGBA{255, 255, 255, 200}}, image.Point{}, nil, false)
+	ink := color.RGBAModel.Convert(c).(color.RGBA)
+	for i, r := range []rune(s) {
+		g := tinyFont[r]
+		for row := 0; row < 5; row++ {
+			for col := 0; col < 3; col++ {
+				if g[row]&(4>>col) != 0 {
+					gx, gy := x0+k*(4*i+col), y0+k*row
+					fillRect(img, image.Rect(gx, gy, gx+k, gy+k), ink)
+				}
+			}
+		}
+	}
+}
+
+// coords formats a point for a label, to a tenth of a unit.
+func coords(x, y float64) string {
+	return "(" + tenths(x) + ", " + tenths(y) + ")"
+}
+
+func tenths(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10+0, 'f', -1, 64)
+}
+
+// tinyFont has 3×5 pixel glyphs for numbers and coordinates, a row of
+// three bits, leftmost highest, per line.
+var tinyFont = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'-': {0, 0, 7, 0, 0},
+	'.': {0, 0, 0, 0, 2},
+	',': {0, 0, 0, 2, 4},
+	'(': {1, 2, 2, 2, 1},
+	')': {4, 2, 2, 2, 4},
+	'°': {2, 5, 2, 0, 0},
+	'#': {5, 7, 5, 7, 5},
+	' ': {},
+}
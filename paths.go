@@ -0,0 +1,141 @@
+package gotuga
+
+import (
+	"image/color"
+	"math"
+)
+
+// Path is a line or filled shape drawn on the canvas, kept as geometry so
+// drawings can be exported as vectors. Points are in logical coordinates,
+// as drawn (after any isometric projection).
+type Path struct {
+	Points [][2]float64
+	Color  color.Color // pen color, nil for a fill
+	Width  float64     // pen width in logical units
+	Fill   color.Color // fill color of a closed shape, nil for a line
+}
+
+// SetRetainPaths sets whether the canvas keeps what is drawn on it as
+// paths, for Paths and the vector exporters that read them. It is off by
+// default, as a drawing that runs for long keeps ever more of them; turn
+// it on before drawing what is to be exported. Turning it off forgets the
+// paths kept so far. It applies to every turtle sharing the canvas.
+func (t *Turtle) SetRetainPaths(on bool) {
+	t.screen.mu.Lock()
+	defer t.screen.mu.Unlock()
+	t.screen.retainPaths = on
+	if !on {
+		t.clearPaths()
+	}
+}
+
+// Paths returns every path drawn on the canvas, by this turtle and any
+// sharing its canvas, in drawing order, since it was last cleared, if
+// paths are retained (see SetRetainPaths). It is safe to call from any
+// goroutine.
+func (t *Turtle) Paths() []Path {
+	t.screen.mu.Lock()
+	defer t.screen.mu.Unlock()
+	out := make([]Path, len(t.screen.paths))
+	for i, p := range t.screen.paths {
+		out[i] = *p
+		out[i].Points = append([][2]float64(nil), p.Points...)
+	}
+	return out
+}
+
+// retain adds the segment (ax, ay)–(bx, by), just drawn with the pen, to
+// the turtle's current path, or starts a new one if the segment does not
+// continue it.
+func (t *Turtle) retain(ax, ay, bx, by float64) {
+	if !t.screen.retainPaths {
+		return
+	}
+	p := t.path
+	if p == nil || p.Color != t.penColor || p.Width != t.penWidth ||
+		p.Points[len(p.Points)-1] != [2]float64{ax, ay} {
+		p = &Path{Points: [][2]float64{{ax, ay}}, Color: t.penColor, Width: t.penWidth}
+		t.screen.paths = append(t.screen.paths, p)
+		t.path = p
+	}
+	p.Points = append(p.Points, [2]float64{bx, by})
+}
+
+// retainShape adds a finished path, such as a fill, ending the turtle's
+// current one.
+func (t *Turtle) retainShape(p *Path) {
+	if !t.screen.retainPaths {
+		return
+	}
+	t.screen.paths = append(t.screen.paths, p)
+	t.path = nil
+}
+
+// clearPaths forgets every path on the canvas.
+func (t *Turtle) clearPaths() {
+	t.screen.paths = nil
+	for _, o := range t.screen.turtles {
+		o.path = nil
+	}
+}
+
+// SimplifyPath removes points from the retained paths, as returned by
+// Paths, that lie within tolerance logical units of the simplified line,
+// using the Douglas–Peucker algorithm. Exporting afterwards gives far
+// smaller files for drawings made of many tiny segments, such as circles
+// and random walks. The canvas is not changed.
+func (t *Turtle) SimplifyPath(tolerance float64) {
+	t.screen.mu.Lock()
+	defer t.screen.mu.Unlock()
+	for _, p := range t.screen.paths {
+		p.Points = Simplify(p.Points, tolerance)
+	}
+}
+
+// Simplify returns the points of a polyline that the Douglas–Peucker
+// algorithm keeps for the given tolerance: the first and last points, and
+// enough in between that no point removed is further than tolerance from
+// the result.
+func Simplify(pts [][2]float64, tolerance float64) [][2]float64 {
+	if len(pts) < 3 {
+		return append([][2]float64(nil), pts...)
+	}
+	keep := make([]bool, len(pts))
+	keep[0], keep[len(pts)-1] = true, true
+	// An explicit stack of ranges, as paths may have many thousands of
+	// points.
+	stack := [][2]int{{0, len(pts) - 1}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		far, dist := -1, tolerance
+		for i := r[0] + 1; i < r[1]; i++ {
+			if d := segmentDistance(pts[i], pts[r[0]], pts[r[1]]); d > dist {
+				far, dist = i, d
+			}
+		}
+		if far >= 0 {
+			keep[far] = true
+			stack = append(stack, [2]int{r[0], far}, [2]int{far, r[1]})
+		}
+	}
+	var out [][2]float64
+	for i, p := range pts {
+		if keep[i] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// segmentDistance returns the distance from p to the segment a–b.
+func segmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	s := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / l2
+	s = math.Max(0, math.Min(1, s))
+	return math.Hypot(p[0]-a[0]-s*dx, p[1]-a[1]-s*dy)
+}
@@ -0,0 +1,115 @@
+package plot
+
+import (
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// AxesOptions configures DrawAxes. The zero value draws axes across the
+// canvas with ticks at round values, every one labeled.
+type AxesOptions struct {
+	// The extent of the axes in the turtle's logical coordinates; all
+	// zero spans the canvas less a margin of 5% on each side.
+	XMin, XMax, YMin, YMax float64
+
+	// Tick is the spacing of tick marks, and LabelEvery how many ticks
+	// apart labels are. Zero picks round spacings for each axis and
+	// labels every tick.
+	Tick       float64
+	LabelEvery int
+
+	TickSize  float64 // length of tick marks, 5 if zero
+	ArrowSize float64 // length of the arrowheads, 3 tick sizes if zero
+
+	// Label draws each label centered at (x, y), as Plot.Label does; nil
+	// writes them in the Hershey font.
+	Label func(t *gotuga.Turtle, x, y float64, text string)
+}
+
+// DrawAxes draws x and y axes in the turtle's own coordinates, crossing at
+// the origin or at the edge of the extent nearest it, with arrowheads at
+// their positive ends and labeled tick marks. Like everything the turtle
+// draws, they are strokes in the pen color and width, so they export with
+// the rest of the drawing. The turtle does not move.
+func DrawAxes(t *gotuga.Turtle, opts AxesOptions) {
+	if opts.XMin == 0 && opts.XMax == 0 && opts.YMin == 0 && opts.YMax == 0 {
+		hw, hh := float64(t.W)/2/t.Scale(), float64(t.H)/2/t.Scale()
+		opts.XMin, opts.XMax, opts.YMin, opts.YMax = -0.95*hw, 0.95*hw, -0.95*hh, 0.95*hh
+	}
+	if !(opts.XMax > opts.XMin && opts.YMax > opts.YMin) {
+		return
+	}
+	if opts.TickSize <= 0 {
+		opts.TickSize = 5
+	}
+	if opts.ArrowSize <= 0 {
+		opts.ArrowSize = 3 * opts.TickSize
+	}
+	if opts.LabelEvery <= 0 {
+		opts.LabelEvery = 1
+	}
+	// A plot whose window is its area, so world and logical coordinates
+	// are the same.
+	p := &Plot{
+		T:    t,
+		XMin: opts.XMin, XMax: opts.XMax, YMin: opts.YMin, YMax: opts.YMax,
+		Left: opts.XMin, Bottom: opts.YMin, Width: opts.XMax - opts.XMin, Height: opts.YMax - opts.YMin,
+		TickSize: opts.TickSize,
+		Label:    opts.Label,
+	}
+	ax := clamp(0, p.XMin, p.XMax) // where the y axis crosses
+	ay := clamp(0, p.YMin, p.YMax) // where the x axis crosses
+	s, a := opts.TickSize, opts.ArrowSize
+
+	p.stroke(p.XMin, ay, p.XMax, ay)
+	p.stroke(p.XMax-a, ay+a/3, p.XMax, ay)
+	p.stroke(p.XMax, ay, p.XMax-a, ay-a/3)
+	first, ticks := axisTicks(p.XMin, p.XMax-a, ax, opts.Tick)
+	for i, x := range ticks {
+		if x == ax {
+			continue
+		}
+		p.stroke(x, ay-s/2, x, ay+s/2)
+		if (first+i)%opts.LabelEvery == 0 {
+			p.label(x, ay-s*2.5, format(x))
+		}
+	}
+
+	p.stroke(ax, p.YMin, ax, p.YMax)
+	p.stroke(ax-a/3, p.YMax-a, ax, p.YMax)
+	p.stroke(ax, p.YMax, ax+a/3, p.YMax-a)
+	first, ticks = axisTicks(p.YMin, p.YMax-a, ay, opts.Tick)
+	for i, y := range ticks {
+		if y == ay {
+			continue
+		}
+		p.stroke(ax-s/2, y, ax+s/2, y)
+		if (first+i)%opts.LabelEvery == 0 {
+			p.labelBeside(ax-s/2, y, -1, format(y))
+		}
+	}
+	p.done()
+}
+
+// axisTicks returns the ticks between lo and hi, step apart counting from
+// origin, or the spacing Ticks picks if step is 0, and the number of the
+// first one counting from the origin, so that every n-th from it can be
+// labeled. Ticks finer than a thousand across return none.
+func axisTicks(lo, hi, origin, step float64) (first int, ticks []float64) {
+	if step <= 0 {
+		nice := Ticks(lo, hi)
+		if len(nice) < 2 {
+			return 0, nil
+		}
+		step = nice[1] - nice[0]
+	}
+	if (hi-lo)/step > 1000 {
+		return 0, nil
+	}
+	first = int(math.Ceil((lo - origin) / step))
+	for i := first; origin+float64(i)*step <= hi+step*1e-9; i++ {
+		ticks = append(ticks, round12(origin+float64(i)*step))
+	}
+	return first, ticks
+}
@@ -0,0 +1,111 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// BarChart draws axes and one bar per value, with bar i centered on
+// x = i+1, on a window fitted to the values. Bars are filled with fill and
+// outlined in the pen color.
+func BarChart(t *gotuga.Turtle, values []float64, fill color.Color) *Plot {
+	lo, hi := bounds(values)
+	lo, hi = niceBounds(math.Min(lo, 0), math.Max(hi, 0))
+	p := New(t, 0, float64(len(values))+0.6, lo, hi)
+	p.Bars(values, 0.8, fill)
+	p.Axes()
+	return p
+}
+
+// ScatterChart draws axes and a dot of the given radius, in logical units,
+// at every point, on a window fitted to the points.
+func ScatterChart(t *gotuga.Turtle, xs, ys []float64, radius float64, fill color.Color) *Plot {
+	xlo, xhi := niceBounds(bounds(xs))
+	ylo, yhi := niceBounds(bounds(ys))
+	p := New(t, xlo, xhi, ylo, yhi)
+	p.Axes()
+	p.Scatter(xs, ys, radius, fill)
+	return p
+}
+
+// Bars draws a bar from y = 0 to values[i] centered on x = i+1, width world
+// units wide, clipped to the window. Bars are filled with fill, which
+// becomes the turtle's fill color, and outlined in the pen color.
+func (p *Plot) Bars(values []float64, width float64, fill color.Color) {
+	p.T.FillColor(fill)
+	xmin, xmax, _, _ := p.window()
+	for i, v := range values {
+		x := float64(i + 1)
+		x0, x1 := math.Max(x-width/2, xmin), math.Min(x+width/2, xmax)
+		y0, y1 := clamp(0, p.YMin, p.YMax), clamp(v, p.YMin, p.YMax)
+		if !finite(v) || x0 >= x1 || y0 == y1 {
+			continue
+		}
+		lx0, ly0 := p.Map(x0, y0)
+		lx1, ly1 := p.Map(x1, y1)
+		p.stroke(lx0, ly0, lx0, ly0)
+		p.T.BeginFill()
+		p.T.GoTo(lx1, ly0)
+		p.T.GoTo(lx1, ly1)
+		p.T.GoTo(lx0, ly1)
+		p.T.GoTo(lx0, ly0)
+		p.T.EndFill()
+	}
+	p.done()
+}
+
+// Scatter draws a dot of the given radius, in logical units, at every point
+// inside the window. Dots are filled with fill, which becomes the turtle's
+// fill color, and outlined in the pen color.
+func (p *Plot) Scatter(xs, ys []float64, radius float64, fill color.Color) {
+	t := p.T
+	t.FillColor(fill)
+	heading := t.Heading()
+	xmin, xmax, ymin, ymax := p.window()
+	n := min(len(xs), len(ys))
+	for i := 0; i < n; i++ {
+		x, y := xs[i], ys[i]
+		if !finite(x, y) || x < xmin || x > xmax || y < ymin || y > ymax {
+			continue
+		}
+		lx, ly := p.Map(x, y)
+		// Circle is centered to the turtle's left, north when facing east.
+		p.stroke(lx, ly-radius, lx, ly-radius)
+		t.SetHeading(0)
+		t.BeginFill()
+		t.Circle(radius)
+		t.EndFill()
+	}
+	t.SetHeading(heading)
+	p.done()
+}
+
+// bounds returns the smallest and largest finite values, or 0 and 1 when
+// there are none.
+func bounds(vs []float64) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range vs {
+		if finite(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	if lo > hi {
+		return 0, 1
+	}
+	return lo, hi
+}
+
+// niceBounds widens [lo, hi] out to the nearest tick values.
+func niceBounds(lo, hi float64) (float64, float64) {
+	if lo == hi {
+		lo, hi = lo-1, hi+1
+	}
+	ticks := Ticks(lo, hi)
+	if len(ticks) < 2 {
+		return lo, hi
+	}
+	step := ticks[1] - ticks[0]
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step
+}
@@ -0,0 +1,78 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/Z6dev/GoTuga/hershey"
+)
+
+// LegendEntry is one line of a legend: a series' color and its label.
+type LegendEntry struct {
+	Color color.Color
+	Label string
+}
+
+// Legend draws a box in a corner of the plot's area listing entries, each
+// a short line in its color followed by its label. The box is outlined and
+// the labels written in the pen color, and it is filled with background
+// unless that is nil; the turtle's fill color, if it had one, is restored
+// afterwards. The box is sized for labels in the Hershey font; if p.Label
+// is set it draws them, centered where those would be.
+func (p *Plot) Legend(corner Corner, background color.Color, entries ...LegendEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	t := p.T
+	s := p.TickSize
+	size := s * 2.4 // of the labels
+	row, pad, swatch := size*1.8, s*1.5, s*4
+	var tw float64
+	for _, e := range entries {
+		w, _ := hershey.Measure(e.Label, size)
+		tw = math.Max(tw, w)
+	}
+	w, h := pad+swatch+pad+tw+pad, float64(len(entries))*row+pad
+
+	x := p.Left + 2*s
+	if corner == BottomRight || corner == TopRight {
+		x = p.Left + p.Width - 2*s - w
+	}
+	y := p.Bottom + 2*s // the bottom of the box
+	if corner == TopLeft || corner == TopRight {
+		y = p.Bottom + p.Height - 2*s - h
+	}
+
+	box := func() {
+		p.stroke(x, y, x+w, y)
+		p.stroke(x+w, y, x+w, y+h)
+		p.stroke(x+w, y+h, x, y+h)
+		p.stroke(x, y+h, x, y)
+	}
+	if background != nil {
+		// Filled first, so the fill does not cover half the outline.
+		down, fill := t.IsDown(), t.Fill()
+		t.PenUp()
+		p.stroke(x, y, x, y)
+		t.FillColor(background)
+		t.BeginFill()
+		box()
+		t.EndFill()
+		t.FillColor(fill)
+		if down {
+			t.PenDown()
+		}
+	}
+	box()
+
+	pen := t.Color()
+	for i, e := range entries {
+		cy := y + h - pad/2 - row*(float64(i)+0.5)
+		t.SetColor(e.Color)
+		p.stroke(x+pad, cy, x+pad+swatch, cy)
+		t.SetColor(pen)
+		lw, _ := hershey.Measure(e.Label, size)
+		p.label(x+pad+swatch+pad+lw/2, cy, e.Label)
+	}
+	p.done()
+}
@@ -0,0 +1,269 @@
+// Package plot draws function plots with a turtle: axes with tick marks,
+// y = f(x) curves, polar curves and grids, point series, bar and scatter
+// charts, rulers, scale bars and legends, all in world coordinates.
+//
+//	p := plot.New(t, -2*math.Pi, 2*math.Pi, -1.5, 1.5)
+//	p.Axes()
+//	p.Func(math.Sin, 400)
+package plot
+
+import (
+	"math"
+	"strconv"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// Plot maps a window of world coordinates onto an area of the turtle's
+// canvas.
+type Plot struct {
+	T *gotuga.Turtle
+
+	// The world window.
+	XMin, XMax, YMin, YMax float64
+
+	// The area it is drawn in, in the turtle's logical coordinates.
+	Left, Bottom, Width, Height float64
+
+	// TickSize is the length of tick marks in logical units.
+	TickSize float64
+
+	// Label, when set, is called to draw each tick label, centered at
+	// (x, y) in logical coordinates.
+	Label func(t *gotuga.Turtle, x, y float64, text string)
+
+	atX, atY float64 // where the last stroke ended, in logical coordinates
+	at       bool
+}
+
+// New returns a plot of the given world window filling the turtle's
+// canvas, less a margin of 10% on each side.
+func New(t *gotuga.Turtle, xmin, xmax, ymin, ymax float64) *Plot {
+	w, h := float64(t.W), float64(t.H)
+	return &Plot{
+		T:    t,
+		XMin: xmin, XMax: xmax, YMin: ymin, YMax: ymax,
+		Left: -w * 0.4, Bottom: -h * 0.4, Width: w * 0.8, Height: h * 0.8,
+		TickSize: 5,
+	}
+}
+
+// Map converts world coordinates to the turtle's logical coordinates.
+func (p *Plot) Map(x, y float64) (float64, float64) {
+	return p.Left + (x-p.XMin)/(p.XMax-p.XMin)*p.Width,
+		p.Bottom + (y-p.YMin)/(p.YMax-p.YMin)*p.Height
+}
+
+// Axes draws the x and y axes through the origin, or along the window's
+// edge when the origin is outside it, with tick marks at round values.
+func (p *Plot) Axes() {
+	ax := clamp(0, p.XMin, p.XMax) // where the y axis crosses
+	ay := clamp(0, p.YMin, p.YMax) // where the x axis crosses
+	p.Line(p.XMin, ay, p.XMax, ay)
+	p.Line(ax, p.YMin, ax, p.YMax)
+
+	xlo, xhi, ylo, yhi := p.window()
+	for _, x := range Ticks(xlo, xhi) {
+		lx, ly := p.Map(x, ay)
+		p.stroke(lx, ly-p.TickSize/2, lx, ly+p.TickSize/2)
+		if p.Label != nil && x != ax {
+			p.Label(p.T, lx, ly-p.TickSize*2.5, format(x))
+		}
+	}
+	for _, y := range Ticks(ylo, yhi) {
+		lx, ly := p.Map(ax, y)
+		p.stroke(lx-p.TickSize/2, ly, lx+p.TickSize/2, ly)
+		if p.Label != nil && y != ay {
+			p.Label(p.T, lx-p.TickSize*2.5, ly, format(y))
+		}
+	}
+	p.done()
+}
+
+// Func plots y = f(x) across the window with the given number of
+// segments. The curve is clipped to the window and broken where f is not
+// finite, or jumps from beyond one edge of the window to beyond the other,
+// as at the poles of tan.
+func (p *Plot) Func(f func(x float64) float64, samples int) {
+	if samples < 1 {
+		samples = 1
+	}
+	_, _, ylo, yhi := p.window()
+	x0, y0 := p.XMin, f(p.XMin)
+	for i := 1; i <= samples; i++ {
+		x := p.XMin + (p.XMax-p.XMin)*float64(i)/float64(samples)
+		y := f(x)
+		if !(y0 > yhi && y < ylo || y0 < ylo && y > yhi) {
+			p.segment(x0, y0, x, y)
+		}
+		x0, y0 = x, y
+	}
+	p.done()
+}
+
+// Series plots the points (xs[i], ys[i]) joined by straight lines, clipped
+// to the window. Points that are not finite break the line.
+func (p *Plot) Series(xs, ys []float64) {
+	n := min(len(xs), len(ys))
+	for i := 1; i < n; i++ {
+		p.segment(xs[i-1], ys[i-1], xs[i], ys[i])
+	}
+	p.done()
+}
+
+// Polar plots the polar curve r(θ) for θ from theta0 to theta1 radians,
+// with the given number of segments, around the world origin.
+func (p *Plot) Polar(r func(theta float64) float64, theta0, theta1 float64, samples int) {
+	if samples < 1 {
+		samples = 1
+	}
+	xs := make([]float64, samples+1)
+	ys := make([]float64, samples+1)
+	for i := range xs {
+		th := theta0 + (theta1-theta0)*float64(i)/float64(samples)
+		rad := r(th)
+		xs[i], ys[i] = rad*math.Cos(th), rad*math.Sin(th)
+	}
+	p.Series(xs, ys)
+}
+
+// PolarGrid draws circles around the world origin at round radii and the
+// given number of evenly spaced spokes, clipped to the window.
+func (p *Plot) PolarGrid(spokes int) {
+	var rmax float64
+	for _, x := range []float64{p.XMin, p.XMax} {
+		for _, y := range []float64{p.YMin, p.YMax} {
+			rmax = math.Max(rmax, math.Hypot(x, y))
+		}
+	}
+	for _, rad := range Ticks(0, rmax) {
+		if rad > 0 {
+			p.Polar(func(float64) float64 { return rad }, 0, 2*math.Pi, 120)
+		}
+	}
+	for i := 0; i < spokes; i++ {
+		th := 2 * math.Pi * float64(i) / float64(spokes)
+		p.Line(0, 0, rmax*math.Cos(th), rmax*math.Sin(th))
+	}
+}
+
+// Line draws a straight line between two world points, clipped to the
+// window.
+func (p *Plot) Line(x0, y0, x1, y1 float64) {
+	p.segment(x0, y0, x1, y1)
+	p.done()
+}
+
+func (p *Plot) segment(x0, y0, x1, y1 float64) {
+	if !finite(x0, y0, x1, y1) {
+		return
+	}
+	x0, y0, x1, y1, ok := p.clip(x0, y0, x1, y1)
+	if !ok {
+		return
+	}
+	lx0, ly0 := p.Map(x0, y0)
+	lx1, ly1 := p.Map(x1, y1)
+	p.stroke(lx0, ly0, lx1, ly1)
+}
+
+// stroke draws between two logical points, moving the turtle only when the
+// line does not continue the previous one.
+func (p *Plot) stroke(x0, y0, x1, y1 float64) {
+	t := p.T
+	if !p.at {
+		p.atX, p.atY = t.Position()
+		p.at = true
+	}
+	t.JumpTo(x0, y0)
+	t.GoTo(x1, y1)
+}
+
+// done returns the turtle to where the drawing started.
+func (p *Plot) done() {
+	if !p.at {
+		return
+	}
+	p.T.JumpTo(p.atX, p.atY)
+	p.at = false
+}
+
+// window returns the world window with each axis's bounds in order; the
+// window may run either way, and Map turns it round.
+func (p *Plot) window() (xmin, xmax, ymin, ymax float64) {
+	return math.Min(p.XMin, p.XMax), math.Max(p.XMin, p.XMax),
+		math.Min(p.YMin, p.YMax), math.Max(p.YMin, p.YMax)
+}
+
+// clip clips a segment to the window (Liang–Barsky).
+func (p *Plot) clip(x0, y0, x1, y1 float64) (float64, float64, float64, float64, bool) {
+	xmin, xmax, ymin, ymax := p.window()
+	t0, t1 := 0.0, 1.0
+	dx, dy := x1-x0, y1-y0
+	edges := [4][2]float64{
+		{-dx, x0 - xmin}, {dx, xmax - x0},
+		{-dy, y0 - ymin}, {dy, ymax - y0},
+	}
+	for _, e := range edges {
+		q, r := e[0], e[1]
+		if q == 0 {
+			if r < 0 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+		u := r / q
+		if q < 0 {
+			t0 = math.Max(t0, u)
+		} else {
+			t1 = math.Min(t1, u)
+		}
+	}
+	if t0 > t1 {
+		return 0, 0, 0, 0, false
+	}
+	return x0 + t0*dx, y0 + t0*dy, x0 + t1*dx, y0 + t1*dy, true
+}
+
+// Ticks returns round values between lo and hi, about five to ten of them,
+// spaced 1, 2 or 5 times a power of ten apart.
+func Ticks(lo, hi float64) []float64 {
+	if !(hi > lo) || !finite(lo, hi) {
+		return nil
+	}
+	step := math.Pow(10, math.Floor(math.Log10((hi-lo)/5)))
+	for _, m := range []float64{1, 2, 5} {
+		if (hi-lo)/(step*m) <= 10 {
+			step *= m
+			break
+		}
+	}
+	var ticks []float64
+	for i := math.Ceil(lo / step); i*step <= hi+step*1e-9; i++ {
+		ticks = append(ticks, round12(i*step))
+	}
+	return ticks
+}
+
+// round12 rounds away float noise such as 0.30000000000000004, to 12
+// significant digits, and turns -0 into 0.
+func round12(v float64) float64 {
+	v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'g', 12, 64), 64)
+	return v + 0
+}
+
+func format(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+// clamp limits v to the range between lo and hi, in either order.
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(math.Min(lo, hi), math.Min(math.Max(lo, hi), v))
+}
+
+func finite(vs ...float64) bool {
+	for _, v := range vs {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package plot
+
+import (
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/hershey"
+)
+
+// Corner is a corner of a plot's area.
+type Corner int
+
+const (
+	BottomLeft Corner = iota
+	BottomRight
+	TopLeft
+	TopRight
+)
+
+// Edge is a side of a plot's area.
+type Edge int
+
+const (
+	EdgeBottom Edge = iota
+	EdgeTop
+	EdgeLeft
+	EdgeRight
+)
+
+// HersheyLabel returns a Label function writing text size units tall in
+// the Hershey font, as lines, in the pen color.
+func HersheyLabel(size float64) func(t *gotuga.Turtle, x, y float64, text string) {
+	return func(t *gotuga.Turtle, x, y float64, text string) {
+		x0, y0 := t.Position()
+		heading, down := t.Heading(), t.IsDown()
+		t.PenUp()
+		t.GoTo(x, y-size/2)
+		t.SetHeading(0)
+		hershey.Write(t, text, size, hershey.Center)
+		t.GoTo(x0, y0)
+		t.SetHeading(heading)
+		if down {
+			t.PenDown()
+		}
+	}
+}
+
+// label draws text centered at (x, y) with p.Label, or in the Hershey
+// font when it is not set.
+func (p *Plot) label(x, y float64, text string) {
+	label := p.Label
+	if label == nil {
+		label = HersheyLabel(p.TickSize * 2.4)
+	}
+	label(p.T, x, y, text)
+}
+
+// labelBeside draws text level with (x, y), to its right if dir is
+// positive and left if negative, clear of it by a tick size.
+func (p *Plot) labelBeside(x, y, dir float64, text string) {
+	off := p.TickSize * 2.5
+	if p.Label == nil {
+		w, _ := hershey.Measure(text, p.TickSize*2.4)
+		off = p.TickSize + w/2
+	}
+	p.label(x+dir*off, y, text)
+}
+
+// ScaleBar draws a bar length world units of x long in a corner of the
+// plot's area, labeled with the length and unit, such as "50 km". A length
+// of 0 or less picks a round one, the spacing of the x axis's ticks.
+func (p *Plot) ScaleBar(length float64, unit string, corner Corner) {
+	if length <= 0 {
+		xmin, xmax, _, _ := p.window()
+		ticks := Ticks(xmin, xmax)
+		if len(ticks) < 2 {
+			return
+		}
+		length = ticks[1] - ticks[0]
+	}
+	w := length / math.Abs(p.XMax-p.XMin) * p.Width
+	s := p.TickSize
+	x := p.Left + 2*s
+	if corner == BottomRight || corner == TopRight {
+		x = p.Left + p.Width - 2*s - w
+	}
+	y := p.Bottom + 2*s
+	if corner == TopLeft || corner == TopRight {
+		y = p.Bottom + p.Height - 5*s // room for the label above
+	}
+	p.stroke(x, y+s, x, y)
+	p.stroke(x, y, x+w, y)
+	p.stroke(x+w, y, x+w, y+s)
+	text := format(length)
+	if unit != "" {
+		text += " " + unit
+	}
+	p.label(x+w/2, y+s*2.5, text)
+	p.done()
+}
+
+// Ruler draws a ruler along an edge of the plot's area: major ticks with
+// labels at the values Ticks picks for that axis, and four or five minor
+// ticks between them. Ticks point into the area and labels sit outside it.
+func (p *Plot) Ruler(edge Edge) {
+	lo, hi, ylo, yhi := p.window()
+	if edge == EdgeLeft || edge == EdgeRight {
+		lo, hi = ylo, yhi
+	}
+	ticks := Ticks(lo, hi)
+	if len(ticks) < 2 {
+		return
+	}
+	step := ticks[1] - ticks[0]
+	// Steps of 2 divide into quarters, of 1 and 5 into fifths.
+	k := 5
+	if m := step / math.Pow(10, math.Floor(math.Log10(step))); math.Abs(m-2) < 1e-9 {
+		k = 4
+	}
+	minor := step / float64(k)
+
+	s := p.TickSize
+	// at returns the logical point of v on the edge, and the direction
+	// into the area.
+	at := func(v float64) (x, y, dx, dy float64) {
+		switch edge {
+		case EdgeTop:
+			x, _ = p.Map(v, 0)
+			return x, p.Bottom + p.Height, 0, -1
+		case EdgeLeft:
+			_, y = p.Map(0, v)
+			return p.Left, y, 1, 0
+		case EdgeRight:
+			_, y = p.Map(0, v)
+			return p.Left + p.Width, y, -1, 0
+		}
+		x, _ = p.Map(v, 0)
+		return x, p.Bottom, 0, 1
+	}
+	x0, y0, _, _ := at(lo)
+	x1, y1, _, _ := at(hi)
+	p.stroke(x0, y0, x1, y1)
+	for i := int(math.Ceil(lo / minor)); float64(i)*minor <= hi+minor*1e-9; i++ {
+		x, y, dx, dy := at(float64(i) * minor)
+		l := s
+		if i%k == 0 {
+			l = 2 * s
+		}
+		p.stroke(x, y, x+dx*l, y+dy*l)
+		if i%k == 0 {
+			text := format(round12(float64(i) * minor))
+			if dx != 0 {
+				p.labelBeside(x, y, -dx, text)
+			} else {
+				p.label(x, y-dy*s*2.5, text)
+			}
+		}
+	}
+	p.done()
+}
@@ -0,0 +1,292 @@
+// Package plotexport connects turtle drawings and gonum/plot figures. A
+// Plotter overlays a turtle's retained paths (see gotuga.Turtle.Paths) on a
+// plot in data coordinates, and a Canvas lets a plot draw itself with a
+// turtle. The Plotter needs the turtle to retain its paths, see
+// gotuga.Turtle.SetRetainPaths.
+//
+//	p := plot.New()
+//	p.Add(plotter.NewGrid(), plotexport.Plotter{Turtle: t})
+//	p.Save(4*vg.Inch, 4*vg.Inch, "overlay.png")
+//
+//	c := plotexport.NewCanvas(t)
+//	p.Draw(draw.New(c)) // the whole figure, drawn by the turtle
+package plotexport
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// ToPath converts points, such as a gotuga.Path's, into a vg.Path, mapping
+// each through tr.
+func ToPath(pts [][2]float64, tr func(x, y float64) vg.Point) vg.Path {
+	var p vg.Path
+	for i, pt := range pts {
+		if i == 0 {
+			p.Move(tr(pt[0], pt[1]))
+		} else {
+			p.Line(tr(pt[0], pt[1]))
+		}
+	}
+	return p
+}
+
+// Plotter draws a turtle's paths on a plot, taking logical coordinates as
+// data coordinates, so annotations drawn by the turtle line up with the
+// plotted data. Pen widths are taken as points.
+type Plotter struct {
+	Turtle *gotuga.Turtle
+}
+
+// Plot implements plot.Plotter.
+func (p Plotter) Plot(c draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&c)
+	tr := func(x, y float64) vg.Point { return vg.Point{X: trX(x), Y: trY(y)} }
+	for _, path := range p.Turtle.Paths() {
+		if len(path.Points) == 0 {
+			continue
+		}
+		vp := ToPath(path.Points, tr)
+		if path.Fill != nil {
+			vp.Close()
+			c.SetColor(path.Fill)
+			c.Fill(vp)
+			continue
+		}
+		c.SetColor(path.Color)
+		c.SetLineWidth(vg.Points(path.Width))
+		c.Stroke(vp)
+	}
+}
+
+// DataRange implements plot.DataRanger, so the plot's axes take in the
+// whole drawing.
+func (p Plotter) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, ymin = math.Inf(1), math.Inf(1)
+	xmax, ymax = math.Inf(-1), math.Inf(-1)
+	for _, path := range p.Turtle.Paths() {
+		for _, pt := range path.Points {
+			xmin, xmax = math.Min(xmin, pt[0]), math.Max(xmax, pt[0])
+			ymin, ymax = math.Min(ymin, pt[1]), math.Max(ymax, pt[1])
+		}
+	}
+	if xmin > xmax {
+		return 0, 0, 0, 0
+	}
+	return xmin, xmax, ymin, ymax
+}
+
+// Canvas is a vg.CanvasSizer that draws with a turtle, one point to a
+// logical unit, with the origin at the bottom left of the turtle's canvas.
+// Curves and arcs are flattened into short lines. Dashes are drawn solid
+// and text is not drawn. Drawing moves the turtle and changes its pen.
+type Canvas struct {
+	t     *gotuga.Turtle
+	m     affine
+	stack []canvasState
+	color color.Color
+	width vg.Length
+}
+
+type canvasState struct {
+	m     affine
+	color color.Color
+	width vg.Length
+}
+
+// NewCanvas returns a canvas the size of t's.
+func NewCanvas(t *gotuga.Turtle) *Canvas {
+	w, h := float64(t.W)/t.Scale(), float64(t.H)/t.Scale()
+	return &Canvas{t: t, m: affine{1, 0, 0, 1, -w / 2, -h / 2}, color: color.Black, width: 1}
+}
+
+// Size implements vg.CanvasSizer.
+func (c *Canvas) Size() (w, h vg.Length) {
+	return vg.Length(float64(c.t.W) / c.t.Scale()), vg.Length(float64(c.t.H) / c.t.Scale())
+}
+
+func (c *Canvas) SetLineWidth(w vg.Length)                          { c.width = w }
+func (c *Canvas) SetLineDash(pattern []vg.Length, offset vg.Length) {}
+func (c *Canvas) SetColor(col color.Color)                          { c.color = col }
+
+func (c *Canvas) Rotate(rad float64) {
+	s, co := math.Sincos(rad)
+	c.m = c.m.mul(affine{co, s, -s, co, 0, 0})
+}
+
+func (c *Canvas) Translate(pt vg.Point) {
+	c.m = c.m.mul(affine{1, 0, 0, 1, float64(pt.X), float64(pt.Y)})
+}
+
+func (c *Canvas) Scale(x, y float64) { c.m = c.m.mul(affine{x, 0, 0, y, 0, 0}) }
+
+func (c *Canvas) Push() { c.stack = append(c.stack, canvasState{c.m, c.color, c.width}) }
+
+func (c *Canvas) Pop() {
+	if len(c.stack) == 0 {
+		return
+	}
+	s := c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+	c.m, c.color, c.width = s.m, s.color, s.width
+}
+
+// Stroke draws the outline of p with the turtle's pen.
+func (c *Canvas) Stroke(p vg.Path) {
+	if c.color == nil || c.width <= 0 {
+		return
+	}
+	c.t.SetColor(c.color)
+	c.t.SetWidth(float64(c.width) * c.m.scale())
+	c.t.PenDown()
+	for _, pts := range c.flatten(p) {
+		c.t.DrawPolyline(pts)
+	}
+}
+
+// Fill fills each subpath of p.
+func (c *Canvas) Fill(p vg.Path) {
+	if c.color == nil {
+		return
+	}
+	c.t.FillColor(c.color)
+	c.t.PenUp()
+	for _, pts := range c.flatten(p) {
+		if len(pts) < 3 {
+			continue
+		}
+		c.t.GoTo(pts[0][0], pts[0][1])
+		c.t.BeginFill()
+		for _, pt := range pts {
+			c.t.GoTo(pt[0], pt[1])
+		}
+		c.t.EndFill()
+	}
+	c.t.PenDown()
+}
+
+// FillString does nothing: text is not drawn.
+func (c *Canvas) FillString(f font.Face, pt vg.Point, text string) {}
+
+// DrawImage stamps img, scaled to fit rect, unrotated.
+func (c *Canvas) DrawImage(rect vg.Rectangle, img image.Image) {
+	x0, y0 := c.m.apply(float64(rect.Min.X), float64(rect.Min.Y))
+	x1, y1 := c.m.apply(float64(rect.Max.X), float64(rect.Max.Y))
+	w, h := int(math.Round(math.Abs(x1-x0))), int(math.Round(math.Abs(y1-y0)))
+	if w <= 0 || h <= 0 {
+		return
+	}
+	b := img.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			scaled.Set(x, y, img.At(b.Min.X+x*b.Dx()/w, b.Min.Y+y*b.Dy()/h))
+		}
+	}
+	c.t.PenUp()
+	c.t.GoTo((x0+x1)/2, (y0+y1)/2)
+	c.t.StampImage(scaled)
+	c.t.PenDown()
+}
+
+// flatten turns p into polylines in logical coordinates, one per subpath.
+func (c *Canvas) flatten(p vg.Path) [][][2]float64 {
+	var out [][][2]float64
+	var cur [][2]float64
+	var start [2]float64
+	pos := [2]float64{}
+	lineTo := func(x, y float64) {
+		if len(cur) == 0 {
+			cur = [][2]float64{pos}
+		}
+		pos = [2]float64{x, y}
+		cur = append(cur, pos)
+	}
+	flush := func() {
+		if len(cur) > 1 {
+			out = append(out, c.transform(cur))
+		}
+		cur = nil
+	}
+	for _, comp := range p {
+		x, y := float64(comp.Pos.X), float64(comp.Pos.Y)
+		switch comp.Type {
+		case vg.MoveComp:
+			flush()
+			pos, start = [2]float64{x, y}, [2]float64{x, y}
+		case vg.LineComp:
+			lineTo(x, y)
+		case vg.ArcComp:
+			r := float64(comp.Radius)
+			n := int(math.Max(4, math.Ceil(math.Abs(comp.Angle)*r/2)))
+			for i := 0; i <= n; i++ {
+				a := comp.Start + comp.Angle*float64(i)/float64(n)
+				s, co := math.Sincos(a)
+				lineTo(x+r*co, y+r*s)
+			}
+		case vg.CurveComp:
+			p0 := pos
+			ctrl := comp.Control
+			n := 16
+			for i := 1; i <= n; i++ {
+				f := float64(i) / float64(n)
+				var bx, by float64
+				switch len(ctrl) {
+				case 1:
+					a, b, d := (1-f)*(1-f), 2*(1-f)*f, f*f
+					bx = a*p0[0] + b*float64(ctrl[0].X) + d*x
+					by = a*p0[1] + b*float64(ctrl[0].Y) + d*y
+				case 2:
+					a, b, d, e := (1-f)*(1-f)*(1-f), 3*(1-f)*(1-f)*f, 3*(1-f)*f*f, f*f*f
+					bx = a*p0[0] + b*float64(ctrl[0].X) + d*float64(ctrl[1].X) + e*x
+					by = a*p0[1] + b*float64(ctrl[0].Y) + d*float64(ctrl[1].Y) + e*y
+				default:
+					bx, by = x, y
+				}
+				lineTo(bx, by)
+			}
+		case vg.CloseComp:
+			lineTo(start[0], start[1])
+			flush()
+			pos = start
+		}
+	}
+	flush()
+	return out
+}
+
+func (c *Canvas) transform(pts [][2]float64) [][2]float64 {
+	out := make([][2]float64, len(pts))
+	for i, p := range pts {
+		out[i][0], out[i][1] = c.m.apply(p[0], p[1])
+	}
+	return out
+}
+
+// affine is the transform x' = a·x + c·y + e, y' = b·x + d·y + f.
+type affine struct{ a, b, c, d, e, f float64 }
+
+// mul returns the transform applying n first, then m.
+func (m affine) mul(n affine) affine {
+	return affine{
+		m.a*n.a + m.c*n.b, m.b*n.a + m.d*n.b,
+		m.a*n.c + m.c*n.d, m.b*n.c + m.d*n.d,
+		m.a*n.e + m.c*n.f + m.e, m.b*n.e + m.d*n.f + m.f,
+	}
+}
+
+func (m affine) apply(x, y float64) (float64, float64) {
+	return m.a*x + m.c*y + m.e, m.b*x + m.d*y + m.f
+}
+
+// scale returns how much the transform enlarges lengths, on average.
+func (m affine) scale() float64 {
+	return math.Sqrt(math.Abs(m.a*m.d - m.b*m.c))
+}
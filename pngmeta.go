@@ -0,0 +1,133 @@
+package gotuga
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"image/png"
+	"io"
+	"sort"
+	"unicode/utf8"
+)
+
+// HistoryHash returns a SHA-256 hex digest of the turtle's canvas
+// configuration and command history, as stored by Session. Two drawings
+// with the same hash were made by the same commands.
+func (t *Turtle) HistoryHash() string {
+	b, _ := json.Marshal(t.Session()) // a Session always marshals
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// SavePNGMetadata writes the canvas to a PNG file like SavePNG, with text
+// metadata; see WritePNGMetadata.
+func (t *Turtle) SavePNGMetadata(filename string, meta map[string]string) error {
+	return writeFile(filename, t.syncSaves, func(w io.Writer) error { return t.WritePNGMetadata(w, meta) })
+}
+
+// WritePNGMetadata encodes the canvas as PNG with a text chunk for each
+// entry of meta, such as "Title", "Author" or "Seed", so outputs can be
+// traced to what made them. A "History" entry holding HistoryHash is added
+// unless meta has one or the turtle does not record its history. Text that is not Latin-1 is stored as UTF-8 in an
+// iTXt chunk. Keys must be 1 to 79 printable Latin-1 characters.
+func (t *Turtle) WritePNGMetadata(w io.Writer, meta map[string]string) error {
+	keys := make([]string, 0, len(meta)+1)
+	for k := range meta {
+		if !validPNGKeyword(k) {
+			return fmt.Errorf("gotuga: invalid PNG text key %q", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	text := func(k string) string { return meta[k] }
+	if _, ok := meta["History"]; !ok && t.recordHistory {
+		keys = append(keys, "History")
+		hash := t.HistoryHash()
+		text = func(k string) string {
+			if k == "History" {
+				return hash
+			}
+			return meta[k]
+		}
+	}
+
+	var buf bytes.Buffer
+	end := t.profileEncode("png", t.canvas.Rect)
+	err := png.Encode(&buf, t.output())
+	end()
+	if err != nil {
+		return err
+	}
+	// The signature and IHDR chunk come first; text chunks may follow.
+	const ihdrEnd = 8 + 4 + 4 + 13 + 4
+	b := buf.Bytes()
+	if _, err := w.Write(b[:ihdrEnd]); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := writeTextChunk(w, k, text(k)); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(b[ihdrEnd:])
+	return err
+}
+
+// writeTextChunk writes a tEXt chunk, or an iTXt chunk if v is not Latin-1.
+func writeTextChunk(w io.Writer, k, v string) error {
+	data := append(latin1(k), 0)
+	typ := "tEXt"
+	if l := latin1(v); l != nil || v == "" {
+		data = append(data, l...)
+	} else {
+		// Uncompressed, with empty language tag and translated keyword.
+		typ = "iTXt"
+		data = append(data, 0, 0, 0, 0)
+		data = append(data, v...)
+	}
+	var hdr [8]byte
+	binary.BigEndian.PutUint32(hdr[:4], uint32(len(data)))
+	copy(hdr[4:], typ)
+	crc := crc32.NewIEEE()
+	crc.Write(hdr[4:])
+	crc.Write(data)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	for _, p := range [][]byte{hdr[:], data, sum[:]} {
+		if _, err := w.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// latin1 converts s to Latin-1, returning nil if it has other characters.
+func latin1(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r == utf8.RuneError || r > 0xff {
+			return nil
+		}
+		b = append(b, byte(r))
+	}
+	return b
+}
+
+// validPNGKeyword reports whether k may be a text chunk keyword: 1 to 79
+// printable Latin-1 characters without leading, trailing or double spaces.
+func validPNGKeyword(k string) bool {
+	l := latin1(k)
+	if len(l) == 0 || len(l) > 79 || l[0] == ' ' || l[len(l)-1] == ' ' || bytes.Contains(l, []byte("  ")) {
+		return false
+	}
+	for _, c := range l {
+		if c < 0x20 || (c > 0x7e && c < 0xa1) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package gotuga
+
+import "math"
+
+// BeginPoly starts capturing the turtle's path: its position now and after
+// every move, until EndPoly. Nothing is drawn differently.
+func (t *Turtle) BeginPoly() {
+	if t.refuse("beginpoly") {
+		return
+	}
+	defer t.track("beginpoly")()
+	t.polying = true
+	t.poly = [][2]float64{{t.x, t.y}}
+}
+
+// EndPoly stops capturing the path. The capture is kept until the next
+// BeginPoly.
+func (t *Turtle) EndPoly() {
+	if t.refuse("endpoly") {
+		return
+	}
+	defer t.track("endpoly")()
+	t.polying = false
+}
+
+// Poly returns a copy of the vertices captured since BeginPoly, in logical
+// coordinates.
+func (t *Turtle) Poly() [][2]float64 {
+	return append([][2]float64(nil), t.poly...)
+}
+
+// PathLength returns the length of the captured path, as walked.
+func (t *Turtle) PathLength() float64 {
+	var n float64
+	for i := 1; i < len(t.poly); i++ {
+		a, b := t.poly[i-1], t.poly[i]
+		n += math.Hypot(b[0]-a[0], b[1]-a[1])
+	}
+	return n
+}
+
+// PathArea returns the area enclosed by the captured path, closed back to
+// its first vertex. Parts of a self-crossing path that wind in opposite
+// directions cancel out.
+func (t *Turtle) PathArea() float64 {
+	return math.Abs(t.signedArea())
+}
+
+// PathCentroid returns the centroid of the area enclosed by the captured
+// path, closed back to its first vertex. A path enclosing no area gives the
+// average of its vertices, and an empty capture (0, 0).
+func (t *Turtle) PathCentroid() (x, y float64) {
+	n := len(t.poly)
+	if n == 0 {
+		return 0, 0
+	}
+	a := t.signedArea()
+	if math.Abs(a) < 1e-12 {
+		for _, p := range t.poly {
+			x += p[0]
+			y += p[1]
+		}
+		return x / float64(n), y / float64(n)
+	}
+	for i, p := range t.poly {
+		q := t.poly[(i+1)%n]
+		cross := p[0]*q[1] - q[0]*p[1]
+		x += (p[0] + q[0]) * cross
+		y += (p[1] + q[1]) * cross
+	}
+	return x / (6 * a), y / (6 * a)
+}
+
+// signedArea is the shoelace area of the captured path, positive when it
+// runs counterclockwise.
+func (t *Turtle) signedArea() float64 {
+	var a float64
+	for i, p := range t.poly {
+		q := t.poly[(i+1)%len(t.poly)]
+		a += p[0]*q[1] - q[0]*p[1]
+	}
+	return a / 2
+}
@@ -0,0 +1,36 @@
+package gotuga
+
+import (
+	"image"
+	"time"
+)
+
+// Profile is what one command, or one encoding of the canvas, cost.
+type Profile struct {
+	Name     string        // the command, as in History, or "encode png" and the like
+	Duration time.Duration // wall time, including the commands it ran
+	Pixels   int           // canvas pixels painted, or encoded
+}
+
+// SetProfiler calls fn with the cost of every top-level command and every
+// encoding of the canvas, so that for a drawing of millions of segments
+// one can find out whether strokes, fills or saving dominate. Summing
+// Profiles by Name gives a breakdown. A nil fn turns profiling off; it
+// costs nothing then.
+func (t *Turtle) SetProfiler(fn func(Profile)) {
+	t.profiler = fn
+	t.profStart = time.Time{}
+}
+
+// profileEncode reports encoding the canvas pixels in r as format to the
+// profiler. Use as: defer t.profileEncode("png", r)()
+func (t *Turtle) profileEncode(format string, r image.Rectangle) func() {
+	fn := t.profiler
+	if fn == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		fn(Profile{Name: "encode " + format, Duration: time.Since(start), Pixels: r.Dx() * r.Dy()})
+	}
+}
@@ -0,0 +1,796 @@
+package pyturtle
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/hexcolor"
+	"github.com/Z6dev/GoTuga/internal/random"
+)
+
+// builtins are the Python builtin functions.
+var builtins = map[string]any{
+	"range": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		start, n, step, err := rangeArgs("range", args)
+		if err != nil {
+			return nil, err
+		}
+		if n > maxRange {
+			return nil, fmt.Errorf("range() of %d numbers is too long, at most %d", n, maxRange)
+		}
+		items := make([]any, n)
+		for i := range items {
+			items[i] = float64(start + i*step)
+		}
+		return &list{items}, nil
+	}),
+	"len": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("len() takes exactly one argument (%d given)", len(args))
+		}
+		if s, ok := args[0].(string); ok {
+			return float64(len([]rune(s))), nil
+		}
+		items, err := iterate(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("object of type '%s' has no len()", typeName(args[0]))
+		}
+		return float64(len(items)), nil
+	}),
+	"int": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		v, err := toNumber("int", args)
+		return math.Trunc(v), err
+	}),
+	"float": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		return toNumber("float", args)
+	}),
+	"str": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		if len(args) == 0 {
+			return "", nil
+		}
+		return format(args[0]), nil
+	}),
+	"bool": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		return len(args) > 0 && truth(args[0]), nil
+	}),
+	"abs": mathFunc("abs", math.Abs),
+	"round": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		x, err := floats("round", args, 1, 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) == 1 {
+			return math.RoundToEven(x[0]), nil
+		}
+		p := math.Pow(10, x[1])
+		return math.RoundToEven(x[0]*p) / p, nil
+	}),
+	"min": minMax("min", -1),
+	"max": minMax("max", 1),
+	"sum": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("sum() takes exactly one argument (%d given)", len(args))
+		}
+		items, err := iterate(args[0])
+		if err != nil {
+			return nil, err
+		}
+		var s float64
+		for _, item := range items {
+			n, err := number(item)
+			if err != nil {
+				return nil, err
+			}
+			s += n
+		}
+		return s, nil
+	}),
+	"print": builtin(func(in *Interpreter, args []any, kw map[string]any) (any, error) {
+		parts := make([]string, len(args))
+		for i, a := range args {
+			parts[i] = format(a)
+		}
+		sep, end := " ", "\n"
+		if s, ok := kw["sep"].(string); ok {
+			sep = s
+		}
+		if s, ok := kw["end"].(string); ok {
+			end = s
+		}
+		_, err := fmt.Fprint(in.Output, strings.Join(parts, sep)+end)
+		return nil, err
+	}),
+}
+
+func toNumber(name string, args []any) (float64, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%s() takes exactly one argument (%d given)", name, len(args))
+	}
+	if s, ok := args[0].(string); ok {
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid literal for %s(): '%s'", name, s)
+		}
+		return n, nil
+	}
+	return number(args[0])
+}
+
+// minMax returns min (sign -1) or max (sign 1), which take a sequence or
+// several arguments.
+func minMax(name string, sign float64) builtin {
+	return func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		items := args
+		if len(args) == 1 {
+			var err error
+			if items, err = iterate(args[0]); err != nil {
+				return nil, err
+			}
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("%s() arg is an empty sequence", name)
+		}
+		best := items[0]
+		for _, item := range items[1:] {
+			ok, err := compare(">", item, best)
+			if err != nil {
+				return nil, err
+			}
+			if ok == (sign > 0) && !equal(item, best) {
+				best = item
+			}
+		}
+		return best, nil
+	}
+}
+
+// floats checks that a function got between lo and hi numeric arguments.
+func floats(name string, args []any, lo, hi int) ([]float64, error) {
+	if len(args) < lo || len(args) > hi {
+		if lo == hi {
+			return nil, fmt.Errorf("%s() takes %d arguments (%d given)", name, lo, len(args))
+		}
+		return nil, fmt.Errorf("%s() takes %d to %d arguments (%d given)", name, lo, hi, len(args))
+	}
+	out := make([]float64, len(args))
+	for i, a := range args {
+		n, err := number(a)
+		if err != nil {
+			return nil, fmt.Errorf("%s(): %v", name, err)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+func mathFunc(name string, fn func(float64) float64) builtin {
+	return func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		x, err := floats(name, args, 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		return fn(x[0]), nil
+	}
+}
+
+func mathFunc2(name string, fn func(a, b float64) float64) builtin {
+	return func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		x, err := floats(name, args, 2, 2)
+		if err != nil {
+			return nil, err
+		}
+		return fn(x[0], x[1]), nil
+	}
+}
+
+// module returns the module called name, or nil if there is none.
+func (in *Interpreter) module(name string) *module {
+	switch name {
+	case "turtle":
+		return in.turtleModule()
+	case "math":
+		return mathModule
+	case "random":
+		return randomModule
+	}
+	return nil
+}
+
+var mathModule = &module{"math", map[string]any{
+	"pi":      math.Pi,
+	"e":       math.E,
+	"tau":     2 * math.Pi,
+	"inf":     math.Inf(1),
+	"sin":     mathFunc("sin", math.Sin),
+	"cos":     mathFunc("cos", math.Cos),
+	"tan":     mathFunc("tan", math.Tan),
+	"asin":    mathFunc("asin", math.Asin),
+	"acos":    mathFunc("acos", math.Acos),
+	"atan":    mathFunc("atan", math.Atan),
+	"atan2":   mathFunc2("atan2", math.Atan2),
+	"sqrt":    mathFunc("sqrt", math.Sqrt),
+	"exp":     mathFunc("exp", math.Exp),
+	"floor":   mathFunc("floor", math.Floor),
+	"ceil":    mathFunc("ceil", math.Ceil),
+	"fabs":    mathFunc("fabs", math.Abs),
+	"hypot":   mathFunc2("hypot", math.Hypot),
+	"pow":     mathFunc2("pow", math.Pow),
+	"degrees": mathFunc("degrees", func(x float64) float64 { return x * 180 / math.Pi }),
+	"radians": mathFunc("radians", func(x float64) float64 { return x * math.Pi / 180 }),
+	"log": builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+		x, err := floats("log", args, 1, 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) == 2 {
+			return math.Log(x[0]) / math.Log(x[1]), nil
+		}
+		return math.Log(x[0]), nil
+	}),
+}}
+
+var randomModule = &module{"random", map[string]any{
+	"random": builtin(func(in *Interpreter, args []any, _ map[string]any) (any, error) {
+		if _, err := floats("random", args, 0, 0); err != nil {
+			return nil, err
+		}
+		return in.randFloat(), nil
+	}),
+	"uniform": builtin(func(in *Interpreter, args []any, _ map[string]any) (any, error) {
+		x, err := floats("uniform", args, 2, 2)
+		if err != nil {
+			return nil, err
+		}
+		return x[0] + (x[1]-x[0])*in.randFloat(), nil
+	}),
+	"randint": builtin(func(in *Interpreter, args []any, _ map[string]any) (any, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("randint() takes 2 arguments (%d given)", len(args))
+		}
+		a, err := integer(args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := integer(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if b < a {
+			return nil, fmt.Errorf("empty range for randint(%d, %d)", a, b)
+		}
+		return float64(a + in.randIntN(b-a+1)), nil
+	}),
+	"randrange": builtin(func(in *Interpreter, args []any, _ map[string]any) (any, error) {
+		start, n, step, err := rangeArgs("randrange", args)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("empty range for randrange()")
+		}
+		return float64(start + step*in.randIntN(n)), nil
+	}),
+	"choice": builtin(func(in *Interpreter, args []any, _ map[string]any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("choice() takes exactly one argument (%d given)", len(args))
+		}
+		items, err := iterate(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("cannot choose from an empty sequence")
+		}
+		return items[in.randIntN(len(items))], nil
+	}),
+	"seed": builtin(func(in *Interpreter, args []any, _ map[string]any) (any, error) {
+		x, err := floats("seed", args, 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		in.Rand = rand.New(rand.NewPCG(uint64(int64(x[0])), 0))
+		return nil, nil
+	}),
+}}
+
+func (in *Interpreter) randFloat() float64 { return random.Float64(in.Rand) }
+
+// maxRange is the most numbers range makes a list of; every one takes
+// some 24 bytes.
+const maxRange = 1 << 22
+
+// rangeArgs reads the arguments of range or randrange, returning the first
+// number, how many there are and the step between them.
+func rangeArgs(name string, args []any) (start, n, step int, err error) {
+	if len(args) < 1 || len(args) > 3 {
+		return 0, 0, 0, fmt.Errorf("%s expected 1 to 3 arguments, got %d", name, len(args))
+	}
+	v := [3]int{0, 0, 1}
+	for i, a := range args {
+		if v[i], err = integer(a); err != nil {
+			return 0, 0, 0, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	start, stop, step := v[0], v[1], v[2]
+	if len(args) == 1 {
+		start, stop = 0, v[0]
+	}
+	if step == 0 {
+		return 0, 0, 0, fmt.Errorf("%s() arg 3 must not be zero", name)
+	}
+	// Integers are at most 2⁵³, so none of this overflows.
+	switch {
+	case step > 0 && start < stop:
+		n = (stop - start + step - 1) / step
+	case step < 0 && start > stop:
+		n = (start - stop - step - 1) / -step
+	}
+	return start, n, step, nil
+}
+
+func (in *Interpreter) randIntN(n int) int { return random.IntN(in.Rand, n) }
+
+// pen is a turtle made with turtle.Turtle().
+type pen struct{ t *gotuga.Turtle }
+
+// screen is the object turtle.Screen() returns.
+type screen struct{}
+
+// turtleModule returns the turtle module, whose turtle functions drive the
+// interpreter's turtle.
+func (in *Interpreter) turtleModule() *module {
+	m := &module{"turtle", make(map[string]any)}
+	for name := range penFuncs {
+		m.attrs[name], _ = in.penMethod(in.t, name)
+	}
+	for name, fn := range screenFuncs {
+		m.attrs[name] = builtin(fn)
+	}
+	newTurtle := builtin(func(in *Interpreter, _ []any, _ map[string]any) (any, error) {
+		return &pen{in.t.Spawn()}, nil
+	})
+	m.attrs["Turtle"] = newTurtle
+	m.attrs["Pen"] = newTurtle
+	m.attrs["Screen"] = builtin(func(*Interpreter, []any, map[string]any) (any, error) { return screen{}, nil })
+	return m
+}
+
+// penMethod returns the turtle function name bound to t.
+func (in *Interpreter) penMethod(t *gotuga.Turtle, name string) (any, bool) {
+	fn, ok := penFuncs[name]
+	if !ok {
+		return nil, false
+	}
+	return builtin(func(in *Interpreter, args []any, kw map[string]any) (any, error) {
+		v, err := fn(in, t, args, kw)
+		if err != nil {
+			if _, ok := err.(*Error); !ok {
+				err = fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		return v, err
+	}), true
+}
+
+type penFunc func(in *Interpreter, t *gotuga.Turtle, args []any, kw map[string]any) (any, error)
+
+var penFuncs = map[string]penFunc{}
+
+func init() {
+	forward := move1(func(t *gotuga.Turtle, n float64) { t.Forward(n) })
+	backward := move1(func(t *gotuga.Turtle, n float64) { t.Backward(n) })
+	right := move1(func(t *gotuga.Turtle, n float64) { t.Right(n) })
+	left := move1(func(t *gotuga.Turtle, n float64) { t.Left(n) })
+	setheading := move1(func(t *gotuga.Turtle, n float64) { t.SetHeading(n) })
+	penup := pen0(func(t *gotuga.Turtle) { t.PenUp() })
+	pendown := pen0(func(t *gotuga.Turtle) { t.PenDown() })
+	position := getter(func(t *gotuga.Turtle) any { x, y := t.Position(); return tuple{x, y} })
+	pensize := penFunc(func(_ *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+		if len(args) == 0 {
+			return t.Width(), nil
+		}
+		w, err := floats("pensize", args, 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		t.SetWidth(w[0])
+		return nil, nil
+	})
+	goTo := penFunc(func(_ *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+		x, y, err := point(args)
+		if err != nil {
+			return nil, err
+		}
+		t.GoTo(x, y)
+		return nil, nil
+	})
+	ignored := penFunc(func(*Interpreter, *gotuga.Turtle, []any, map[string]any) (any, error) { return nil, nil })
+
+	for names, fn := range map[string]penFunc{
+		"forward fd":              forward,
+		"backward back bk":        backward,
+		"right rt":                right,
+		"left lt":                 left,
+		"setheading seth":         setheading,
+		"goto setpos setposition": goTo,
+		"penup pu up":             penup,
+		"pendown pd down":         pendown,
+		"pensize width":           pensize,
+		"position pos":            position,
+		"home":                    pen0(func(t *gotuga.Turtle) { t.Home() }),
+		"clear":                   pen0(func(t *gotuga.Turtle) { t.Clear() }),
+		"reset":                   pen0(func(t *gotuga.Turtle) { t.Reset() }),
+		"begin_fill":              pen0(func(t *gotuga.Turtle) { t.BeginFill() }),
+		"end_fill":                pen0(func(t *gotuga.Turtle) { t.EndFill() }),
+		"setx":                    move1(func(t *gotuga.Turtle, n float64) { _, y := t.Position(); t.GoTo(n, y) }),
+		"sety":                    move1(func(t *gotuga.Turtle, n float64) { x, _ := t.Position(); t.GoTo(x, n) }),
+		"xcor":                    getter(func(t *gotuga.Turtle) any { x, _ := t.Position(); return x }),
+		"ycor":                    getter(func(t *gotuga.Turtle) any { _, y := t.Position(); return y }),
+		"heading":                 getter(func(t *gotuga.Turtle) any { return math.Mod(math.Mod(t.Heading(), 360)+360, 360) }),
+		"isdown":                  getter(func(t *gotuga.Turtle) any { return t.IsDown() }),
+		"isvisible":               getter(func(*gotuga.Turtle) any { return true }),
+		"distance":                distance,
+		"towards":                 towards,
+		"circle":                  circle,
+		"dot":                     dot,
+		"pencolor":                pencolor,
+		"fillcolor":               fillcolor,
+		"color":                   colorBoth,
+		"stamp":                   getter(func(*gotuga.Turtle) any { return 0.0 }),
+		"speed shape shapesize turtlesize hideturtle ht showturtle st write tilt": ignored,
+	} {
+		for _, name := range strings.Fields(names) {
+			penFuncs[name] = fn
+		}
+	}
+}
+
+// move1 wraps a turtle command taking one number.
+func move1(fn func(t *gotuga.Turtle, n float64)) penFunc {
+	return func(_ *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("takes 1 argument (%d given)", len(args))
+		}
+		n, err := number(args[0])
+		if err != nil {
+			return nil, err
+		}
+		fn(t, n)
+		return nil, nil
+	}
+}
+
+// pen0 wraps a turtle command taking no arguments.
+func pen0(fn func(t *gotuga.Turtle)) penFunc {
+	return func(_ *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+		if len(args) != 0 {
+			return nil, fmt.Errorf("takes no arguments (%d given)", len(args))
+		}
+		fn(t)
+		return nil, nil
+	}
+}
+
+// getter wraps a query of the turtle's state.
+func getter(fn func(t *gotuga.Turtle) any) penFunc {
+	return func(_ *Interpreter, t *gotuga.Turtle, _ []any, _ map[string]any) (any, error) {
+		return fn(t), nil
+	}
+}
+
+// point reads a position given as x, y, as a pair, or as a turtle.
+func point(args []any) (x, y float64, err error) {
+	if len(args) == 1 {
+		switch v := args[0].(type) {
+		case *pen:
+			x, y = v.t.Position()
+			return x, y, nil
+		case tuple, *list:
+			items, _ := iterate(v)
+			args = items
+		}
+	}
+	xy, err := floats("position", args, 2, 2)
+	if err != nil {
+		return 0, 0, fmt.Errorf("want x, y or a position")
+	}
+	return xy[0], xy[1], nil
+}
+
+var distance = penFunc(func(_ *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+	x, y, err := point(args)
+	if err != nil {
+		return nil, err
+	}
+	cx, cy := t.Position()
+	return math.Hypot(x-cx, y-cy), nil
+})
+
+var towards = penFunc(func(_ *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+	x, y, err := point(args)
+	if err != nil {
+		return nil, err
+	}
+	cx, cy := t.Position()
+	a := math.Atan2(y-cy, x-cx) * 180 / math.Pi
+	return math.Mod(a+360, 360), nil
+})
+
+// circle draws a circle, or with extent an arc, of the given radius with
+// its center radius units left of the turtle, as Python does: a full circle
+// without steps is drawn smoothly, anything else as Python's polygon.
+var circle = penFunc(func(_ *Interpreter, t *gotuga.Turtle, args []any, kw map[string]any) (any, error) {
+	args = withKeywords(args, kw, "radius", "extent", "steps")
+	if len(args) < 1 || len(args) > 3 {
+		return nil, fmt.Errorf("takes 1 to 3 arguments (%d given)", len(args))
+	}
+	r, err := number(args[0])
+	if err != nil {
+		return nil, err
+	}
+	extent, steps := 360.0, 0
+	if len(args) > 1 && args[1] != nil {
+		if extent, err = number(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) > 2 && args[2] != nil {
+		if steps, err = integer(args[2]); err != nil {
+			return nil, err
+		}
+	}
+	if extent == 360 && steps == 0 {
+		t.Circle(r)
+		return nil, nil
+	}
+	if steps <= 0 {
+		frac := math.Abs(extent) / 360
+		steps = 1 + int(math.Min(11+math.Abs(r)/6, 59)*frac)
+	}
+	w := extent / float64(steps)
+	w2 := w / 2
+	l := 2 * r * math.Sin(w2*math.Pi/180)
+	if r < 0 {
+		l, w, w2 = -l, -w, -w2
+	}
+	t.Left(w2)
+	for i := 0; i < steps; i++ {
+		t.Forward(l)
+		t.Left(w)
+	}
+	t.Left(-w2)
+	return nil, nil
+})
+
+// dot draws a filled circle of the given diameter on the turtle, in the
+// given color or the pen color, whether or not the pen is down.
+var dot = penFunc(func(in *Interpreter, t *gotuga.Turtle, args []any, kw map[string]any) (any, error) {
+	args = withKeywords(args, kw, "size")
+	size := 0.0
+	if len(args) > 0 {
+		if n, err := number(args[0]); err == nil {
+			size = n
+			args = args[1:]
+		} else if args[0] == nil {
+			args = args[1:]
+		}
+	}
+	c := t.Color()
+	if len(args) > 0 {
+		var err error
+		if c, err = in.parseColor(args); err != nil {
+			return nil, err
+		}
+	}
+	t.Dot(size, c)
+	return nil, nil
+})
+
+// withKeywords appends keyword arguments to the positional ones in the
+// order of names, with None for any gaps.
+func withKeywords(args []any, kw map[string]any, names ...string) []any {
+	for i, name := range names {
+		v, ok := kw[name]
+		if !ok {
+			continue
+		}
+		for len(args) <= i {
+			args = append(args, nil)
+		}
+		args[i] = v
+	}
+	return args
+}
+
+var pencolor = penFunc(func(in *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+	if len(args) == 0 {
+		return in.colorValue(t.Color()), nil
+	}
+	c, err := in.parseColor(args)
+	if err != nil {
+		return nil, err
+	}
+	t.SetColor(c)
+	return nil, nil
+})
+
+var fillcolor = penFunc(func(in *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("reading the fill color is not supported")
+	}
+	c, err := in.parseColor(args)
+	if err != nil {
+		return nil, err
+	}
+	t.FillColor(c)
+	return nil, nil
+})
+
+// colorBoth sets the pen and fill colors: color(c) sets both, color(pen,
+// fill) each.
+var colorBoth = penFunc(func(in *Interpreter, t *gotuga.Turtle, args []any, _ map[string]any) (any, error) {
+	if len(args) == 0 {
+		return in.colorValue(t.Color()), nil
+	}
+	pc, fc := args, args
+	if len(args) == 2 {
+		pc, fc = args[:1], args[1:]
+	}
+	p, err := in.parseColor(pc)
+	if err != nil {
+		return nil, err
+	}
+	f, err := in.parseColor(fc)
+	if err != nil {
+		return nil, err
+	}
+	t.SetColor(p)
+	t.FillColor(f)
+	return nil, nil
+})
+
+// screenFuncs are the methods of the screen, also offered by the turtle
+// module.
+var screenFuncs = map[string]func(in *Interpreter, args []any, kw map[string]any) (any, error){
+	"bgcolor": func(in *Interpreter, args []any, _ map[string]any) (any, error) {
+		if len(args) == 0 {
+			return in.colorValue(in.t.Background()), nil
+		}
+		c, err := in.parseColor(args)
+		if err != nil {
+			return nil, fmt.Errorf("bgcolor: %w", err)
+		}
+		img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		img.Set(0, 0, c)
+		in.t.SetBackgroundImage(img)
+		return nil, nil
+	},
+	"colormode": func(in *Interpreter, args []any, _ map[string]any) (any, error) {
+		if len(args) == 0 {
+			return in.colorMode, nil
+		}
+		n, err := number(args[0])
+		if err != nil || n != 1 && n != 255 {
+			return nil, fmt.Errorf("colormode must be 1.0 or 255")
+		}
+		in.colorMode = n
+		return nil, nil
+	},
+	"window_width": func(in *Interpreter, _ []any, _ map[string]any) (any, error) {
+		return float64(in.t.W), nil
+	},
+	"window_height": func(in *Interpreter, _ []any, _ map[string]any) (any, error) {
+		return float64(in.t.H), nil
+	},
+	"setup": ignore, "screensize": ignore, "title": ignore, "tracer": ignore,
+	"update": ignore, "delay": ignore, "done": ignore, "mainloop": ignore,
+	"exitonclick": ignore, "bye": ignore, "listen": ignore,
+}
+
+func ignore(*Interpreter, []any, map[string]any) (any, error) { return nil, nil }
+
+// parseColor reads a color given as a name, as "#rrggbb", or as red, green
+// and blue, separately or as a tuple, scaled by the color mode.
+func (in *Interpreter) parseColor(args []any) (color.Color, error) {
+	if len(args) == 1 {
+		switch v := args[0].(type) {
+		case string:
+			return parseColorString(v)
+		case tuple, *list:
+			args, _ = iterate(v)
+		}
+	}
+	rgb, err := floats("color", args, 3, 3)
+	if err != nil {
+		return nil, fmt.Errorf("bad color arguments")
+	}
+	c := color.NRGBA{A: 255}
+	for i, v := range rgb {
+		if v < 0 || v > in.colorMode {
+			return nil, fmt.Errorf("bad color sequence: %s", format(tuple(args)))
+		}
+		b := uint8(math.Round(v / in.colorMode * 255))
+		switch i {
+		case 0:
+			c.R = b
+		case 1:
+			c.G = b
+		case 2:
+			c.B = b
+		}
+	}
+	return c, nil
+}
+
+func parseColorString(s string) (color.Color, error) {
+	if c, ok := colorNames[strings.ToLower(strings.ReplaceAll(s, " ", ""))]; ok {
+		return c, nil
+	}
+	h := s
+	if len(h) == 4 && h[0] == '#' {
+		h = string([]byte{'#', h[1], h[1], h[2], h[2], h[3], h[3]})
+	}
+	if c, err := hexcolor.Parse(h); err == nil && len(h) == 7 {
+		return c, nil
+	}
+	return nil, fmt.Errorf("bad color string: %s", s)
+}
+
+// colorValue returns c as a tuple in the current color mode.
+func (in *Interpreter) colorValue(c color.Color) tuple {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	k := in.colorMode / 255
+	return tuple{float64(n.R) * k, float64(n.G) * k, float64(n.B) * k}
+}
+
+// colorNames are the color names scripts use most, with their web colors.
+var colorNames = map[string]color.NRGBA{
+	"black":     {0, 0, 0, 255},
+	"white":     {255, 255, 255, 255},
+	"red":       {255, 0, 0, 255},
+	"green":     {0, 128, 0, 255},
+	"lime":      {0, 255, 0, 255},
+	"blue":      {0, 0, 255, 255},
+	"yellow":    {255, 255, 0, 255},
+	"cyan":      {0, 255, 255, 255},
+	"magenta":   {255, 0, 255, 255},
+	"orange":    {255, 165, 0, 255},
+	"purple":    {128, 0, 128, 255},
+	"pink":      {255, 192, 203, 255},
+	"brown":     {165, 42, 42, 255},
+	"gray":      {128, 128, 128, 255},
+	"grey":      {128, 128, 128, 255},
+	"lightgray": {211, 211, 211, 255},
+	"lightgrey": {211, 211, 211, 255},
+	"darkgray":  {169, 169, 169, 255},
+	"darkgrey":  {169, 169, 169, 255},
+	"silver":    {192, 192, 192, 255},
+	"navy":      {0, 0, 128, 255},
+	"darkblue":  {0, 0, 139, 255},
+	"lightblue": {173, 216, 230, 255},
+	"skyblue":   {135, 206, 235, 255},
+	"darkgreen": {0, 100, 0, 255},
+	"olive":     {128, 128, 0, 255},
+	"teal":      {0, 128, 128, 255},
+	"maroon":    {128, 0, 0, 255},
+	"gold":      {255, 215, 0, 255},
+	"violet":    {238, 130, 238, 255},
+	"indigo":    {75, 0, 130, 255},
+	"turquoise": {64, 224, 208, 255},
+	"salmon":    {250, 128, 114, 255},
+	"coral":     {255, 127, 80, 255},
+	"tomato":    {255, 99, 71, 255},
+	"crimson":   {220, 20, 60, 255},
+	"chocolate": {210, 105, 30, 255},
+	"tan":       {210, 180, 140, 255},
+	"beige":     {245, 245, 220, 255},
+	"khaki":     {240, 230, 140, 255},
+	"orchid":    {218, 112, 214, 255},
+	"plum":      {221, 160, 221, 255},
+}
@@ -0,0 +1,223 @@
+package pyturtle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	nameToken    tokenKind = iota // forward, for, t
+	numberToken                   // 100, 2.5, 1e3
+	stringToken                   // "red", 'blue'
+	opToken                       // operators and punctuation
+	newlineToken                  // the end of a logical line
+	indentToken                   // a deeper block starts
+	dedentToken                   // a block ends
+	eofToken
+)
+
+type token struct {
+	kind tokenKind
+	text string // as written, or a string's value
+	num  float64
+	line int
+}
+
+// operators lists the operators and punctuation, longest first.
+var operators = []string{
+	"**=", "//=",
+	"**", "//", "==", "!=", "<=", ">=", "+=", "-=", "*=", "/=", "%=", "->",
+	"+", "-", "*", "/", "%", "<", ">", "=", "(", ")", "[", "]", "{", "}", ",", ":", ".", ";",
+}
+
+// lex splits Python source into tokens, turning indentation into indent and
+// dedent tokens the way Python's tokenizer does. Lines inside brackets or
+// ending in a backslash are joined.
+func lex(src string) ([]token, error) {
+	var toks []token
+	rs := []rune(src)
+	line := 1
+	indents := []int{0}
+	depth := 0 // of open brackets
+	atStart := true
+	add := func(kind tokenKind, text string) {
+		toks = append(toks, token{kind: kind, text: text, line: line})
+	}
+	for i := 0; i < len(rs); {
+		if atStart && depth == 0 {
+			col, j := 0, i
+			for ; j < len(rs) && (rs[j] == ' ' || rs[j] == '\t'); j++ {
+				if rs[j] == '\t' {
+					col = col/8*8 + 8
+				} else {
+					col++
+				}
+			}
+			if j < len(rs) && (rs[j] == '\n' || rs[j] == '\r' || rs[j] == '#') {
+				for j < len(rs) && rs[j] != '\n' {
+					j++
+				}
+				i = j // a blank line: the newline is counted below
+				if i < len(rs) {
+					i++
+					line++
+				}
+				continue
+			}
+			i = j
+			if i == len(rs) {
+				break
+			}
+			atStart = false
+			switch top := indents[len(indents)-1]; {
+			case col > top:
+				indents = append(indents, col)
+				add(indentToken, "")
+			case col < top:
+				for col < indents[len(indents)-1] {
+					indents = indents[:len(indents)-1]
+					add(dedentToken, "")
+				}
+				if col != indents[len(indents)-1] {
+					return nil, &Error{line, fmt.Errorf("unindent does not match any outer indentation level")}
+				}
+			}
+			continue
+		}
+		r := rs[i]
+		switch {
+		case r == '\n':
+			if depth == 0 {
+				add(newlineToken, "")
+				atStart = true
+			}
+			line++
+			i++
+		case r == '\\' && i+1 < len(rs) && (rs[i+1] == '\n' || rs[i+1] == '\r'):
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+			i++
+			line++
+		case unicode.IsSpace(r):
+			i++
+		case r == '#':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '"' || r == '\'':
+			s, n, lines, err := lexString(rs[i:])
+			if err != nil {
+				return nil, &Error{line, err}
+			}
+			add(stringToken, s)
+			line += lines
+			i += n
+		case unicode.IsDigit(r) || r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1]):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.' || rs[j] == '_') {
+				j++
+			}
+			if j < len(rs) && (rs[j] == 'e' || rs[j] == 'E') {
+				k := j + 1
+				if k < len(rs) && (rs[k] == '+' || rs[k] == '-') {
+					k++
+				}
+				if k < len(rs) && unicode.IsDigit(rs[k]) {
+					for j = k; j < len(rs) && unicode.IsDigit(rs[j]); j++ {
+					}
+				}
+			}
+			text := string(rs[i:j])
+			n, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64)
+			if err != nil {
+				return nil, &Error{line, fmt.Errorf("invalid number %s", text)}
+			}
+			toks = append(toks, token{kind: numberToken, text: text, num: n, line: line})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(rs) && (rs[j] == '_' || unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			add(nameToken, string(rs[i:j]))
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(string(rs[i:min(i+3, len(rs))]), o) {
+					op = o
+					break
+				}
+			}
+			switch op {
+			case "":
+				return nil, &Error{line, fmt.Errorf("invalid character %q", r)}
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth = max(depth-1, 0)
+			}
+			add(opToken, op)
+			i += len(op)
+		}
+	}
+	if len(toks) > 0 && toks[len(toks)-1].kind != newlineToken {
+		add(newlineToken, "")
+	}
+	for len(indents) > 1 {
+		indents = indents[:len(indents)-1]
+		add(dedentToken, "")
+	}
+	add(eofToken, "")
+	return toks, nil
+}
+
+// lexString reads a string literal at the start of rs, returning its value,
+// how many runes it took up and how many newlines it spanned.
+func lexString(rs []rune) (s string, n, lines int, err error) {
+	q := rs[0]
+	triple := len(rs) >= 3 && rs[1] == q && rs[2] == q
+	i := 1
+	if triple {
+		i = 3
+	}
+	var b strings.Builder
+	for i < len(rs) {
+		r := rs[i]
+		switch {
+		case triple && r == q && i+2 < len(rs) && rs[i+1] == q && rs[i+2] == q:
+			return b.String(), i + 3, lines, nil
+		case !triple && r == q:
+			return b.String(), i + 1, lines, nil
+		case !triple && r == '\n':
+			return "", 0, 0, fmt.Errorf("unterminated string")
+		case r == '\\' && i+1 < len(rs):
+			i++
+			switch e := rs[i]; e {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			case '\\', '\'', '"':
+				b.WriteRune(e)
+			case '\n':
+				lines++ // a continued line
+			default:
+				b.WriteRune('\\')
+				b.WriteRune(e)
+			}
+		default:
+			if r == '\n' {
+				lines++
+			}
+			b.WriteRune(r)
+		}
+		i++
+	}
+	return "", 0, 0, fmt.Errorf("unterminated string")
+}
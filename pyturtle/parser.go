@@ -0,0 +1,884 @@
+package pyturtle
+
+import "fmt"
+
+// Statements.
+type (
+	stmt any
+
+	exprStmt struct {
+		x    expr
+		line int
+	}
+	// assignStmt assigns value to every target, or with op set, combines
+	// the single target with value as in x += 1.
+	assignStmt struct {
+		targets []expr
+		op      string
+		value   expr
+		line    int
+	}
+	ifStmt struct {
+		cond      expr
+		body, els []stmt
+		line      int
+	}
+	whileStmt struct {
+		cond expr
+		body []stmt
+		line int
+	}
+	forStmt struct {
+		target expr
+		iter   expr
+		body   []stmt
+		line   int
+	}
+	defStmt struct {
+		name     string
+		params   []string
+		defaults []expr // for the last len(defaults) params
+		body     []stmt
+		line     int
+	}
+	returnStmt struct {
+		x    expr // nil for a bare return
+		line int
+	}
+	// importStmt is import module [as alias] or from module import names.
+	importStmt struct {
+		module string
+		alias  string
+		from   bool
+		names  [][2]string // name and alias; nil with from for import *
+		line   int
+	}
+	globalStmt   struct{ names []string }
+	passStmt     struct{}
+	breakStmt    struct{ line int }
+	continueStmt struct{ line int }
+)
+
+// Expressions.
+type (
+	expr any
+
+	constExpr struct{ v any } // a number, string, True, False or None
+	nameExpr  struct {
+		name string
+		line int
+	}
+	unaryExpr struct {
+		op   string
+		x    expr
+		line int
+	}
+	binaryExpr struct {
+		op   string
+		x, y expr
+		line int
+	}
+	// compareExpr is a chain of comparisons, as in 0 <= x < 10.
+	compareExpr struct {
+		ops  []string
+		xs   []expr
+		line int
+	}
+	logicExpr struct {
+		op   string // and, or
+		x, y expr
+	}
+	condExpr struct{ then, cond, els expr }
+	callExpr struct {
+		fn     expr
+		args   []expr
+		kwargs []kwarg
+		line   int
+	}
+	attrExpr struct {
+		x    expr
+		name string
+		line int
+	}
+	indexExpr struct {
+		x, i expr
+		line int
+	}
+	listExpr struct {
+		elems []expr
+		tuple bool
+	}
+)
+
+type kwarg struct {
+	name string
+	x    expr
+}
+
+var keywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true,
+	"break": true, "continue": true, "def": true, "elif": true, "else": true,
+	"for": true, "from": true, "global": true, "if": true, "import": true,
+	"in": true, "is": true, "lambda": true, "not": true, "or": true,
+	"pass": true, "return": true, "while": true,
+}
+
+// parser builds statements from tokens.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func parse(toks []token) ([]stmt, error) {
+	p := &parser{toks: toks}
+	var prog []stmt
+	for p.peek().kind != eofToken {
+		if p.peek().kind == indentToken {
+			return nil, p.errorf("unexpected indent")
+		}
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		prog = append(prog, s...)
+	}
+	return prog, nil
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	tk := p.toks[p.pos]
+	if tk.kind != eofToken {
+		p.pos++
+	}
+	return tk
+}
+
+// isOp reports whether the next token is the operator op.
+func (p *parser) isOp(op string) bool {
+	tk := p.peek()
+	return tk.kind == opToken && tk.text == op
+}
+
+// isWord reports whether the next token is the keyword or name w.
+func (p *parser) isWord(w string) bool {
+	tk := p.peek()
+	return tk.kind == nameToken && tk.text == w
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.errorf("expected '%s'", op)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) name() (string, error) {
+	tk := p.peek()
+	if tk.kind != nameToken || keywords[tk.text] {
+		return "", p.errorf("expected a name")
+	}
+	p.next()
+	return tk.text, nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	tk := p.peek()
+	msg := fmt.Sprintf(format, args...)
+	switch tk.kind {
+	case newlineToken:
+		msg += " at end of line"
+	case eofToken, indentToken, dedentToken:
+	default:
+		msg += fmt.Sprintf(" before %q", tk.text)
+	}
+	return &Error{tk.line, fmt.Errorf("syntax error: %s", msg)}
+}
+
+// statement parses a compound statement or a line of simple ones.
+func (p *parser) statement() ([]stmt, error) {
+	tk := p.peek()
+	if tk.kind == nameToken {
+		var s stmt
+		var err error
+		switch tk.text {
+		case "if":
+			s, err = p.ifStatement()
+		case "while":
+			s, err = p.whileStatement()
+		case "for":
+			s, err = p.forStatement()
+		case "def":
+			s, err = p.defStatement()
+		default:
+			return p.simpleLine()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []stmt{s}, nil
+	}
+	return p.simpleLine()
+}
+
+// block parses the body of a compound statement after its ':', either
+// indented on the following lines or simple statements on the same line.
+func (p *parser) block() ([]stmt, error) {
+	if p.peek().kind != newlineToken {
+		return p.simpleLine()
+	}
+	p.next()
+	if p.peek().kind != indentToken {
+		return nil, p.errorf("expected an indented block")
+	}
+	p.next()
+	var body []stmt
+	for p.peek().kind != dedentToken && p.peek().kind != eofToken {
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, s...)
+	}
+	p.next()
+	return body, nil
+}
+
+func (p *parser) ifStatement() (stmt, error) {
+	line := p.next().line // if or elif
+	cond, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	s := &ifStmt{cond: cond, body: body, line: line}
+	switch {
+	case p.isWord("elif"):
+		elif, err := p.ifStatement()
+		if err != nil {
+			return nil, err
+		}
+		s.els = []stmt{elif}
+	case p.isWord("else"):
+		p.next()
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if s.els, err = p.block(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (p *parser) whileStatement() (stmt, error) {
+	line := p.next().line
+	cond, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	return &whileStmt{cond, body, line}, nil
+}
+
+func (p *parser) forStatement() (stmt, error) {
+	line := p.next().line
+	target, err := p.targetList()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isWord("in") {
+		return nil, p.errorf("expected 'in'")
+	}
+	p.next()
+	iter, err := p.exprList()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	return &forStmt{target, iter, body, line}, nil
+}
+
+// targetList parses the names a for loop assigns: i, or x, y.
+func (p *parser) targetList() (expr, error) {
+	var names []expr
+	paren := p.isOp("(")
+	if paren {
+		p.next()
+	}
+	for {
+		tk := p.peek()
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, &nameExpr{name, tk.line})
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if paren {
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if len(names) == 1 {
+		return names[0], nil
+	}
+	return &listExpr{elems: names, tuple: true}, nil
+}
+
+func (p *parser) defStatement() (stmt, error) {
+	line := p.next().line
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	s := &defStmt{name: name, line: line}
+	for !p.isOp(")") {
+		param, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		s.params = append(s.params, param)
+		if p.isOp("=") {
+			p.next()
+			d, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			s.defaults = append(s.defaults, d)
+		} else if len(s.defaults) > 0 {
+			return nil, p.errorf("non-default argument follows default argument")
+		}
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	if s.body, err = p.block(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// simpleLine parses simple statements separated by ';' up to the end of
+// the line.
+func (p *parser) simpleLine() ([]stmt, error) {
+	var out []stmt
+	for {
+		s, err := p.simple()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+		if !p.isOp(";") {
+			break
+		}
+		p.next()
+		if p.peek().kind == newlineToken {
+			break
+		}
+	}
+	switch p.peek().kind {
+	case newlineToken:
+		p.next()
+	case eofToken, dedentToken:
+	default:
+		return nil, p.errorf("invalid syntax")
+	}
+	return out, nil
+}
+
+func (p *parser) simple() (stmt, error) {
+	tk := p.peek()
+	if tk.kind == nameToken {
+		switch tk.text {
+		case "pass":
+			p.next()
+			return &passStmt{}, nil
+		case "break":
+			p.next()
+			return &breakStmt{tk.line}, nil
+		case "continue":
+			p.next()
+			return &continueStmt{tk.line}, nil
+		case "return":
+			p.next()
+			s := &returnStmt{line: tk.line}
+			if k := p.peek().kind; k != newlineToken && k != eofToken && !p.isOp(";") {
+				x, err := p.exprList()
+				if err != nil {
+					return nil, err
+				}
+				s.x = x
+			}
+			return s, nil
+		case "global":
+			p.next()
+			s := &globalStmt{}
+			for {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				s.names = append(s.names, name)
+				if !p.isOp(",") {
+					return s, nil
+				}
+				p.next()
+			}
+		case "import", "from":
+			return p.importStatement()
+		}
+	}
+
+	x, err := p.exprList()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == opToken && len(t.text) >= 2 && t.text[len(t.text)-1] == '=' &&
+		t.text != "==" && t.text != "!=" && t.text != "<=" && t.text != ">=" {
+		p.next()
+		if err := checkTarget(x, false); err != nil {
+			return nil, &Error{t.line, err}
+		}
+		value, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+		return &assignStmt{targets: []expr{x}, op: t.text[:len(t.text)-1], value: value, line: t.line}, nil
+	}
+	if !p.isOp("=") {
+		return &exprStmt{x, tk.line}, nil
+	}
+	s := &assignStmt{line: tk.line}
+	for p.isOp("=") {
+		eq := p.next()
+		if err := checkTarget(x, true); err != nil {
+			return nil, &Error{eq.line, err}
+		}
+		s.targets = append(s.targets, x)
+		if x, err = p.exprList(); err != nil {
+			return nil, err
+		}
+	}
+	s.value = x
+	return s, nil
+}
+
+// checkTarget reports whether x can be assigned to: a name, an item, or
+// with unpacking allowed, a tuple or list of those.
+func checkTarget(x expr, unpack bool) error {
+	switch x := x.(type) {
+	case *nameExpr, *indexExpr:
+		return nil
+	case *listExpr:
+		if unpack {
+			for _, e := range x.elems {
+				if err := checkTarget(e, false); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("syntax error: cannot assign to expression")
+}
+
+func (p *parser) importStatement() (stmt, error) {
+	tk := p.next()
+	s := &importStmt{line: tk.line, from: tk.text == "from"}
+	module, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	s.module = module
+	if !s.from {
+		if p.isWord("as") {
+			p.next()
+			if s.alias, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	}
+	if !p.isWord("import") {
+		return nil, p.errorf("expected 'import'")
+	}
+	p.next()
+	if p.isOp("*") {
+		p.next()
+		return s, nil
+	}
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		alias := name
+		if p.isWord("as") {
+			p.next()
+			if alias, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+		s.names = append(s.names, [2]string{name, alias})
+		if !p.isOp(",") {
+			return s, nil
+		}
+		p.next()
+	}
+}
+
+// exprList parses expressions separated by commas, which make a tuple.
+func (p *parser) exprList() (expr, error) {
+	x, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOp(",") {
+		return x, nil
+	}
+	t := &listExpr{elems: []expr{x}, tuple: true}
+	for p.isOp(",") {
+		p.next()
+		if !p.startsExpr() {
+			break // a trailing comma
+		}
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		t.elems = append(t.elems, x)
+	}
+	return t, nil
+}
+
+// startsExpr reports whether the next token can start an expression.
+func (p *parser) startsExpr() bool {
+	tk := p.peek()
+	switch tk.kind {
+	case nameToken:
+		return !keywords[tk.text] || tk.text == "not" || tk.text == "True" || tk.text == "False" || tk.text == "None"
+	case numberToken, stringToken:
+		return true
+	case opToken:
+		return tk.text == "(" || tk.text == "[" || tk.text == "-" || tk.text == "+"
+	}
+	return false
+}
+
+// expr parses a conditional expression, the loosest binding.
+func (p *parser) expr() (expr, error) {
+	x, err := p.or()
+	if err != nil || !p.isWord("if") {
+		return x, err
+	}
+	p.next()
+	cond, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isWord("else") {
+		return nil, p.errorf("expected 'else'")
+	}
+	p.next()
+	els, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return &condExpr{x, cond, els}, nil
+}
+
+func (p *parser) or() (expr, error) {
+	x, err := p.and()
+	for err == nil && p.isWord("or") {
+		p.next()
+		var y expr
+		if y, err = p.and(); err == nil {
+			x = &logicExpr{"or", x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) and() (expr, error) {
+	x, err := p.not()
+	for err == nil && p.isWord("and") {
+		p.next()
+		var y expr
+		if y, err = p.not(); err == nil {
+			x = &logicExpr{"and", x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) not() (expr, error) {
+	if !p.isWord("not") {
+		return p.comparison()
+	}
+	tk := p.next()
+	x, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	return &unaryExpr{"not", x, tk.line}, nil
+}
+
+func (p *parser) comparison() (expr, error) {
+	x, err := p.arith()
+	if err != nil {
+		return nil, err
+	}
+	c := &compareExpr{xs: []expr{x}, line: p.peek().line}
+	for {
+		tk := p.peek()
+		var op string
+		switch {
+		case tk.kind == opToken && (tk.text == "<" || tk.text == ">" || tk.text == "==" ||
+			tk.text == "!=" || tk.text == "<=" || tk.text == ">="):
+			op = tk.text
+			p.next()
+		case p.isWord("in"):
+			op = "in"
+			p.next()
+		case p.isWord("is"):
+			p.next()
+			op = "is"
+			if p.isWord("not") {
+				p.next()
+				op = "is not"
+			}
+		case p.isWord("not") && p.pos+1 < len(p.toks) && p.toks[p.pos+1].kind == nameToken && p.toks[p.pos+1].text == "in":
+			p.next()
+			p.next()
+			op = "not in"
+		}
+		if op == "" {
+			break
+		}
+		y, err := p.arith()
+		if err != nil {
+			return nil, err
+		}
+		c.ops = append(c.ops, op)
+		c.xs = append(c.xs, y)
+	}
+	if len(c.ops) == 0 {
+		return x, nil
+	}
+	return c, nil
+}
+
+func (p *parser) arith() (expr, error) {
+	x, err := p.term()
+	for err == nil && (p.isOp("+") || p.isOp("-")) {
+		op := p.next()
+		var y expr
+		if y, err = p.term(); err == nil {
+			x = &binaryExpr{op.text, x, y, op.line}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) term() (expr, error) {
+	x, err := p.factor()
+	for err == nil && (p.isOp("*") || p.isOp("/") || p.isOp("//") || p.isOp("%")) {
+		op := p.next()
+		var y expr
+		if y, err = p.factor(); err == nil {
+			x = &binaryExpr{op.text, x, y, op.line}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) factor() (expr, error) {
+	if p.isOp("-") || p.isOp("+") {
+		op := p.next()
+		x, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op.text, x, op.line}, nil
+	}
+	return p.power()
+}
+
+func (p *parser) power() (expr, error) {
+	x, err := p.postfix()
+	if err != nil || !p.isOp("**") {
+		return x, err
+	}
+	op := p.next()
+	y, err := p.factor() // right-associative, and -x binds tighter on the right
+	if err != nil {
+		return nil, err
+	}
+	return &binaryExpr{"**", x, y, op.line}, nil
+}
+
+// postfix parses an atom followed by calls, indexing and attributes.
+func (p *parser) postfix() (expr, error) {
+	x, err := p.atom()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tk := p.peek()
+		switch {
+		case p.isOp("("):
+			p.next()
+			call := &callExpr{fn: x, line: tk.line}
+			for !p.isOp(")") {
+				if next := p.toks[p.pos+1]; p.peek().kind == nameToken && next.kind == opToken && next.text == "=" {
+					name := p.next().text
+					p.next()
+					v, err := p.expr()
+					if err != nil {
+						return nil, err
+					}
+					call.kwargs = append(call.kwargs, kwarg{name, v})
+				} else {
+					if len(call.kwargs) > 0 {
+						return nil, p.errorf("positional argument follows keyword argument")
+					}
+					v, err := p.expr()
+					if err != nil {
+						return nil, err
+					}
+					call.args = append(call.args, v)
+				}
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			x = call
+		case p.isOp("["):
+			p.next()
+			i, err := p.exprList()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &indexExpr{x, i, tk.line}
+		case p.isOp("."):
+			p.next()
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			x = &attrExpr{x, name, tk.line}
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *parser) atom() (expr, error) {
+	tk := p.peek()
+	switch tk.kind {
+	case numberToken:
+		p.next()
+		return &constExpr{tk.num}, nil
+	case stringToken:
+		p.next()
+		s := tk.text
+		for p.peek().kind == stringToken { // adjacent literals concatenate
+			s += p.next().text
+		}
+		return &constExpr{s}, nil
+	case nameToken:
+		switch tk.text {
+		case "True":
+			p.next()
+			return &constExpr{true}, nil
+		case "False":
+			p.next()
+			return &constExpr{false}, nil
+		case "None":
+			p.next()
+			return &constExpr{nil}, nil
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		return &nameExpr{name, tk.line}, nil
+	case opToken:
+		switch tk.text {
+		case "(":
+			p.next()
+			if p.isOp(")") {
+				p.next()
+				return &listExpr{tuple: true}, nil
+			}
+			x, err := p.exprList()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			p.next()
+			l := &listExpr{}
+			for !p.isOp("]") {
+				x, err := p.expr()
+				if err != nil {
+					return nil, err
+				}
+				l.elems = append(l.elems, x)
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			return l, nil
+		}
+	}
+	return nil, p.errorf("invalid syntax")
+}
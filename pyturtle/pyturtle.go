@@ -0,0 +1,943 @@
+// Package pyturtle runs Python turtle scripts on a gotuga turtle, so
+// classroom programs written for Python's turtle module can be reused
+// unchanged.
+//
+//	pyturtle.Run(t, `
+//	import turtle
+//	t = turtle.Turtle()
+//	t.color("red", "yellow")
+//	t.begin_fill()
+//	for i in range(36):
+//	    t.forward(200)
+//	    t.left(170)
+//	t.end_fill()
+//	turtle.done()
+//	`)
+//
+// It understands the subset of Python such scripts are written in: numbers,
+// strings, lists and tuples; variables, with global; arithmetic,
+// comparisons and boolean logic; if, while and for loops over range and
+// lists, with break and continue; functions defined with def, with default
+// and keyword arguments; and the builtins range, len, int, float, str,
+// bool, abs, min, max, sum, round and print. The math and random modules
+// are available too.
+//
+// The turtle module offers the usual motion, pen, color, fill and state
+// functions, both on turtles made with Turtle() and at module level, where
+// they drive t. Calls that only make sense in a window, such as speed,
+// shape, hideturtle, tracer, title and done, are accepted and ignored, as
+// is write. bgcolor repaints the whole canvas, clearing it. Turtles share
+// t's canvas, and clear clears all of it.
+package pyturtle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// maxDepth limits recursion, as Python does.
+const maxDepth = 1000
+
+// Interpreter runs Python source on a turtle. Functions and global
+// variables persist between calls to Run.
+type Interpreter struct {
+	// Output receives the text of print; os.Stdout by default.
+	Output io.Writer
+
+	// Rand is the source for the random module; nil uses the global
+	// source. random.seed replaces it.
+	Rand *rand.Rand
+
+	t         *gotuga.Turtle
+	globals   map[string]any
+	frames    []*frame
+	colorMode float64
+}
+
+// frame holds a function call's local variables.
+type frame struct {
+	vars    map[string]any
+	globals map[string]bool // names declared global
+}
+
+// New returns an interpreter whose module-level turtle functions drive t.
+func New(t *gotuga.Turtle) *Interpreter {
+	return &Interpreter{
+		Output:    os.Stdout,
+		t:         t,
+		globals:   make(map[string]any),
+		colorMode: 1,
+	}
+}
+
+// Run runs src on t with a new interpreter.
+func Run(t *gotuga.Turtle, src string) error {
+	return New(t).Run(src)
+}
+
+// Turtle returns the turtle module-level functions draw with.
+func (in *Interpreter) Turtle() *gotuga.Turtle { return in.t }
+
+// Run executes the Python program src. A turtle past its step or time
+// budget stops the program with an error wrapping a *gotuga.BudgetError.
+func (in *Interpreter) Run(src string) error {
+	toks, err := lex(src)
+	if err != nil {
+		return err
+	}
+	prog, err := parse(toks)
+	if err != nil {
+		return err
+	}
+	err = in.exec(prog)
+	switch err := err.(type) {
+	case *returnSignal:
+		return &Error{err.line, fmt.Errorf("'return' outside function")}
+	case breakSignal:
+		return &Error{err.line, fmt.Errorf("'break' outside loop")}
+	case continueSignal:
+		return &Error{err.line, fmt.Errorf("'continue' not properly in loop")}
+	}
+	return err
+}
+
+// Error is a Python error with the source line it occurred on.
+type Error struct {
+	Line int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("pyturtle: %v", e.Err)
+	}
+	return fmt.Sprintf("pyturtle: line %d: %v", e.Line, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// returnSignal, breakSignal and continueSignal unwind functions and loops.
+type returnSignal struct {
+	value any
+	line  int
+}
+
+type breakSignal struct{ line int }
+
+type continueSignal struct{ line int }
+
+func (*returnSignal) Error() string { return "return outside function" }
+
+func (breakSignal) Error() string { return "break outside loop" }
+
+func (continueSignal) Error() string { return "continue outside loop" }
+
+// Values are nil (None), bool, float64, string, *list, tuple, *function,
+// builtin, *module and *pen.
+type (
+	list  struct{ items []any }
+	tuple []any
+
+	function struct{ def *defStmt }
+
+	// builtin is a function implemented in Go.
+	builtin func(in *Interpreter, args []any, kw map[string]any) (any, error)
+
+	module struct {
+		name  string
+		attrs map[string]any
+	}
+)
+
+func (in *Interpreter) exec(stmts []stmt) error {
+	for _, s := range stmts {
+		if err := in.execOne(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (in *Interpreter) execOne(s stmt) error {
+	switch s := s.(type) {
+	case *exprStmt:
+		_, err := in.eval(s.x)
+		return err
+	case *assignStmt:
+		v, err := in.eval(s.value)
+		if err != nil {
+			return err
+		}
+		if s.op != "" {
+			cur, err := in.eval(s.targets[0])
+			if err != nil {
+				return err
+			}
+			if v, err = binary(s.op, cur, v); err != nil {
+				return &Error{s.line, err}
+			}
+		}
+		for _, target := range s.targets {
+			if err := in.assign(target, v, s.line); err != nil {
+				return err
+			}
+		}
+	case *ifStmt:
+		v, err := in.eval(s.cond)
+		if err != nil {
+			return err
+		}
+		if truth(v) {
+			return in.exec(s.body)
+		}
+		return in.exec(s.els)
+	case *whileStmt:
+		for {
+			if err := in.t.CheckBudget("while"); err != nil {
+				return err
+			}
+			v, err := in.eval(s.cond)
+			if err != nil {
+				return err
+			}
+			if !truth(v) {
+				return nil
+			}
+			if stop, err := loopBody(in.exec(s.body)); stop {
+				return err
+			}
+		}
+	case *forStmt:
+		v, err := in.eval(s.iter)
+		if err != nil {
+			return err
+		}
+		items, err := iterate(v)
+		if err != nil {
+			return &Error{s.line, err}
+		}
+		for _, item := range items {
+			if err := in.t.CheckBudget("for"); err != nil {
+				return err
+			}
+			if err := in.assign(s.target, item, s.line); err != nil {
+				return err
+			}
+			if stop, err := loopBody(in.exec(s.body)); stop {
+				return err
+			}
+		}
+	case *defStmt:
+		in.set(s.name, &function{s})
+	case *returnStmt:
+		var v any
+		if s.x != nil {
+			var err error
+			if v, err = in.eval(s.x); err != nil {
+				return err
+			}
+		}
+		return &returnSignal{v, s.line}
+	case *breakStmt:
+		return breakSignal{s.line}
+	case *continueStmt:
+		return continueSignal{s.line}
+	case *passStmt:
+	case *globalStmt:
+		if len(in.frames) > 0 {
+			f := in.frames[len(in.frames)-1]
+			for _, name := range s.names {
+				f.globals[name] = true
+			}
+		}
+	case *importStmt:
+		return in.importModule(s)
+	}
+	return nil
+}
+
+// loopBody interprets the result of running a loop's body once, reporting
+// whether the loop ends and with what error.
+func loopBody(err error) (stop bool, _ error) {
+	switch err.(type) {
+	case nil, continueSignal:
+		return false, nil
+	case breakSignal:
+		return true, nil
+	}
+	return true, err
+}
+
+func (in *Interpreter) importModule(s *importStmt) error {
+	m := in.module(s.module)
+	if m == nil {
+		return &Error{s.line, fmt.Errorf("no module named '%s'", s.module)}
+	}
+	switch {
+	case !s.from && s.alias != "":
+		in.set(s.alias, m)
+	case !s.from:
+		in.set(s.module, m)
+	case s.names == nil:
+		for name, v := range m.attrs {
+			in.set(name, v)
+		}
+	default:
+		for _, n := range s.names {
+			v, ok := m.attrs[n[0]]
+			if !ok {
+				return &Error{s.line, fmt.Errorf("cannot import name '%s' from '%s'", n[0], s.module)}
+			}
+			in.set(n[1], v)
+		}
+	}
+	return nil
+}
+
+// assign stores v in a name, an item, or unpacks it into a tuple of
+// targets.
+func (in *Interpreter) assign(target expr, v any, line int) error {
+	switch target := target.(type) {
+	case *nameExpr:
+		in.set(target.name, v)
+	case *listExpr:
+		items, err := iterate(v)
+		if err != nil {
+			return &Error{line, fmt.Errorf("cannot unpack non-sequence %s", typeName(v))}
+		}
+		if len(items) != len(target.elems) {
+			return &Error{line, fmt.Errorf("expected %d values to unpack, got %d", len(target.elems), len(items))}
+		}
+		for i, e := range target.elems {
+			if err := in.assign(e, items[i], line); err != nil {
+				return err
+			}
+		}
+	case *indexExpr:
+		x, err := in.eval(target.x)
+		if err != nil {
+			return err
+		}
+		l, ok := x.(*list)
+		if !ok {
+			return &Error{line, fmt.Errorf("'%s' object does not support item assignment", typeName(x))}
+		}
+		iv, err := in.eval(target.i)
+		if err != nil {
+			return err
+		}
+		i, err := index(iv, len(l.items))
+		if err != nil {
+			return &Error{line, err}
+		}
+		l.items[i] = v
+	}
+	return nil
+}
+
+// set assigns a variable in the current function, unless declared global,
+// or at top level.
+func (in *Interpreter) set(name string, v any) {
+	if n := len(in.frames); n > 0 && !in.frames[n-1].globals[name] {
+		in.frames[n-1].vars[name] = v
+		return
+	}
+	in.globals[name] = v
+}
+
+// lookup finds a variable: local, then global, then builtin.
+func (in *Interpreter) lookup(name string) (any, bool) {
+	if n := len(in.frames); n > 0 {
+		if v, ok := in.frames[n-1].vars[name]; ok {
+			return v, true
+		}
+	}
+	if v, ok := in.globals[name]; ok {
+		return v, true
+	}
+	v, ok := builtins[name]
+	return v, ok
+}
+
+func (in *Interpreter) eval(x expr) (any, error) {
+	switch x := x.(type) {
+	case *constExpr:
+		return x.v, nil
+	case *nameExpr:
+		v, ok := in.lookup(x.name)
+		if !ok {
+			return nil, &Error{x.line, fmt.Errorf("name '%s' is not defined", x.name)}
+		}
+		return v, nil
+	case *listExpr:
+		items := make([]any, len(x.elems))
+		for i, e := range x.elems {
+			v, err := in.eval(e)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = v
+		}
+		if x.tuple {
+			return tuple(items), nil
+		}
+		return &list{items}, nil
+	case *unaryExpr:
+		v, err := in.eval(x.x)
+		if err != nil {
+			return nil, err
+		}
+		if x.op == "not" {
+			return !truth(v), nil
+		}
+		n, err := number(v)
+		if err != nil {
+			return nil, &Error{x.line, fmt.Errorf("bad operand type for unary %s: '%s'", x.op, typeName(v))}
+		}
+		if x.op == "-" {
+			return -n, nil
+		}
+		return n, nil
+	case *binaryExpr:
+		a, err := in.eval(x.x)
+		if err != nil {
+			return nil, err
+		}
+		b, err := in.eval(x.y)
+		if err != nil {
+			return nil, err
+		}
+		v, err := binary(x.op, a, b)
+		if err != nil {
+			return nil, &Error{x.line, err}
+		}
+		return v, nil
+	case *compareExpr:
+		a, err := in.eval(x.xs[0])
+		if err != nil {
+			return nil, err
+		}
+		for i, op := range x.ops {
+			b, err := in.eval(x.xs[i+1])
+			if err != nil {
+				return nil, err
+			}
+			ok, err := compare(op, a, b)
+			if err != nil {
+				return nil, &Error{x.line, err}
+			}
+			if !ok {
+				return false, nil
+			}
+			a = b
+		}
+		return true, nil
+	case *logicExpr:
+		a, err := in.eval(x.x)
+		if err != nil || truth(a) == (x.op == "or") {
+			return a, err
+		}
+		return in.eval(x.y)
+	case *condExpr:
+		c, err := in.eval(x.cond)
+		if err != nil {
+			return nil, err
+		}
+		if truth(c) {
+			return in.eval(x.then)
+		}
+		return in.eval(x.els)
+	case *callExpr:
+		fn, err := in.eval(x.fn)
+		if err != nil {
+			return nil, err
+		}
+		args := make([]any, len(x.args))
+		for i, a := range x.args {
+			if args[i], err = in.eval(a); err != nil {
+				return nil, err
+			}
+		}
+		var kw map[string]any
+		if len(x.kwargs) > 0 {
+			kw = make(map[string]any, len(x.kwargs))
+			for _, k := range x.kwargs {
+				if kw[k.name], err = in.eval(k.x); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return in.call(fn, args, kw, x.line)
+	case *attrExpr:
+		v, err := in.eval(x.x)
+		if err != nil {
+			return nil, err
+		}
+		a, ok := in.attr(v, x.name)
+		if !ok {
+			return nil, &Error{x.line, fmt.Errorf("'%s' object has no attribute '%s'", typeName(v), x.name)}
+		}
+		return a, nil
+	case *indexExpr:
+		v, err := in.eval(x.x)
+		if err != nil {
+			return nil, err
+		}
+		iv, err := in.eval(x.i)
+		if err != nil {
+			return nil, err
+		}
+		var r any
+		switch v := v.(type) {
+		case *list:
+			var i int
+			if i, err = index(iv, len(v.items)); err == nil {
+				r = v.items[i]
+			}
+		case tuple:
+			var i int
+			if i, err = index(iv, len(v)); err == nil {
+				r = v[i]
+			}
+		case string:
+			rs := []rune(v)
+			var i int
+			if i, err = index(iv, len(rs)); err == nil {
+				r = string(rs[i])
+			}
+		default:
+			err = fmt.Errorf("'%s' object is not subscriptable", typeName(v))
+		}
+		if err != nil {
+			return nil, &Error{x.line, err}
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("pyturtle: unknown expression %T", x)
+}
+
+// call calls a function with positional and keyword arguments.
+func (in *Interpreter) call(fn any, args []any, kw map[string]any, line int) (any, error) {
+	switch fn := fn.(type) {
+	case *function:
+		return in.invoke(fn, args, kw, line)
+	case builtin:
+		v, err := fn(in, args, kw)
+		switch err.(type) {
+		case nil, *Error:
+		default:
+			err = &Error{line, err}
+		}
+		return v, err
+	}
+	return nil, &Error{line, fmt.Errorf("'%s' object is not callable", typeName(fn))}
+}
+
+// invoke runs a user function with its arguments bound in a new frame.
+func (in *Interpreter) invoke(fn *function, args []any, kw map[string]any, line int) (any, error) {
+	d := fn.def
+	if err := in.t.CheckBudget(d.name); err != nil {
+		return nil, err
+	}
+	if len(in.frames) >= maxDepth {
+		return nil, &Error{line, fmt.Errorf("maximum recursion depth exceeded")}
+	}
+	if len(args) > len(d.params) {
+		return nil, &Error{line, fmt.Errorf("%s() takes %d positional arguments but %d were given", d.name, len(d.params), len(args))}
+	}
+	f := &frame{vars: make(map[string]any, len(d.params)), globals: make(map[string]bool)}
+	for i, a := range args {
+		f.vars[d.params[i]] = a
+	}
+	for name, v := range kw {
+		if _, dup := f.vars[name]; dup {
+			return nil, &Error{line, fmt.Errorf("%s() got multiple values for argument '%s'", d.name, name)}
+		}
+		if !containsString(d.params, name) {
+			return nil, &Error{line, fmt.Errorf("%s() got an unexpected keyword argument '%s'", d.name, name)}
+		}
+		f.vars[name] = v
+	}
+	first := len(d.params) - len(d.defaults)
+	for i, name := range d.params {
+		if _, ok := f.vars[name]; ok {
+			continue
+		}
+		if i < first {
+			return nil, &Error{line, fmt.Errorf("%s() missing required argument: '%s'", d.name, name)}
+		}
+		v, err := in.eval(d.defaults[i-first])
+		if err != nil {
+			return nil, err
+		}
+		f.vars[name] = v
+	}
+
+	in.frames = append(in.frames, f)
+	defer func() { in.frames = in.frames[:len(in.frames)-1] }()
+	err := in.exec(d.body)
+	switch err := err.(type) {
+	case *returnSignal:
+		return err.value, nil
+	case breakSignal:
+		return nil, &Error{err.line, fmt.Errorf("'break' outside loop")}
+	case continueSignal:
+		return nil, &Error{err.line, fmt.Errorf("'continue' not properly in loop")}
+	}
+	return nil, err
+}
+
+// attr looks up an attribute of a module, turtle, screen or list.
+func (in *Interpreter) attr(v any, name string) (any, bool) {
+	switch v := v.(type) {
+	case *module:
+		a, ok := v.attrs[name]
+		return a, ok
+	case *pen:
+		return in.penMethod(v.t, name)
+	case screen:
+		fn, ok := screenFuncs[name]
+		if !ok {
+			return nil, false
+		}
+		return builtin(fn), true
+	case *list:
+		if name == "append" {
+			return builtin(func(_ *Interpreter, args []any, _ map[string]any) (any, error) {
+				if len(args) != 1 {
+					return nil, fmt.Errorf("append() takes exactly one argument (%d given)", len(args))
+				}
+				v.items = append(v.items, args[0])
+				return nil, nil
+			}), true
+		}
+	}
+	return nil, false
+}
+
+// binary applies an arithmetic operator.
+func binary(op string, a, b any) (any, error) {
+	if op == "+" {
+		switch a := a.(type) {
+		case string:
+			if b, ok := b.(string); ok {
+				return a + b, nil
+			}
+		case *list:
+			if b, ok := b.(*list); ok {
+				return &list{append(append([]any(nil), a.items...), b.items...)}, nil
+			}
+		case tuple:
+			if b, ok := b.(tuple); ok {
+				return append(append(tuple(nil), a...), b...), nil
+			}
+		}
+	}
+	if op == "*" {
+		if s, ok := a.(string); ok {
+			if n, err := number(b); err == nil {
+				return strings.Repeat(s, max(int(n), 0)), nil
+			}
+		}
+	}
+	x, errA := number(a)
+	y, errB := number(b)
+	if errA != nil || errB != nil {
+		return nil, fmt.Errorf("unsupported operand type(s) for %s: '%s' and '%s'", op, typeName(a), typeName(b))
+	}
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return x / y, nil
+	case "//":
+		if y == 0 {
+			return nil, fmt.Errorf("integer division or modulo by zero")
+		}
+		return math.Floor(x / y), nil
+	case "%":
+		if y == 0 {
+			return nil, fmt.Errorf("integer division or modulo by zero")
+		}
+		return x - y*math.Floor(x/y), nil
+	case "**":
+		return math.Pow(x, y), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+// compare applies a comparison operator.
+func compare(op string, a, b any) (bool, error) {
+	switch op {
+	case "==":
+		return equal(a, b), nil
+	case "!=":
+		return !equal(a, b), nil
+	case "is":
+		return a == nil && b == nil || equal(a, b) && isScalar(a), nil
+	case "is not":
+		ok, _ := compare("is", a, b)
+		return !ok, nil
+	case "in", "not in":
+		var found bool
+		if s, ok := b.(string); ok {
+			sub, ok := a.(string)
+			if !ok {
+				return false, fmt.Errorf("'in <string>' requires string as left operand, not %s", typeName(a))
+			}
+			found = strings.Contains(s, sub)
+		} else {
+			items, err := iterate(b)
+			if err != nil {
+				return false, fmt.Errorf("argument of type '%s' is not iterable", typeName(b))
+			}
+			for _, item := range items {
+				if equal(a, item) {
+					found = true
+					break
+				}
+			}
+		}
+		return found == (op == "in"), nil
+	}
+	if s, ok := a.(string); ok {
+		if t, ok := b.(string); ok {
+			c := strings.Compare(s, t)
+			return op == "<" && c < 0 || op == ">" && c > 0 || op == "<=" && c <= 0 || op == ">=" && c >= 0, nil
+		}
+	}
+	x, errA := number(a)
+	y, errB := number(b)
+	if errA != nil || errB != nil {
+		return false, fmt.Errorf("'%s' not supported between instances of '%s' and '%s'", op, typeName(a), typeName(b))
+	}
+	switch op {
+	case "<":
+		return x < y, nil
+	case ">":
+		return x > y, nil
+	case "<=":
+		return x <= y, nil
+	}
+	return x >= y, nil
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case nil, bool, float64, string:
+		return true
+	}
+	return false
+}
+
+func equal(a, b any) bool {
+	if x, err := number(a); err == nil {
+		y, err := number(b)
+		return err == nil && x == y
+	}
+	switch a := a.(type) {
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case nil:
+		return b == nil
+	case *list:
+		b, ok := b.(*list)
+		return ok && equalItems(a.items, b.items)
+	case tuple:
+		b, ok := b.(tuple)
+		return ok && equalItems(a, b)
+	}
+	return a == b
+}
+
+func equalItems(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// truth reports whether v counts as true.
+func truth(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case *list:
+		return len(v.items) > 0
+	case tuple:
+		return len(v) > 0
+	}
+	return true
+}
+
+// number converts a number or bool to float64.
+func number(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %s", typeName(v))
+}
+
+// integer converts a whole number to int.
+func integer(v any) (int, error) {
+	n, err := number(v)
+	if err != nil {
+		return 0, err
+	}
+	if n != math.Trunc(n) || math.Abs(n) > 1<<53 {
+		return 0, fmt.Errorf("expected an integer, got %s", format(v))
+	}
+	return int(n), nil
+}
+
+// index converts v to an index into a sequence of n items, counting from
+// the end if negative.
+func index(v any, n int) (int, error) {
+	i, err := integer(v)
+	if err != nil {
+		return 0, errors.New("indices must be integers")
+	}
+	if i < 0 {
+		i += n
+	}
+	if i < 0 || i >= n {
+		return 0, errors.New("index out of range")
+	}
+	return i, nil
+}
+
+// iterate returns the items of a list, tuple or string.
+func iterate(v any) ([]any, error) {
+	switch v := v.(type) {
+	case *list:
+		return append([]any(nil), v.items...), nil
+	case tuple:
+		return v, nil
+	case string:
+		var items []any
+		for _, r := range v {
+			items = append(items, string(r))
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("'%s' object is not iterable", typeName(v))
+}
+
+func typeName(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NoneType"
+	case bool:
+		return "bool"
+	case float64:
+		if v == math.Trunc(v) {
+			return "int"
+		}
+		return "float"
+	case string:
+		return "str"
+	case *list:
+		return "list"
+	case tuple:
+		return "tuple"
+	case *function, builtin:
+		return "function"
+	case *module:
+		return "module"
+	case *pen:
+		return "Turtle"
+	case screen:
+		return "Screen"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// format renders a value the way print shows it.
+func format(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e16 {
+			return strconv.FormatFloat(v, 'f', 0, 64)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return v
+	case *list:
+		return "[" + formatItems(v.items) + "]"
+	case tuple:
+		if len(v) == 1 {
+			return "(" + repr(v[0]) + ",)"
+		}
+		return "(" + formatItems(v) + ")"
+	case *function:
+		return "<function " + v.def.name + ">"
+	case *module:
+		return "<module '" + v.name + "'>"
+	}
+	return "<" + typeName(v) + ">"
+}
+
+func formatItems(items []any) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = repr(item)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// repr renders a value as it appears inside a list.
+func repr(v any) string {
+	if s, ok := v.(string); ok {
+		return "'" + strings.ReplaceAll(s, "'", "\\'") + "'"
+	}
+	return format(v)
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
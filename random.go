@@ -0,0 +1,44 @@
+package gotuga
+
+import (
+	"math/rand/v2"
+
+	"github.com/Z6dev/GoTuga/internal/random"
+)
+
+// The random helpers take their randomness from rng, so a drawing made from
+// a seeded source, e.g. rand.New(rand.NewPCG(seed, 0)), is the same every
+// time. A nil rng uses the unseeded global source. They are recorded as the
+// plain commands they perform, so sessions replay them exactly.
+
+// RandomWalk takes steps steps of length stepLen, turning by a random angle
+// of up to maxTurn degrees either way before each one.
+func (t *Turtle) RandomWalk(steps int, stepLen, maxTurn float64, rng *rand.Rand) {
+	for i := 0; i < steps; i++ {
+		t.Left(jitter(maxTurn, rng))
+		t.Forward(stepLen)
+	}
+}
+
+// JitterForward moves forward d plus a random amount of up to amount either
+// way.
+func (t *Turtle) JitterForward(d, amount float64, rng *rand.Rand) {
+	t.Forward(d + jitter(amount, rng))
+}
+
+// JitterLeft turns left deg plus a random amount of up to amount degrees
+// either way.
+func (t *Turtle) JitterLeft(deg, amount float64, rng *rand.Rand) {
+	t.Left(deg + jitter(amount, rng))
+}
+
+// JitterRight turns right deg plus a random amount of up to amount degrees
+// either way.
+func (t *Turtle) JitterRight(deg, amount float64, rng *rand.Rand) {
+	t.Right(deg + jitter(amount, rng))
+}
+
+// jitter returns a uniformly random value in [-amount, amount].
+func jitter(amount float64, rng *rand.Rand) float64 {
+	return (2*random.Float64(rng) - 1) * amount
+}
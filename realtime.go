@@ -0,0 +1,90 @@
+package gotuga
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	defaultMoveSpeed = 200 // logical units per second
+	defaultTurnSpeed = 360 // degrees per second
+)
+
+// SetRealTime turns on real-time mode at fps frames per second: moves and
+// turns take time in proportion to their distance and angle (see SetSpeed)
+// and attached recorders capture one frame per frame interval instead of one
+// per command, so animations play at a consistent perceived speed.
+// An fps of zero or less turns real-time mode off.
+func (t *Turtle) SetRealTime(fps int) {
+	if t.refuse("realtime") {
+		return
+	}
+	defer t.track("realtime", float64(fps))()
+	t.fps = max(fps, 0)
+}
+
+// SetSpeed sets how fast the turtle moves (logical units per second) and
+// turns (degrees per second) in real-time mode. Non-positive values are ignored.
+func (t *Turtle) SetSpeed(move, turn float64) {
+	if t.refuse("speed") {
+		return
+	}
+	defer t.track("speed", move, turn)()
+	if move > 0 {
+		t.moveSpeed = move
+	}
+	if turn > 0 {
+		t.turnSpeed = turn
+	}
+}
+
+// Delay lets d pass without moving. In real-time mode the pause shows up as
+// frames in attached recorders; otherwise it does nothing.
+func (t *Turtle) Delay(d time.Duration) {
+	if t.refuse("delay") {
+		return
+	}
+	defer t.track("delay", d.Seconds())()
+	t.advance(d, func(float64) {})
+}
+
+// Elapsed returns the motion time accumulated in real-time mode.
+func (t *Turtle) Elapsed() time.Duration { return t.elapsed }
+
+func (t *Turtle) frameInterval() time.Duration {
+	return time.Second / time.Duration(t.fps)
+}
+
+func (t *Turtle) moveDuration(dist float64) time.Duration {
+	return time.Duration(dist / t.moveSpeed * float64(time.Second))
+}
+
+func (t *Turtle) turnDuration(deg float64) time.Duration {
+	return time.Duration(math.Abs(deg) / t.turnSpeed * float64(time.Second))
+}
+
+// advance lets dur of motion time pass. step is called with the completed
+// fraction of the motion at every frame boundary crossed, before frame
+// observers run, and finally with 1. It must be called while a command holds
+// the screen lock. Outside real-time mode step is simply
+// called with 1.
+func (t *Turtle) advance(dur time.Duration, step func(f float64)) {
+	if t.fps <= 0 || dur <= 0 {
+		step(1)
+		return
+	}
+	frame := t.frameInterval()
+	start := t.elapsed
+	end := start + dur
+	for next := (start/frame + 1) * frame; next <= end; next += frame {
+		step(float64(next-start) / float64(dur))
+		t.elapsed = next
+		// Frame observers may hand control to other turtles on this
+		// canvas, so release it while they run.
+		t.screen.mu.Unlock()
+		t.notifyFrame()
+		t.screen.mu.Lock()
+	}
+	step(1)
+	t.elapsed = end
+}
@@ -0,0 +1,308 @@
+package gotuga
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Recorder captures a copy of the canvas after every command a turtle runs.
+//
+// A recorder keeps a clock of recording time that starts at zero and only
+// moves forward through Advance, so timer-driven animations can be recorded
+// faster than real time. Each frame is stamped with the clock when captured.
+//
+// In real-time mode (see SetRealTime) frames are captured at the turtle's
+// frame rate instead, and the clock follows the turtle's motion time.
+type Recorder struct {
+	t       *Turtle
+	mu      sync.Mutex // followed turtles may draw on other goroutines
+	frames  []*image.RGBA
+	times   []time.Duration
+	kinds   []frameKind
+	version uint64 // screen version at the last capture
+	stops   []func()
+
+	now     time.Duration
+	elapsed time.Duration // furthest motion time reached by a followed turtle
+	timers  []*recTimer
+
+	keys    []Keyframe
+	lastKey map[int]int // index in keys of each turtle's latest keyframe
+	turtles int         // number of followed turtles
+}
+
+// frameKind records what the turtle was doing when a frame was captured.
+type frameKind uint8
+
+const (
+	travelFrame  frameKind = 1 << iota // pen up, so nothing was drawn
+	fillingFrame                       // between BeginFill and EndFill
+)
+
+type recTimer struct {
+	interval time.Duration
+	next     time.Duration
+	fn       func(t *Turtle)
+}
+
+// Record starts capturing frames. The current canvas is stored as the first
+// frame. Call Stop on the returned Recorder to detach it.
+func (t *Turtle) Record() *Recorder {
+	r := &Recorder{t: t}
+	r.capture(t)
+	r.Follow(t)
+	return r
+}
+
+// Follow makes the recorder also capture the commands of other, a turtle
+// spawned on the same canvas, so several turtles end up in one recording.
+func (r *Recorder) Follow(other *Turtle) {
+	r.elapsed = max(r.elapsed, other.elapsed)
+	id := r.turtles
+	r.turtles++
+	r.keyframe(id, other)
+	stopCmds := other.Observe(func(Command) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if other.fps == 0 {
+			r.capture(other)
+		}
+		r.keyframe(id, other)
+	})
+	stopFrames := other.observeFrames(func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		// With several turtles only the one furthest ahead in motion
+		// time moves the clock, so each frame is captured once.
+		if other.elapsed > r.elapsed {
+			r.now += other.elapsed - r.elapsed
+			r.elapsed = other.elapsed
+			r.capture(other)
+		}
+		r.keyframe(id, other)
+	})
+	r.stops = append(r.stops, stopCmds, stopFrames)
+}
+
+// capture stores a copy of the canvas, taken between commands, as a new
+// frame; src is the turtle whose action caused it.
+func (r *Recorder) capture(src *Turtle) {
+	var kind frameKind
+	if !src.penDown {
+		kind |= travelFrame
+	}
+	if src.filling {
+		kind |= fillingFrame
+	}
+	img, version := r.t.copyCanvas()
+	r.frames = append(r.frames, img)
+	r.times = append(r.times, r.now)
+	r.kinds = append(r.kinds, kind)
+	r.version = version
+}
+
+// Stop detaches the recorder; frames captured so far are kept. In real-time
+// mode a last frame is captured if the canvas may have changed since the
+// previous one.
+func (r *Recorder) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stops == nil {
+		return
+	}
+	if r.t.canvasVersion() != r.version {
+		r.capture(r.t)
+	}
+	for _, stop := range r.stops {
+		stop()
+	}
+	r.stops = nil
+}
+
+// Frames returns the captured frames in order.
+func (r *Recorder) Frames() []*image.RGBA { return r.frames }
+
+// Times returns the recording time at which each frame was captured.
+func (r *Recorder) Times() []time.Duration { return r.times }
+
+// Now returns the current recording time.
+func (r *Recorder) Now() time.Duration { return r.now }
+
+// OnTimer makes fn run every interval of recording time, the first time one
+// interval from now. Timers only fire inside Advance.
+func (r *Recorder) OnTimer(interval time.Duration, fn func(t *Turtle)) {
+	if interval <= 0 || fn == nil {
+		return
+	}
+	r.timers = append(r.timers, &recTimer{interval: interval, next: r.now + interval, fn: fn})
+}
+
+// Advance moves the recording clock forward by d, running every timer that
+// falls due in order of due time. Frames captured by a timer's commands are
+// stamped with the time it fired.
+func (r *Recorder) Advance(d time.Duration) {
+	end := r.now + d
+	for {
+		var due *recTimer
+		for _, tm := range r.timers {
+			if tm.next <= end && (due == nil || tm.next < due.next) {
+				due = tm
+			}
+		}
+		if due == nil {
+			break
+		}
+		r.now = max(r.now, due.next)
+		due.next += due.interval
+		due.fn(r.t)
+	}
+	// Real-time motion inside timers may have run past end.
+	r.now = max(r.now, end)
+}
+
+// FrameOptions controls how frame sequences are exported.
+type FrameOptions struct {
+	// OnionSkin is the number of previous frames drawn as ghosts beneath
+	// each frame. Zero disables onion skinning.
+	OnionSkin int
+	// OnionOpacity is the opacity of the most recent ghost, in (0,1].
+	// Older ghosts fade geometrically. Defaults to 0.3.
+	OnionOpacity float64
+
+	// Delay is how long each frame is shown in animations when the
+	// recording has no timing (no real-time mode or Advance), and how long
+	// the last frame is held. Defaults to 50ms.
+	Delay time.Duration
+	// LoopCount is the GIF loop count: 0 loops forever, -1 plays once.
+	LoopCount int
+
+	// Speed multiplies the playback speed of animations; 2 plays twice as
+	// fast. Zero means 1.
+	Speed float64
+	// SkipTravel drops the frames captured while the pen was up, so moves
+	// that draw nothing take no time and leave no frames behind.
+	SkipTravel bool
+	// FillSpeed further speeds up the frames captured while a fill was
+	// being traced, between BeginFill and EndFill. Zero means 1.
+	FillSpeed float64
+}
+
+// factor returns how much the time leading up to a frame of the given kind
+// is scaled during playback; zero drops the frame.
+func (o *FrameOptions) factor(kind frameKind) float64 {
+	if o == nil {
+		return 1
+	}
+	if o.SkipTravel && kind&travelFrame != 0 {
+		return 0
+	}
+	f := 1.0
+	if o.Speed > 0 {
+		f /= o.Speed
+	}
+	if o.FillSpeed > 0 && kind&fillingFrame != 0 {
+		f /= o.FillSpeed
+	}
+	return f
+}
+
+func (o *FrameOptions) delay() time.Duration {
+	if o == nil || o.Delay <= 0 {
+		return 50 * time.Millisecond
+	}
+	return o.Delay
+}
+
+// Frame returns frame i rendered with the given options applied.
+func (r *Recorder) Frame(i int, opts *FrameOptions) *image.RGBA {
+	if opts == nil || opts.OnionSkin <= 0 {
+		return r.frames[i]
+	}
+	return OnionSkin(r.frames, i, opts.OnionSkin, opts.OnionOpacity, r.t.bg)
+}
+
+// playbackTimes returns when each frame starts during playback. Each frame
+// accounts for the recording time since the previous one, or for opts.Delay
+// when the recording has no timing, scaled by the playback options.
+func (r *Recorder) playbackTimes(opts *FrameOptions) []time.Duration {
+	n := len(r.frames)
+	at := make([]time.Duration, n)
+	untimed := n > 0 && r.times[n-1] == r.times[0]
+	for i := 1; i < n; i++ {
+		step := opts.delay()
+		if !untimed {
+			step = r.times[i] - r.times[i-1]
+		}
+		at[i] = at[i-1] + time.Duration(float64(step)*opts.factor(r.kinds[i]))
+	}
+	return at
+}
+
+// SaveFrames writes every frame to dir as frame_0000.png, frame_0001.png, …
+// Frames skipped by SkipTravel are not written; the rest are numbered
+// consecutively.
+func (r *Recorder) SaveFrames(dir string, opts *FrameOptions) error {
+	n := 0
+	for i := range r.frames {
+		if i > 0 && opts.factor(r.kinds[i]) == 0 {
+			continue
+		}
+		name := filepath.Join(dir, fmt.Sprintf("frame_%04d.png", n))
+		if err := r.savePNG(name, r.Frame(i, opts)); err != nil {
+			return err
+		}
+		n++
+	}
+	return nil
+}
+
+// OnionSkin composes frames[i] over up to n previous frames. Pixels of a
+// previous frame that differ from bg are drawn as ghosts at reduced opacity,
+// the nearest frame strongest, then the non-background pixels of frames[i]
+// are drawn on top at full strength.
+func OnionSkin(frames []*image.RGBA, i, n int, opacity float64, bg color.Color) *image.RGBA {
+	if opacity <= 0 || opacity > 1 {
+		opacity = 0.3
+	}
+	cur := frames[i]
+	out := image.NewRGBA(cur.Bounds())
+	draw.Draw(out, out.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+	bgc := color.RGBAModel.Convert(bg).(color.RGBA)
+
+	for k := n; k >= 1; k-- {
+		if i-k < 0 {
+			continue
+		}
+		a := math.Pow(opacity, float64(k))
+		overlayForeground(out, frames[i-k], bgc, uint8(math.Round(a*255)))
+	}
+	overlayForeground(out, cur, bgc, 255)
+	return out
+}
+
+// overlayForeground draws the pixels of src that differ from bg onto dst
+// with the given opacity.
+func overlayForeground(dst, src *image.RGBA, bg color.RGBA, alpha uint8) {
+	b := src.Bounds().Intersect(dst.Bounds())
+	mask := image.NewAlpha(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if src.RGBAAt(x, y) != bg {
+				mask.SetAlpha(x, y, color.Alpha{A: alpha})
+			}
+		}
+	}
+	draw.DrawMask(dst, b, src, b.Min, mask, b.Min, draw.Over)
+}
+
+func (r *Recorder) savePNG(filename string, img image.Image) error {
+	return writeFile(filename, r.t.syncSaves, func(w io.Writer) error { return png.Encode(w, img) })
+}
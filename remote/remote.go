@@ -0,0 +1,320 @@
+// Package remote lets other programs drive turtles over HTTP, so block
+// editors, chat bots and the like can draw on a shared server-side canvas.
+//
+// Command endpoints answer with the turtle's state as JSON. Parameters may
+// be passed in the query string or as form values:
+//
+//	POST /forward?distance=100     POST /backward?distance=100
+//	POST /left?angle=90            POST /right?angle=90
+//	POST /heading?angle=0          POST /goto?x=10&y=20
+//	POST /penup                    POST /pendown
+//	POST /color?color=%23ff0000    POST /width?width=3
+//	POST /home  POST /clear  POST /reset
+//	POST /command                  body: {"cmd":"circle","args":[50]} or an array of them
+//	GET  /state                    GET  /snapshot.png
+//
+// Clients are not trusted: every endpoint runs only the commands Allowed
+// lists, which leaves out /clear and /reset unless they are added to it,
+// the body of /command is limited to a megabyte, and each request may run
+// at most 10,000 commands for 5 seconds, so no client can hang the server,
+// grow the canvas or wipe it.
+//
+// Several clients can share the canvas, each with a turtle of its own:
+// POST /turtles spawns one and answers {"id": "..."}; the routes above are
+// then available under /turtles/{id}/, e.g. POST /turtles/3/forward.
+// GET /turtles lists every turtle's state and DELETE /turtles/{id} removes
+// one (its drawing stays). Everybody can watch the shared canvas live at
+// /view/ (see gotuga.Turtle.PreviewHandler).
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/hexcolor"
+)
+
+// State is the turtle state reported by the command endpoints.
+type State struct {
+	ID      string  `json:"id"`
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Heading float64 `json:"heading"`
+	PenDown bool    `json:"penDown"`
+	Color   string  `json:"color"`
+	Width   float64 `json:"width"`
+}
+
+// Server is an http.Handler exposing turtles that share one canvas.
+// Commands to one turtle are serialized, so any number of clients may send
+// commands at once.
+type Server struct {
+	mux     *http.ServeMux
+	main    *entry
+	preview http.Handler
+
+	mu      sync.Mutex // guards turtles and nextID
+	turtles map[string]*entry
+	nextID  int
+}
+
+type entry struct {
+	mu      sync.Mutex
+	id      string
+	t       *gotuga.Turtle
+	removed bool // by DELETE; t is detached from the canvas
+}
+
+// mainID names the turtle passed to NewServer.
+const mainID = "main"
+
+// Limits on one request; maxBody and maxPolygonSides apply to /command.
+const (
+	maxBody         = 1 << 20
+	maxCommands     = 10000
+	maxCommandTime  = 5 * time.Second
+	maxPolygonSides = 1000
+)
+
+// Allowed lists the commands clients may run: moving, drawing and pen
+// settings, but nothing that changes the canvas's edges or timing. Clear
+// and reset, which wipe the drawings of everyone sharing the canvas, are
+// not allowed unless set here, say for a server with a single client.
+var Allowed = map[string]bool{
+	"forward": true, "backward": true, "left": true, "right": true,
+	"setheading": true, "goto": true, "home": true,
+	"penup": true, "pendown": true, "color": true, "width": true, "linejoin": true,
+	"rect": true, "polygon": true, "circle": true, "dot": true,
+	"beginfill": true, "fillcolor": true, "endfill": true, "fillrule": true,
+}
+
+// allow reports whether a client may run c.
+func allow(c gotuga.Command) bool {
+	if c.Name == "polygon" && len(c.Args) > 0 && !(math.Abs(c.Args[0]) <= maxPolygonSides) {
+		return false
+	}
+	return Allowed[c.Name]
+}
+
+// NewServer returns a server controlling t, with further turtles spawned on
+// its canvas on request. The turtle must not be used directly while the
+// server is running.
+func NewServer(t *gotuga.Turtle) *Server {
+	s := &Server{
+		mux:     http.NewServeMux(),
+		main:    &entry{id: mainID, t: t},
+		preview: t.PreviewHandler(),
+		turtles: make(map[string]*entry),
+		nextID:  1,
+	}
+	s.turtles[mainID] = s.main
+	s.handle("forward", "forward", "distance")
+	s.handle("backward", "backward", "distance")
+	s.handle("left", "left", "angle")
+	s.handle("right", "right", "angle")
+	s.handle("heading", "setheading", "angle")
+	s.handle("goto", "goto", "x", "y")
+	s.handle("width", "width", "width")
+	s.handle("penup", "penup")
+	s.handle("pendown", "pendown")
+	s.handle("home", "home")
+	s.handle("clear", "clear")
+	s.handle("reset", "reset")
+	s.route("POST", "color", s.serveColor)
+	s.route("POST", "command", s.serveCommand)
+	s.route("GET", "state", func(w http.ResponseWriter, e *entry, _ *http.Request) {
+		writeJSON(w, e.state())
+	})
+
+	s.mux.HandleFunc("POST /turtles", s.serveSpawn)
+	s.mux.HandleFunc("GET /turtles", s.serveList)
+	s.mux.HandleFunc("DELETE /turtles/{id}", s.serveRemove)
+	s.mux.Handle("/view/", http.StripPrefix("/view", s.preview))
+	s.mux.HandleFunc("GET /snapshot.png", func(w http.ResponseWriter, r *http.Request) {
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = "/canvas.png"
+		s.preview.ServeHTTP(w, r2)
+	})
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// route registers a per-turtle endpoint both for the main turtle, at /name,
+// and for spawned turtles, at /turtles/{id}/name. fn runs holding e.mu.
+func (s *Server) route(method, name string, fn func(w http.ResponseWriter, e *entry, r *http.Request)) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		e := s.main
+		if id := r.PathValue("id"); id != "" {
+			s.mu.Lock()
+			e = s.turtles[id]
+			s.mu.Unlock()
+			if e == nil {
+				http.Error(w, "no turtle "+id, http.StatusNotFound)
+				return
+			}
+		}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.removed {
+			http.Error(w, "no turtle "+e.id, http.StatusNotFound)
+			return
+		}
+		fn(w, e, r)
+	}
+	s.mux.HandleFunc(method+" /"+name, h)
+	s.mux.HandleFunc(method+" /turtles/{id}/"+name, h)
+}
+
+// handle registers POST /name, which parses the named numeric parameters
+// and runs the command cmd with them. Invalid parameters are answered with
+// 400 Bad Request.
+func (s *Server) handle(name, cmd string, params ...string) {
+	s.route("POST", name, func(w http.ResponseWriter, e *entry, r *http.Request) {
+		args := make([]float64, len(params))
+		for i, p := range params {
+			v, err := strconv.ParseFloat(r.FormValue(p), 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("parameter %s: %v", p, err), http.StatusBadRequest)
+				return
+			}
+			args[i] = v
+		}
+		runOne(w, e, gotuga.Command{Name: cmd, Args: args})
+	})
+}
+
+func (s *Server) serveColor(w http.ResponseWriter, e *entry, r *http.Request) {
+	c, err := hexcolor.Parse(r.FormValue("color"))
+	if err != nil {
+		http.Error(w, "parameter color: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	runOne(w, e, gotuga.Command{Name: "color", Args: []float64{float64(c.R), float64(c.G), float64(c.B), float64(c.A)}})
+}
+
+// runOne runs c on e's turtle, if clients may run it, within the limits of
+// one request, and answers with the turtle's state.
+func runOne(w http.ResponseWriter, e *entry, c gotuga.Command) {
+	if !allow(c) {
+		http.Error(w, fmt.Sprintf("command %q is not allowed", c.Name), http.StatusForbidden)
+		return
+	}
+	if err := limited(e.t, func() error { return e.t.Apply(c) }); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, e.state())
+}
+
+func (s *Server) serveCommand(w http.ResponseWriter, e *entry, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, maxBody)
+	if err := execLimited(e.t, body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, e.state())
+}
+
+// execLimited runs the allowed commands from r on t within the limits of
+// one request, returning a *gotuga.BudgetError if they run past them.
+func execLimited(t *gotuga.Turtle, r io.Reader) error {
+	return limited(t, func() error { return t.ExecAllowed(r, allow) })
+}
+
+// limited calls run with t's budget set to the limits of one request.
+func limited(t *gotuga.Turtle, run func() error) error {
+	t.SetStepBudget(maxCommands)
+	t.SetTimeBudget(maxCommandTime)
+	defer func() {
+		t.SetStepBudget(0)
+		t.SetTimeBudget(0)
+	}()
+	return run()
+}
+
+func (s *Server) serveSpawn(w http.ResponseWriter, r *http.Request) {
+	s.main.mu.Lock()
+	t := s.main.t.Spawn()
+	s.main.mu.Unlock()
+
+	s.mu.Lock()
+	e := &entry{id: strconv.Itoa(s.nextID), t: t}
+	s.nextID++
+	s.turtles[e.id] = e
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, e.state())
+}
+
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	entries := make([]*entry, 0, len(s.turtles))
+	for _, e := range s.turtles {
+		entries = append(entries, e)
+	}
+	s.mu.Unlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+
+	states := make([]State, len(entries))
+	for i, e := range entries {
+		e.mu.Lock()
+		states[i] = e.state()
+		e.mu.Unlock()
+	}
+	writeJSON(w, states)
+}
+
+func (s *Server) serveRemove(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == mainID {
+		http.Error(w, "the main turtle cannot be removed", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	e, ok := s.turtles[id]
+	delete(s.turtles, id)
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "no turtle "+id, http.StatusNotFound)
+		return
+	}
+	e.mu.Lock()
+	e.t.Detach()
+	e.removed = true
+	e.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// state reports the turtle's state; e.mu must be held.
+func (e *entry) state() State {
+	x, y := e.t.Position()
+	n := color.NRGBAModel.Convert(e.t.Color()).(color.NRGBA)
+	return State{
+		ID:      e.id,
+		X:       x,
+		Y:       y,
+		Heading: e.t.Heading(),
+		PenDown: e.t.IsDown(),
+		Color:   fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A),
+		Width:   e.t.Width(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
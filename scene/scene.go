@@ -0,0 +1,372 @@
+// Package scene renders drawings described as data rather than code: a
+// JSON document listing shapes with their positions, colors and repeats,
+// so drawings can be written, and rendered with the gotuga command, by
+// people who don't program. A sun:
+//
+//	{
+//	  "width": 300, "height": 300, "background": "skyblue",
+//	  "shapes": [
+//	    {"type": "circle", "radius": 50, "fill": "gold", "stroke": "orange", "width": 4},
+//	    {"type": "line", "x": 65, "length": 40, "stroke": "orange", "width": 3,
+//	     "repeat": {"count": 12, "turn": 30}}
+//	  ]
+//	}
+//
+// Coordinates are the turtle's: the origin is the middle of the canvas, y
+// grows upwards and headings are degrees counterclockwise from east.
+package scene
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/hexcolor"
+)
+
+// Scene is a whole drawing.
+type Scene struct {
+	Width      int     `json:"width"`      // 500 if not given, at most 16384
+	Height     int     `json:"height"`     // 500 if not given, at most 16384
+	Background string  `json:"background"` // white if not given
+	Shapes     []Shape `json:"shapes"`
+}
+
+// Shape is one shape of a scene, or a group of them. Which fields count
+// depends on Type:
+//
+//	line     from (X, Y) along Heading, Length long
+//	rect     W by H, centered on (X, Y) and turned by Heading
+//	square   Size across, like rect
+//	circle   Radius, centered on (X, Y)
+//	dot      Size across, centered on (X, Y); drawn in Fill, or else Stroke
+//	polygon  Sides sides Size long, centered on (X, Y), a vertex at Heading
+//	star     Sides points Size from (X, Y), the first at Heading
+//	path     lines through Points, relative to (X, Y); closed if filled
+//	group    Shapes, placed at (X, Y) and turned by Heading
+//
+// Colors are names such as "red" or "#rrggbb" or "#rrggbbaa"; "none" or
+// leaving Stroke out draws no outline when there is a Fill, and a black
+// one otherwise.
+type Shape struct {
+	Type    string  `json:"type"`
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Heading float64 `json:"heading"`
+
+	Length float64      `json:"length"`
+	W      float64      `json:"w"`
+	H      float64      `json:"h"`
+	Size   float64      `json:"size"`
+	Radius float64      `json:"radius"`
+	Sides  int          `json:"sides"`
+	Points [][2]float64 `json:"points"`
+
+	Stroke string  `json:"stroke"`
+	Fill   string  `json:"fill"`
+	Width  float64 `json:"width"` // pen width, 2 if not given
+
+	Repeat *Repeat `json:"repeat"`
+	Shapes []Shape `json:"shapes"`
+}
+
+// Repeat draws a shape Count times, each copy turned by Turn degrees and
+// scaled by Grow about the origin of the enclosing group or scene, then
+// moved by DX and DY, from the one before.
+type Repeat struct {
+	Count int     `json:"count"`
+	DX    float64 `json:"dx"`
+	DY    float64 `json:"dy"`
+	Turn  float64 `json:"turn"`
+	Grow  float64 `json:"grow"` // 1 if not given
+}
+
+// maxCopies limits the shapes one scene may draw, counting repeats of
+// repeats, so that a mistyped count cannot run for hours.
+const maxCopies = 1 << 20
+
+// maxSize limits the sides of a scene's canvas, which at most takes 1 GiB,
+// and maxSides those of its polygons and the points of its stars.
+const (
+	maxSize  = 1 << 14
+	maxSides = 1 << 12
+)
+
+// Decode reads a scene from JSON. Fields it does not know are errors, to
+// catch misspellings.
+func Decode(r io.Reader) (*Scene, error) {
+	var s Scene
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("scene: %w", err)
+	}
+	return &s, nil
+}
+
+// Render reads a scene from JSON and draws it on a new canvas.
+func Render(r io.Reader) (*gotuga.Turtle, error) {
+	s, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Render()
+}
+
+// Render draws the scene on a new canvas of its size and background.
+func (s *Scene) Render() (*gotuga.Turtle, error) {
+	w, h := s.Width, s.Height
+	if w <= 0 {
+		w = 500
+	}
+	if h <= 0 {
+		h = 500
+	}
+	if w > maxSize || h > maxSize {
+		return nil, fmt.Errorf("scene: %d×%d canvas is too large, at most %d on a side", w, h, maxSize)
+	}
+	var bg color.Color = color.White
+	if s.Background != "" {
+		c, err := parseColor(s.Background)
+		if err != nil {
+			return nil, fmt.Errorf("scene: background: %w", err)
+		}
+		bg = c
+	}
+	t := gotuga.New(w, h, bg)
+	return t, s.Draw(t)
+}
+
+// Draw draws the scene's shapes with t, leaving the turtle wherever the
+// last shape did.
+func (s *Scene) Draw(t *gotuga.Turtle) error {
+	r := &renderer{t: t}
+	return r.shapes(s.Shapes, transform{scale: 1}, "shapes")
+}
+
+// transform places shapes: a point (x, y) of a shape is drawn at
+// (ox, oy) plus (x, y) turned by rot degrees and scaled by scale.
+type transform struct {
+	ox, oy, rot, scale float64
+}
+
+func (tr transform) apply(x, y float64) (float64, float64) {
+	sin, cos := math.Sincos(tr.rot * math.Pi / 180)
+	return tr.ox + tr.scale*(x*cos-y*sin), tr.oy + tr.scale*(x*sin+y*cos)
+}
+
+// then returns the transform that applies inner, then tr.
+func (tr transform) then(inner transform) transform {
+	ox, oy := tr.apply(inner.ox, inner.oy)
+	return transform{ox, oy, tr.rot + inner.rot, tr.scale * inner.scale}
+}
+
+type renderer struct {
+	t      *gotuga.Turtle
+	copies int
+}
+
+func (r *renderer) shapes(shapes []Shape, tr transform, where string) error {
+	for i := range shapes {
+		sh := &shapes[i]
+		at := fmt.Sprintf("%s[%d]", where, i)
+		n, step := 1, transform{scale: 1}
+		if rp := sh.Repeat; rp != nil {
+			n = rp.Count
+			step = transform{rp.DX, rp.DY, rp.Turn, 1}
+			if rp.Grow != 0 {
+				step.scale = rp.Grow
+			}
+		}
+		local := transform{scale: 1} // the copy, in tr's coordinates
+		for k := 0; k < n; k++ {
+			if r.copies++; r.copies > maxCopies {
+				return fmt.Errorf("scene: %s: more than %d shapes", at, maxCopies)
+			}
+			if err := r.shape(sh, tr.then(local), at); err != nil {
+				return err
+			}
+			local = step.then(local)
+		}
+	}
+	return nil
+}
+
+// shape draws one copy of sh, placed by tr.
+func (r *renderer) shape(sh *Shape, tr transform, at string) error {
+	t := r.t
+	stroke, fill, err := sh.colors()
+	if err != nil {
+		return fmt.Errorf("scene: %s: %w", at, err)
+	}
+	// The shape's own position and heading.
+	tr = tr.then(transform{sh.X, sh.Y, sh.Heading, 1})
+	width := sh.Width
+	if width <= 0 {
+		width = 2
+	}
+	if stroke != nil {
+		t.SetColor(stroke)
+		t.SetWidth(width * tr.scale)
+	}
+
+	kind := strings.ToLower(sh.Type)
+	switch kind {
+	case "line":
+		r.outline(tr, [][2]float64{{0, 0}, {sh.Length, 0}}, stroke, nil)
+	case "rect":
+		w, h := sh.W/2, sh.H/2
+		r.outline(tr, [][2]float64{{-w, -h}, {w, -h}, {w, h}, {-w, h}, {-w, -h}}, stroke, fill)
+	case "square":
+		s := sh.Size / 2
+		r.outline(tr, [][2]float64{{-s, -s}, {s, -s}, {s, s}, {-s, s}, {-s, -s}}, stroke, fill)
+	case "circle":
+		x, y := tr.apply(0, 0)
+		rad := sh.Radius * tr.scale
+		t.PenUp()
+		t.GoTo(x, y-rad)
+		t.SetHeading(0)
+		if fill != nil {
+			t.FillColor(fill)
+			t.BeginFill()
+		}
+		if stroke != nil {
+			t.PenDown()
+		}
+		t.Circle(rad)
+		if fill != nil {
+			t.EndFill()
+		}
+	case "dot":
+		c := fill
+		if c == nil {
+			c = stroke
+		}
+		if c == nil {
+			break
+		}
+		t.PenUp()
+		t.GoTo(tr.apply(0, 0))
+		t.Dot(sh.Size*tr.scale, c)
+	case "polygon", "star":
+		if sh.Sides < 3 && kind == "polygon" || sh.Sides < 2 {
+			return fmt.Errorf("scene: %s: %d sides are too few", at, sh.Sides)
+		}
+		if sh.Sides > maxSides {
+			return fmt.Errorf("scene: %s: %d sides are too many, at most %d", at, sh.Sides, maxSides)
+		}
+		n := sh.Sides
+		rad := sh.Size
+		if kind == "polygon" {
+			rad = sh.Size / (2 * math.Sin(math.Pi/float64(n)))
+		} else {
+			n *= 2 // points and the hollows between them
+		}
+		pts := make([][2]float64, n+1)
+		for i := range pts {
+			a := 2 * math.Pi * float64(i%n) / float64(n)
+			d := rad
+			if kind == "star" && i%2 == 1 {
+				d = rad * 0.4
+			}
+			pts[i] = [2]float64{d * math.Cos(a), d * math.Sin(a)}
+		}
+		r.outline(tr, pts, stroke, fill)
+	case "path":
+		pts := sh.Points
+		if fill != nil && len(pts) > 0 && pts[0] != pts[len(pts)-1] {
+			pts = append(pts[:len(pts):len(pts)], pts[0])
+		}
+		r.outline(tr, pts, stroke, fill)
+	case "group":
+		return r.shapes(sh.Shapes, tr, at+".shapes")
+	default:
+		return fmt.Errorf("scene: %s: unknown shape type %q", at, sh.Type)
+	}
+	return nil
+}
+
+// outline draws lines through pts, placed by tr, in the pen color if
+// stroke is set, filling them with fill if that is.
+func (r *renderer) outline(tr transform, pts [][2]float64, stroke, fill color.Color) {
+	if len(pts) == 0 {
+		return
+	}
+	t := r.t
+	t.PenUp()
+	t.GoTo(tr.apply(pts[0][0], pts[0][1]))
+	if fill != nil {
+		t.FillColor(fill)
+		t.BeginFill()
+	}
+	if stroke != nil {
+		t.PenDown()
+	}
+	for _, p := range pts[1:] {
+		t.GoTo(tr.apply(p[0], p[1]))
+	}
+	if fill != nil {
+		t.EndFill()
+	}
+	t.PenUp()
+}
+
+// colors returns the outline and fill colors of the shape, nil for none.
+func (sh *Shape) colors() (stroke, fill color.Color, err error) {
+	if sh.Fill != "" && sh.Fill != "none" {
+		if fill, err = parseColor(sh.Fill); err != nil {
+			return nil, nil, fmt.Errorf("fill: %w", err)
+		}
+	}
+	switch {
+	case sh.Stroke == "none":
+	case sh.Stroke != "":
+		if stroke, err = parseColor(sh.Stroke); err != nil {
+			return nil, nil, fmt.Errorf("stroke: %w", err)
+		}
+	case fill == nil:
+		stroke = color.Black
+	}
+	return stroke, fill, nil
+}
+
+// parseColor parses a color name or #rrggbb or #rrggbbaa.
+func parseColor(s string) (color.Color, error) {
+	if c, ok := colorNames[strings.ToLower(strings.ReplaceAll(s, " ", ""))]; ok {
+		return c, nil
+	}
+	if c, err := hexcolor.Parse(s); err == nil {
+		return c, nil
+	}
+	return nil, fmt.Errorf("unknown color %q, want a name, #rrggbb or #rrggbbaa", s)
+}
+
+// colorNames are the color names scenes use most, with their web colors.
+var colorNames = map[string]color.NRGBA{
+	"black":       {0, 0, 0, 255},
+	"white":       {255, 255, 255, 255},
+	"red":         {255, 0, 0, 255},
+	"green":       {0, 128, 0, 255},
+	"lime":        {0, 255, 0, 255},
+	"blue":        {0, 0, 255, 255},
+	"navy":        {0, 0, 128, 255},
+	"skyblue":     {135, 206, 235, 255},
+	"yellow":      {255, 255, 0, 255},
+	"gold":        {255, 215, 0, 255},
+	"cyan":        {0, 255, 255, 255},
+	"magenta":     {255, 0, 255, 255},
+	"orange":      {255, 165, 0, 255},
+	"purple":      {128, 0, 128, 255},
+	"pink":        {255, 192, 203, 255},
+	"brown":       {165, 42, 42, 255},
+	"gray":        {128, 128, 128, 255},
+	"grey":        {128, 128, 128, 255},
+	"lightgray":   {211, 211, 211, 255},
+	"lightgrey":   {211, 211, 211, 255},
+	"darkgreen":   {0, 100, 0, 255},
+	"transparent": {},
+}
@@ -0,0 +1,97 @@
+package gotuga
+
+// ScheduleMode selects how a Scheduler interleaves turtles.
+type ScheduleMode int
+
+const (
+	// RoundRobin lets each turtle run one command per turn.
+	RoundRobin ScheduleMode = iota
+	// TimeSliced always runs the turtle with the least motion time (see
+	// SetRealTime), switching turtles after every command and every frame
+	// boundary, so turtles in real-time mode move simultaneously.
+	TimeSliced
+)
+
+// Scheduler interleaves the scripts of several turtles, usually spawned on
+// one canvas, so races, swarms and duets animate correctly on screen and in
+// recordings. Only one script runs at a time, so scripts need no locking.
+type Scheduler struct {
+	mode  ScheduleMode
+	tasks []*task
+}
+
+type task struct {
+	t      *Turtle
+	script func(t *Turtle)
+	resume chan struct{}
+	done   bool
+}
+
+// NewScheduler returns an empty scheduler using the given mode.
+func NewScheduler(mode ScheduleMode) *Scheduler {
+	return &Scheduler{mode: mode}
+}
+
+// Add registers script to be run on t by Run.
+func (s *Scheduler) Add(t *Turtle, script func(t *Turtle)) {
+	s.tasks = append(s.tasks, &task{t: t, script: script, resume: make(chan struct{})})
+}
+
+// Run executes all scripts, interleaved, and returns when every one of them
+// has finished.
+func (s *Scheduler) Run() {
+	yielded := make(chan *task)
+	var stops []func()
+	for _, tk := range s.tasks {
+		tk := tk
+		yield := func() {
+			yielded <- tk
+			<-tk.resume
+		}
+		stops = append(stops, tk.t.Observe(func(Command) { yield() }))
+		if s.mode == TimeSliced {
+			stops = append(stops, tk.t.observeFrames(yield))
+		}
+		go func() {
+			<-tk.resume
+			tk.script(tk.t)
+			tk.done = true
+			yielded <- tk
+		}()
+	}
+	defer func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}()
+
+	active := append([]*task(nil), s.tasks...)
+	next := 0
+	for len(active) > 0 {
+		i := s.pick(active, next)
+		active[i].resume <- struct{}{}
+		if tk := <-yielded; tk.done {
+			active = append(active[:i], active[i+1:]...)
+			next = i
+		} else {
+			next = i + 1
+		}
+	}
+}
+
+// pick returns the index of the task to run next; from is where round-robin
+// order continues.
+func (s *Scheduler) pick(active []*task, from int) int {
+	from %= len(active)
+	if s.mode != TimeSliced {
+		return from
+	}
+	best := from
+	for k := 1; k < len(active); k++ {
+		i := (from + k) % len(active)
+		if active[i].t.elapsed < active[best].t.elapsed {
+			best = i
+		}
+	}
+	return best
+}
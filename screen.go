@@ -0,0 +1,59 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+	"sync"
+)
+
+// screen is the state shared by all turtles drawing on one canvas.
+type screen struct {
+	mu      sync.Mutex               // held while a command runs
+	version uint64                   // incremented after every command
+	turtles []*Turtle                // every turtle drawing on the canvas
+	paths   []*Path                  // everything drawn since the canvas was cleared
+	clear   func(canvas *image.RGBA) // paints a cleared canvas, see pixels
+
+	borrowed    bool // the canvas is the caller's, see NewWithBuffer
+	retainPaths bool // see SetRetainPaths
+}
+
+// Spawn returns a new turtle that draws on the same canvas as t. It starts
+// at the origin facing east with the default pen, like a turtle from New.
+// Turtles sharing a canvas may be driven from different goroutines; their
+// commands are serialized.
+func (t *Turtle) Spawn() *Turtle {
+	s := &Turtle{
+		canvas:     t.canvas,
+		W:          t.W,
+		H:          t.H,
+		bg:         t.bg,
+		bgImage:    t.bgImage,
+		penDown:    true,
+		penColor:   color.Black,
+		penWidth:   2,
+		miterLimit: defaultMiterLimit,
+		screen:     t.screen,
+		scale:      t.scale,
+		moveSpeed:  defaultMoveSpeed,
+		turnSpeed:  defaultTurnSpeed,
+	}
+	t.screen.mu.Lock()
+	t.screen.turtles = append(t.screen.turtles, s)
+	t.screen.mu.Unlock()
+	return s
+}
+
+// Detach removes a turtle from the canvas it shares, so the canvas no
+// longer keeps it; what it drew stays. It must not be used afterwards.
+func (t *Turtle) Detach() {
+	t.screen.mu.Lock()
+	defer t.screen.mu.Unlock()
+	for i, o := range t.screen.turtles {
+		if o == t {
+			t.screen.turtles = append(t.screen.turtles[:i], t.screen.turtles[i+1:]...)
+			break
+		}
+	}
+	t.path = nil
+}
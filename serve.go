@@ -0,0 +1,95 @@
+package gotuga
+
+import (
+	"bytes"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"time"
+)
+
+const previewPage = `<!DOCTYPE html>
+<html><head><title>GoTuga</title></head>
+<body style="margin:0;background:#333;display:flex;justify-content:center;align-items:center;height:100vh">
+<img src="stream.mjpeg" alt="canvas" style="max-width:100%;max-height:100%">
+</body></html>
+`
+
+// Serve serves a live preview of the canvas over HTTP on addr. It blocks, so
+// it is usually started in its own goroutine before drawing:
+//
+//	go t.Serve(":8080")
+//
+// See PreviewHandler for the endpoints.
+func (t *Turtle) Serve(addr string) error {
+	return http.ListenAndServe(addr, t.PreviewHandler())
+}
+
+// PreviewHandler returns an http.Handler serving:
+//
+//	/             an HTML page showing the live stream
+//	/canvas.png   the current canvas as PNG
+//	/stream.mjpeg a motion-JPEG stream that pushes a frame whenever the canvas changes
+//
+// The canvas is copied between commands, so the handler is safe to use while
+// the turtle keeps drawing on another goroutine.
+func (t *Turtle) PreviewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, previewPage)
+	})
+	mux.HandleFunc("/canvas.png", func(w http.ResponseWriter, r *http.Request) {
+		img := t.Snapshot()
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-store")
+		png.Encode(w, img)
+	})
+	mux.HandleFunc("/stream.mjpeg", t.serveMJPEG)
+	return mux
+}
+
+// serveMJPEG streams JPEG frames as multipart/x-mixed-replace, checking for
+// changes up to 30 times per second.
+func (t *Turtle) serveMJPEG(w http.ResponseWriter, r *http.Request) {
+	const boundary = "gotugaframe"
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+boundary)
+	w.Header().Set("Cache-Control", "no-store")
+	flusher, _ := w.(http.Flusher)
+
+	tick := time.NewTicker(time.Second / 30)
+	defer tick.Stop()
+	var sent uint64
+	first := true
+	var buf bytes.Buffer
+	for {
+		if v := t.canvasVersion(); first || v != sent {
+			img, v := t.copyCanvas()
+			buf.Reset()
+			if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+				return
+			}
+			_, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", boundary, buf.Len())
+			if err == nil {
+				_, err = w.Write(append(buf.Bytes(), '\r', '\n'))
+			}
+			if err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			sent, first = v, false
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package gotuga
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/Z6dev/GoTuga/internal/hexcolor"
+)
+
+const sessionFormat = "gotuga-session"
+
+// Session is a drawing stored as its canvas configuration and the commands
+// that produced it, so it can be archived and re-rendered later at any
+// resolution. Sessions are saved as .tuga files: gzip-compressed JSON.
+type Session struct {
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	Background string    `json:"background"` // #rrggbbaa
+	Commands   []Command `json:"commands"`
+}
+
+type sessionFile struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Session
+}
+
+// Session returns the turtle's canvas configuration and command history,
+// which is empty unless the turtle records it (see SetRecordHistory).
+func (t *Turtle) Session() *Session {
+	return &Session{
+		Width:      t.W,
+		Height:     t.H,
+		Background: hexColor(t.bg),
+		Commands:   append([]Command(nil), t.history...),
+	}
+}
+
+// errNoHistory is returned when saving the session of a turtle that does
+// not record its history.
+var errNoHistory = errors.New("gotuga: no history to save, see SetRecordHistory")
+
+// SaveSession writes the turtle's session to filename as a .tuga file. It
+// fails unless the turtle records its history.
+func (t *Turtle) SaveSession(filename string) error {
+	if !t.recordHistory {
+		return errNoHistory
+	}
+	s := t.Session()
+	return writeFile(filename, t.syncSaves, s.Write)
+}
+
+// Save writes the session to filename as a .tuga file.
+func (s *Session) Save(filename string) error {
+	return writeFile(filename, false, s.Write)
+}
+
+// Write encodes the session in the .tuga format.
+func (s *Session) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(sessionFile{sessionFormat, 1, *s}); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// LoadSession reads a .tuga file.
+func LoadSession(filename string) (*Session, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSession(f)
+}
+
+// ReadSession decodes a session in the .tuga format. Uncompressed JSON is
+// accepted too.
+func ReadSession(r io.Reader) (*Session, error) {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		src = zr
+	}
+	var f sessionFile
+	if err := json.NewDecoder(src).Decode(&f); err != nil {
+		return nil, err
+	}
+	if f.Format != sessionFormat {
+		return nil, fmt.Errorf("gotuga: not a session file (format %q)", f.Format)
+	}
+	if f.Version != 1 {
+		return nil, fmt.Errorf("gotuga: unsupported session version %d", f.Version)
+	}
+	return &f.Session, nil
+}
+
+// Render replays the session on a new turtle whose canvas is scale times the
+// original size in each dimension. Logical coordinates are unchanged, so the
+// drawing is the same at a higher or lower resolution.
+func (s *Session) Render(scale float64) (*Turtle, error) {
+	if !(scale > 0) || math.IsInf(scale, 1) {
+		return nil, fmt.Errorf("gotuga: invalid scale %v", scale)
+	}
+	bg, err := hexcolor.Parse(s.Background)
+	if err != nil {
+		return nil, err
+	}
+	fw, fh := math.Round(float64(s.Width)*scale), math.Round(float64(s.Height)*scale)
+	if !(fw <= maxCanvasSide && fh <= maxCanvasSide) {
+		return nil, fmt.Errorf("gotuga: session of %d×%d is too large at scale %v", s.Width, s.Height, scale)
+	}
+	w, h := int(fw), int(fh)
+	if err := checkCanvasSize(w, h); err != nil {
+		return nil, err
+	}
+	t := New(w, h, bg)
+	t.scale = scale
+	for _, c := range s.Commands {
+		if err := t.Apply(c); err != nil {
+			return t, err
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,152 @@
+// Package shapes draws ready-made shapes with a turtle: hearts, arrows,
+// gears, clouds and speech bubbles.
+//
+//	t.SetColor(color.NRGBA{200, 0, 0, 255})
+//	t.FillColor(color.NRGBA{255, 120, 150, 255})
+//	t.BeginFill()
+//	shapes.Heart(t, 100)
+//	t.EndFill()
+//
+// Every shape is drawn at the turtle's position and turned by its heading:
+// facing east, the default, shapes are upright. Each is one closed outline
+// in the pen's color and width, so BeginFill and EndFill around it fill it,
+// and the turtle is put back where it started, pen and all.
+package shapes
+
+import (
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// Heart draws a heart size wide, centered on the turtle.
+func Heart(t *gotuga.Turtle, size float64) {
+	k := size / 32 // the curve is 32 wide and 29 tall, centered 2.5 down
+	n := segments(size * 3)
+	pts := make([][2]float64, n+1)
+	for i := range pts {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		s := math.Sin(a)
+		x := 16 * s * s * s
+		y := 13*math.Cos(a) - 5*math.Cos(2*a) - 2*math.Cos(3*a) - math.Cos(4*a)
+		pts[i] = [2]float64{k * x, k * (y + 2.5)}
+	}
+	outline(t, pts)
+}
+
+// Arrow draws an arrow length long from the turtle to its tip ahead, with
+// a head width across and a shaft a third of that. Unlike the other
+// shapes it points along the heading.
+func Arrow(t *gotuga.Turtle, length, width float64) {
+	head := math.Min(width, length)
+	w, s := width/2, width/6
+	outline(t, [][2]float64{
+		{0, -s}, {length - head, -s}, {length - head, -w}, {length, 0},
+		{length - head, w}, {length - head, s}, {0, s}, {0, -s},
+	})
+}
+
+// Gear draws a gear with the given number of teeth, radius to the tips of
+// the teeth and depth of the teeth, centered on the turtle, with a tooth
+// to its right.
+func Gear(t *gotuga.Turtle, teeth int, radius, depth float64) {
+	if teeth < 3 {
+		teeth = 3
+	}
+	root := radius - depth
+	pitch := 2 * math.Pi / float64(teeth)
+	// A tooth's flanks each take a sixth of the pitch, its top and the
+	// gap after it a third each; the gap follows the root circle.
+	arc := max(1, int(root*pitch/9))
+	var pts [][2]float64
+	at := func(r, a float64) { pts = append(pts, [2]float64{r * math.Cos(a), r * math.Sin(a)}) }
+	for i := 0; i < teeth; i++ {
+		a := float64(i)*pitch - pitch/3
+		at(root, a)
+		at(radius, a+pitch/6)
+		at(radius, a+pitch/2)
+		for j := 0; j < arc; j++ {
+			at(root, a+pitch*2/3+pitch/3*float64(j)/float64(arc))
+		}
+	}
+	pts = append(pts, pts[0])
+	outline(t, pts)
+}
+
+// Cloud draws a cloud w wide and h tall, centered on the turtle, with the
+// given number of puffs around its edge.
+func Cloud(t *gotuga.Turtle, w, h float64, puffs int) {
+	if puffs < 3 {
+		puffs = 3
+	}
+	// An ellipse scalloped outwards between puffs points, shrunk so the
+	// puffs reach w and h.
+	const bulge = 0.25
+	n := puffs * segments(math.Pi*(w+h)/float64(puffs))
+	rx, ry := w/2/(1+bulge), h/2/(1+bulge)
+	pts := make([][2]float64, n+1)
+	for i := range pts {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		f := 1 + bulge*math.Abs(math.Sin(a*float64(puffs)/2))
+		pts[i] = [2]float64{f * rx * math.Cos(a), f * ry * math.Sin(a)}
+	}
+	outline(t, pts)
+}
+
+// SpeechBubble draws a rounded w by h box centered on the turtle, with a
+// tail from the left of its bottom edge pointing down and left, to the
+// speaker, half of h below it.
+func SpeechBubble(t *gotuga.Turtle, w, h float64) {
+	r := math.Min(w, h) / 4
+	x, y := w/2, h/2
+	var pts [][2]float64
+	corner := func(cx, cy, from float64) {
+		n := segments(r * math.Pi / 2)
+		for i := 0; i <= n; i++ {
+			a := (from + 90*float64(i)/float64(n)) * math.Pi / 180
+			pts = append(pts, [2]float64{cx + r*math.Cos(a), cy + r*math.Sin(a)})
+		}
+	}
+	corner(x-r, -y+r, 270)
+	corner(x-r, y-r, 0)
+	corner(-x+r, y-r, 90)
+	corner(-x+r, -y+r, 180)
+	// The tail, on the bottom edge between the last corner and the first.
+	base := -x + r
+	tail := math.Min(w/4, h/2)
+	pts = append(pts,
+		[2]float64{base, -y}, [2]float64{base - tail/2, -y - h/2},
+		[2]float64{base + tail, -y}, pts[0])
+	outline(t, pts)
+}
+
+// outline draws lines through pts, given with x to the turtle's front and
+// y to its left, then puts the turtle back.
+func outline(t *gotuga.Turtle, pts [][2]float64) {
+	x, y := t.Position()
+	heading, down := t.Heading(), t.IsDown()
+	sin, cos := math.Sincos(heading * math.Pi / 180)
+	at := func(p [2]float64) (float64, float64) {
+		return x + p[0]*cos - p[1]*sin, y + p[0]*sin + p[1]*cos
+	}
+	t.PenUp()
+	t.GoTo(at(pts[0]))
+	if down {
+		t.PenDown()
+	}
+	for _, p := range pts[1:] {
+		t.GoTo(at(p))
+	}
+	t.PenUp()
+	t.GoTo(x, y)
+	t.SetHeading(heading)
+	if down {
+		t.PenDown()
+	}
+}
+
+// segments returns how many lines to draw a curve of the given length
+// with, about 3 units each, as Circle does.
+func segments(length float64) int {
+	return int(math.Max(12, math.Min(1<<12, math.Abs(length)/3)))
+}
@@ -0,0 +1,37 @@
+package gotuga
+
+import (
+	"image"
+	"time"
+)
+
+// FrameSink consumes a stream of frames equally spaced at its frame rate,
+// such as a video encoder. All frames have the same size.
+type FrameSink interface {
+	WriteFrame(img *image.RGBA) error
+	Close() error
+	FPS() int // frames per second
+}
+
+// Export plays the recording into sink at the sink's frame rate, repeating
+// or dropping frames to follow the playback timing (see FrameOptions). The
+// last frame is held for opts.Delay. Export does not close the sink.
+func (r *Recorder) Export(sink FrameSink, opts *FrameOptions) error {
+	fps := sink.FPS()
+	if len(r.frames) == 0 || fps <= 0 {
+		return nil
+	}
+	at := r.playbackTimes(opts)
+	end := at[len(at)-1] + opts.delay()
+	interval := time.Second / time.Duration(fps)
+	i := 0
+	for t := time.Duration(0); t < end; t += interval {
+		for i+1 < len(at) && at[i+1] <= t {
+			i++
+		}
+		if err := sink.WriteFrame(r.Frame(i, opts)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
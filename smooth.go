@@ -0,0 +1,135 @@
+package gotuga
+
+import "math"
+
+// Chaikin smooths a polyline by corner cutting: each pass replaces every
+// segment by points a quarter and three quarters along it. Three or four
+// passes give a smooth curve. An open polyline keeps its end points; a
+// closed one (first point equal to the last) stays closed.
+func Chaikin(pts [][2]float64, passes int) [][2]float64 {
+	out := append([][2]float64(nil), pts...)
+	if len(pts) < 3 {
+		return out
+	}
+	closed := pts[0] == pts[len(pts)-1]
+	for ; passes > 0; passes-- {
+		next := make([][2]float64, 0, 2*len(out))
+		if !closed {
+			next = append(next, out[0])
+		}
+		for i := 0; i+1 < len(out); i++ {
+			a, b := out[i], out[i+1]
+			next = append(next, lerp2(a, b, 0.25), lerp2(a, b, 0.75))
+		}
+		if closed {
+			next = append(next, next[0])
+		} else {
+			next = append(next, out[len(out)-1])
+		}
+		out = next
+	}
+	return out
+}
+
+// RoundCorners replaces every corner of a polyline by a circular arc of the
+// given radius, shrunk where the segments beside it are too short to fit
+// it. A closed polyline (first point equal to the last) also has its
+// starting corner rounded.
+func RoundCorners(pts [][2]float64, radius float64) [][2]float64 {
+	if len(pts) < 3 || radius <= 0 {
+		return append([][2]float64(nil), pts...)
+	}
+	closed := pts[0] == pts[len(pts)-1]
+	n := len(pts)
+	if closed {
+		n-- // the last point repeats the first
+	}
+	var out [][2]float64
+	for i := 0; i < n; i++ {
+		if !closed && (i == 0 || i == n-1) {
+			out = append(out, pts[i])
+			continue
+		}
+		prev, p, next := pts[(i+n-1)%n], pts[i], pts[(i+1)%n]
+		out = append(out, roundCorner(prev, p, next, radius)...)
+	}
+	if closed {
+		out = append(out, out[0])
+	}
+	return out
+}
+
+// roundCorner returns the arc replacing the corner at p between segments
+// from prev and to next.
+func roundCorner(prev, p, next [2]float64, radius float64) [][2]float64 {
+	ux, uy := prev[0]-p[0], prev[1]-p[1]
+	vx, vy := next[0]-p[0], next[1]-p[1]
+	lu, lv := math.Hypot(ux, uy), math.Hypot(vx, vy)
+	if lu == 0 || lv == 0 {
+		return [][2]float64{p}
+	}
+	ux, uy, vx, vy = ux/lu, uy/lu, vx/lv, vy/lv
+	angle := math.Acos(math.Max(-1, math.Min(1, ux*vx+uy*vy))) // between the segments
+	if angle < 1e-6 || math.Pi-angle < 1e-6 {
+		return [][2]float64{p} // a reversal or a straight line: no arc
+	}
+	// The arc touches both segments at distance d from the corner, using
+	// at most half of each so neighbouring corners do not overlap.
+	half := angle / 2
+	d := math.Min(radius/math.Tan(half), math.Min(lu, lv)/2)
+	r := d * math.Tan(half)
+	ax, ay := p[0]+ux*d, p[1]+uy*d
+	bx, by := p[0]+vx*d, p[1]+vy*d
+	bis := math.Hypot(ux+vx, uy+vy)
+	cx := p[0] + (ux+vx)/bis*r/math.Sin(half)
+	cy := p[1] + (uy+vy)/bis*r/math.Sin(half)
+	a0 := math.Atan2(ay-cy, ax-cx)
+	sweep := math.Atan2(by-cy, bx-cx) - a0
+	if sweep > math.Pi {
+		sweep -= 2 * math.Pi
+	} else if sweep < -math.Pi {
+		sweep += 2 * math.Pi
+	}
+	steps := int(math.Max(2, math.Ceil(math.Abs(sweep)*r/2)))
+	arc := make([][2]float64, 0, steps+1)
+	for k := 0; k <= steps; k++ {
+		a := a0 + sweep*float64(k)/float64(steps)
+		arc = append(arc, [2]float64{cx + r*math.Cos(a), cy + r*math.Sin(a)})
+	}
+	return arc
+}
+
+func lerp2(a, b [2]float64, f float64) [2]float64 {
+	return [2]float64{a[0] + f*(b[0]-a[0]), a[1] + f*(b[1]-a[1])}
+}
+
+// DrawPolyline moves to the first point without drawing and then through
+// the rest, e.g. to draw a captured path after smoothing it:
+//
+//	t.BeginPoly()
+//	... // draw a jagged shape with the pen up
+//	t.EndPoly()
+//	t.PenDown()
+//	t.DrawPolyline(gotuga.Chaikin(t.Poly(), 4))
+//
+// It is recorded as the GoTo commands it performs.
+func (t *Turtle) DrawPolyline(pts [][2]float64) {
+	if len(pts) == 0 {
+		return
+	}
+	t.JumpTo(pts[0][0], pts[0][1])
+	for _, p := range pts[1:] {
+		t.GoTo(p[0], p[1])
+	}
+}
+
+// SmoothPath smooths the retained paths, as returned by Paths, with passes
+// of Chaikin corner cutting, before vector export. The canvas is not
+// changed.
+func (t *Turtle) SmoothPath(passes int) {
+	t.screen.mu.Lock()
+	defer t.screen.mu.Unlock()
+	for _, p := range t.screen.paths {
+		p.Points = Chaikin(p.Points, passes)
+	}
+}
@@ -0,0 +1,41 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+)
+
+// fillSpan sets the pixels of img from x0 up to x1 on row y to c. The
+// first pixel is written and then copied, doubling the filled stretch
+// each time, which is several times faster than setting pixels one by
+// one on wide spans.
+func fillSpan(img *image.RGBA, x0, x1, y int, c color.RGBA) {
+	if x1 <= x0 {
+		return
+	}
+	row := img.Pix[img.PixOffset(x0, y):][:4*(x1-x0)]
+	row[0], row[1], row[2], row[3] = c.R, c.G, c.B, c.A
+	for n := 4; n < len(row); n *= 2 {
+		copy(row[n:], row[:n])
+	}
+}
+
+// fillRect sets the pixels of img in r to c, copying the first row into
+// the others.
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Rect)
+	if r.Empty() {
+		return
+	}
+	fillSpan(img, r.Min.X, r.Max.X, r.Min.Y, c)
+	first := img.Pix[img.PixOffset(r.Min.X, r.Min.Y):][:4*r.Dx()]
+	for y := r.Min.Y + 1; y < r.Max.Y; y++ {
+		copy(img.Pix[img.PixOffset(r.Min.X, y):], first)
+	}
+}
+
+// solid reports whether painting col at full coverage just replaces
+// pixels, so that spans of it can be filled in bulk.
+func (t *Turtle) solid(col color.NRGBA) bool {
+	return col.A == 255 && t.clip == nil
+}
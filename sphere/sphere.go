@@ -0,0 +1,283 @@
+// Package sphere is a turtle that walks on a globe, drawn on a gotuga
+// canvas through a map projection.
+//
+//	t := gotuga.New(720, 360, color.White)
+//	s := sphere.New(t, sphere.Equirectangular(0), 360/math.Pi)
+//	s.Graticule(30)
+//	s.PenUp()
+//	s.GoTo(51.5, -0.1) // London
+//	s.PenDown()
+//	s.GoTo(40.7, -74)  // the great circle to New York
+//
+// Positions are latitude and longitude in degrees. Headings are in degrees
+// counterclockwise from east, as for the 2D turtle, so 90 is north.
+// Distances are degrees of arc along a great circle: Forward(360) goes
+// once around the globe.
+package sphere
+
+import (
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+const rad = math.Pi / 180
+
+// Projection maps the globe onto the plane.
+type Projection struct {
+	// Project returns where a point lands on a map of the unit sphere, or
+	// ok false if it is not shown, e.g. on the far side of an
+	// orthographic globe.
+	Project func(lat, lon float64) (x, y float64, ok bool)
+
+	// Seam is the longitude where the map is cut open, if it is; lines
+	// crossing it are broken there instead of drawn across the map.
+	Seam    float64
+	HasSeam bool
+}
+
+// Equirectangular is the plate carrée: longitude and latitude in radians
+// as x and y, centered on longitude lon0.
+func Equirectangular(lon0 float64) Projection {
+	return Projection{
+		Project: func(lat, lon float64) (float64, float64, bool) {
+			return relLon(lon, lon0) * rad, lat * rad, true
+		},
+		Seam: lon0 + 180, HasSeam: true,
+	}
+}
+
+// Mercator is the conformal cylindrical projection centered on longitude
+// lon0, cut off beyond 85° north and south.
+func Mercator(lon0 float64) Projection {
+	return Projection{
+		Project: func(lat, lon float64) (float64, float64, bool) {
+			if math.Abs(lat) > 85 {
+				return 0, 0, false
+			}
+			return relLon(lon, lon0) * rad, math.Log(math.Tan(math.Pi/4 + lat*rad/2)), true
+		},
+		Seam: lon0 + 180, HasSeam: true,
+	}
+}
+
+// Sinusoidal is the equal-area projection centered on longitude lon0,
+// with meridians curving in towards the poles.
+func Sinusoidal(lon0 float64) Projection {
+	return Projection{
+		Project: func(lat, lon float64) (float64, float64, bool) {
+			return relLon(lon, lon0) * rad * math.Cos(lat*rad), lat * rad, true
+		},
+		Seam: lon0 + 180, HasSeam: true,
+	}
+}
+
+// Orthographic shows the globe as seen from far away above (lat0, lon0);
+// the far hemisphere is hidden.
+func Orthographic(lat0, lon0 float64) Projection {
+	s0, c0 := math.Sincos(lat0 * rad)
+	return Projection{
+		Project: func(lat, lon float64) (float64, float64, bool) {
+			s, c := math.Sincos(lat * rad)
+			sl, cl := math.Sincos((lon - lon0) * rad)
+			if s0*s+c0*c*cl < 0 {
+				return 0, 0, false
+			}
+			return c * sl, c0*s - s0*c*cl, true
+		},
+	}
+}
+
+// relLon returns lon relative to lon0, in [-180, 180).
+func relLon(lon, lon0 float64) float64 {
+	return math.Mod(math.Mod(lon-lon0+180, 360)+360, 360) - 180
+}
+
+// vec3 is a point or direction in space, with the globe a unit sphere
+// around the origin, the north pole at +z and longitude 0 at +x.
+type vec3 struct{ x, y, z float64 }
+
+func (v vec3) add(w vec3) vec3      { return vec3{v.x + w.x, v.y + w.y, v.z + w.z} }
+func (v vec3) scale(k float64) vec3 { return vec3{v.x * k, v.y * k, v.z * k} }
+func (v vec3) dot(w vec3) float64   { return v.x*w.x + v.y*w.y + v.z*w.z }
+func (v vec3) cross(w vec3) vec3 {
+	return vec3{v.y*w.z - v.z*w.y, v.z*w.x - v.x*w.z, v.x*w.y - v.y*w.x}
+}
+func (v vec3) unit() vec3 {
+	if n := math.Sqrt(v.dot(v)); n > 0 {
+		return v.scale(1 / n)
+	}
+	return v
+}
+
+func fromLatLon(lat, lon float64) vec3 {
+	s, c := math.Sincos(lat * rad)
+	sl, cl := math.Sincos(lon * rad)
+	return vec3{c * cl, c * sl, s}
+}
+
+func (v vec3) latLon() (lat, lon float64) {
+	return math.Asin(math.Max(-1, math.Min(1, v.z))) / rad, math.Atan2(v.y, v.x) / rad
+}
+
+// Turtle walks on the globe and draws with a 2D turtle.
+type Turtle struct {
+	T          *gotuga.Turtle
+	Projection Projection
+	Scale      float64 // logical units per unit of the projection
+
+	p, h vec3 // position and heading, both unit vectors
+}
+
+// New returns a turtle at latitude and longitude 0 heading east, drawing
+// with t through proj. For the cylindrical projections, a scale of
+// t.W/(2π) makes the map fill the canvas's width.
+func New(t *gotuga.Turtle, proj Projection, scale float64) *Turtle {
+	s := &Turtle{T: t, Projection: proj, Scale: scale, p: vec3{1, 0, 0}, h: vec3{0, 1, 0}}
+	s.sync()
+	return s
+}
+
+// Position returns the turtle's latitude and longitude.
+func (s *Turtle) Position() (lat, lon float64) { return s.p.latLon() }
+
+// Heading returns the turtle's heading, counterclockwise from east. It is
+// 0 at the poles, where east is undefined, if the turtle heads along +x.
+func (s *Turtle) Heading() float64 {
+	east := vec3{0, 0, 1}.cross(s.p).unit()
+	if east.dot(east) == 0 {
+		east = vec3{0, 1, 0}
+	}
+	north := s.p.cross(east)
+	return math.Atan2(s.h.dot(north), s.h.dot(east)) / rad
+}
+
+// SetHeading turns the turtle to deg degrees counterclockwise from east.
+func (s *Turtle) SetHeading(deg float64) {
+	s.turn(deg - s.Heading())
+}
+
+// Left turns left by deg degrees.
+func (s *Turtle) Left(deg float64) { s.turn(deg) }
+
+// Right turns right by deg degrees.
+func (s *Turtle) Right(deg float64) { s.turn(-deg) }
+
+func (s *Turtle) turn(deg float64) {
+	sn, c := math.Sincos(deg * rad)
+	s.h = s.h.scale(c).add(s.p.cross(s.h).scale(sn)).unit()
+}
+
+// PenUp stops drawing.
+func (s *Turtle) PenUp() { s.T.PenUp() }
+
+// PenDown starts drawing.
+func (s *Turtle) PenDown() { s.T.PenDown() }
+
+// Forward walks deg degrees of arc along the great circle ahead.
+func (s *Turtle) Forward(deg float64) {
+	n := int(math.Ceil(math.Abs(deg)))
+	if n == 0 {
+		return
+	}
+	step := deg / float64(n) * rad
+	sn, c := math.Sincos(step)
+	pts := [][2]float64{latLon(s.p)}
+	for i := 0; i < n; i++ {
+		s.p, s.h = s.p.scale(c).add(s.h.scale(sn)).unit(), s.h.scale(c).add(s.p.scale(-sn)).unit()
+		pts = append(pts, latLon(s.p))
+	}
+	s.trace(pts)
+}
+
+// Backward walks deg degrees of arc back along the great circle.
+func (s *Turtle) Backward(deg float64) { s.Forward(-deg) }
+
+// GoTo walks the shorter great circle to (lat, lon), ending up heading
+// along it.
+func (s *Turtle) GoTo(lat, lon float64) {
+	q := fromLatLon(lat, lon)
+	axis := s.p.cross(q)
+	if axis.dot(axis) < 1e-24 {
+		if s.p.dot(q) < 0 { // antipodal: any great circle will do
+			s.Forward(180)
+		}
+		s.p = q
+		return
+	}
+	s.h = axis.unit().cross(s.p)
+	s.Forward(math.Acos(math.Max(-1, math.Min(1, s.p.dot(q)))) / rad)
+	s.p = q
+}
+
+// Graticule draws parallels and meridians every step degrees, then returns
+// the turtle to where it was.
+func (s *Turtle) Graticule(step float64) {
+	if step <= 0 {
+		return
+	}
+	down := s.T.IsDown()
+	s.T.PenDown()
+	for lat := -90 + step; lat < 90; lat += step {
+		var pts [][2]float64
+		for lon := -180.0; lon <= 180; lon++ {
+			pts = append(pts, [2]float64{lat, lon})
+		}
+		s.trace(pts)
+	}
+	for lon := -180.0; lon < 180; lon += step {
+		var pts [][2]float64
+		for lat := -90.0; lat <= 90; lat++ {
+			pts = append(pts, [2]float64{lat, lon})
+		}
+		s.trace(pts)
+	}
+	if !down {
+		s.T.PenUp()
+	}
+	s.sync()
+}
+
+func latLon(v vec3) [2]float64 {
+	lat, lon := v.latLon()
+	return [2]float64{lat, lon}
+}
+
+// trace draws a line through points given as latitude and longitude, if
+// the pen is down, breaking it where points are hidden or it crosses the
+// seam. It leaves the 2D turtle at the last point, if shown.
+func (s *Turtle) trace(pts [][2]float64) {
+	if !s.T.IsDown() {
+		s.sync()
+		return
+	}
+	connected := false
+	for i, pt := range pts {
+		x, y, ok := s.Projection.Project(pt[0], pt[1])
+		if !ok {
+			connected = false
+			continue
+		}
+		if connected && s.Projection.HasSeam {
+			lon0 := s.Projection.Seam - 180
+			if math.Abs(relLon(pt[1], lon0)-relLon(pts[i-1][1], lon0)) > 180 {
+				connected = false
+			}
+		}
+		if connected {
+			s.T.GoTo(x*s.Scale, y*s.Scale)
+		} else {
+			s.T.JumpTo(x*s.Scale, y*s.Scale)
+		}
+		connected = true
+	}
+}
+
+// sync moves the 2D turtle, without drawing, to the turtle's place on the
+// map, if it is shown.
+func (s *Turtle) sync() {
+	lat, lon := s.p.latLon()
+	if x, y, ok := s.Projection.Project(lat, lon); ok {
+		s.T.JumpTo(x*s.Scale, y*s.Scale)
+	}
+}
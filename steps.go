@@ -0,0 +1,121 @@
+package gotuga
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Step is one command of a drawing with the canvas after it, as StepMode
+// reports it.
+type Step struct {
+	N       int // from 1, counting from the call to StepMode
+	Command Command
+	Text    string      // the command in words, see Command.Describe
+	Image   *image.RGBA // a copy of the canvas, as Image has it
+}
+
+// StepMode calls fn after every top-level command with a numbered Step,
+// for a sequence of slides explaining how a drawing is built, until the
+// returned function is called.
+func (t *Turtle) StepMode(fn func(s Step)) (stop func()) {
+	n := 0
+	return t.Observe(func(c Command) {
+		n++
+		fn(Step{N: n, Command: c, Text: c.Describe(), Image: cloneRGBA(t.output())})
+	})
+}
+
+// SaveSteps runs StepMode, writing each step's image to dir as
+// step_0001.png, step_0002.png, … and a line such as "0002 turn left 90°"
+// to dir/steps.txt. Call the returned function to stop; it reports the
+// first error writing any of them, after which no more are written.
+func (t *Turtle) SaveSteps(dir string) (stop func() error) {
+	var (
+		text *os.File
+		err  error
+	)
+	cancel := t.StepMode(func(s Step) {
+		if err != nil {
+			return
+		}
+		if text == nil {
+			if text, err = os.Create(filepath.Join(dir, "steps.txt")); err != nil {
+				return
+			}
+		}
+		name := filepath.Join(dir, fmt.Sprintf("step_%04d.png", s.N))
+		err = writeFile(name, t.syncSaves, func(w io.Writer) error { return png.Encode(w, s.Image) })
+		if err == nil {
+			_, err = fmt.Fprintf(text, "%04d %s\n", s.N, s.Text)
+		}
+	})
+	return func() error {
+		cancel()
+		if text != nil {
+			if cerr := text.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}
+}
+
+// descriptions phrase commands in words, with a verb for each argument.
+var descriptions = map[string]string{
+	"forward":    "move forward %g",
+	"backward":   "move back %g",
+	"left":       "turn left %g°",
+	"right":      "turn right %g°",
+	"setheading": "turn to face %g°",
+	"goto":       "go to (%g, %g)",
+	"home":       "go home to (0, 0)",
+	"penup":      "lift the pen",
+	"pendown":    "put the pen down",
+	"width":      "set the pen width to %g",
+	"clear":      "clear the canvas",
+	"reset":      "start again",
+	"rect":       "draw a %g by %g rectangle",
+	"polygon":    "draw a polygon of %g sides %g long",
+	"circle":     "draw a circle of radius %g",
+	"dot":        "draw a dot %g across",
+	"beginfill":  "start a shape to fill",
+	"endfill":    "fill the shape",
+	"up":         "climb %g",
+	"down":       "descend %g",
+}
+
+// Describe returns the command in words, such as "turn left 90°", for
+// captions and step-by-step explanations. Commands without a description
+// are given by name and arguments.
+func (c Command) Describe() string {
+	switch c.Name {
+	case "color", "fillcolor":
+		what := "pen"
+		if c.Name == "fillcolor" {
+			what = "fill"
+		}
+		if c := argColor(c.Args); c != nil {
+			return fmt.Sprintf("set the %s color to %s", what, hexColor(c))
+		}
+	}
+	if d, ok := descriptions[c.Name]; ok {
+		if n := strings.Count(d, "%"); n <= len(c.Args) {
+			args := make([]any, n)
+			for i := range args {
+				args[i] = c.Args[i]
+			}
+			return fmt.Sprintf(d, args...)
+		}
+	}
+	var b strings.Builder
+	b.WriteString(c.Name)
+	for _, a := range c.Args {
+		fmt.Fprintf(&b, " %g", a)
+	}
+	return b.String()
+}
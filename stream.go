@@ -0,0 +1,162 @@
+package gotuga
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/Z6dev/GoTuga/internal/hexcolor"
+)
+
+// streamItem is one value of a JSON command stream: a command, or the
+// optional canvas header that may come first.
+type streamItem struct {
+	Command
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Background string `json:"background"`
+}
+
+// Exec runs a stream of JSON commands from r, in the format reported by
+// Observe: {"cmd":"forward","args":[100]}. The stream may hold one command
+// per value (e.g. newline-delimited) or a single JSON array of commands.
+// A canvas header at the start of the stream (see RenderJSON) is skipped.
+func (t *Turtle) Exec(r io.Reader) error {
+	return t.ExecAllowed(r, nil)
+}
+
+// ExecAllowed is like Exec but runs only the commands allow accepts; the
+// first it refuses is an error, and the rest of the stream is not run. A
+// nil allow accepts every command. Servers running commands from clients
+// can keep them to drawing this way.
+func (t *Turtle) ExecAllowed(r io.Reader, allow func(c Command) bool) error {
+	first := true
+	return decodeStream(r, func(it streamItem) error {
+		header := first
+		first = false
+		if it.Name == "" && header {
+			return nil
+		}
+		if it.Name == "" {
+			return errors.New("gotuga: missing cmd")
+		}
+		if allow != nil && !allow(it.Command) {
+			return fmt.Errorf("gotuga: command %q is not allowed", it.Name)
+		}
+		return t.Apply(it.Command)
+	})
+}
+
+// RenderJSON renders a JSON command stream (see Exec) on a new canvas. The
+// first value may be a header without "cmd" setting the canvas, e.g.
+// {"width":800,"height":600,"background":"#ffffff"}; otherwise the canvas
+// is 500×500 and white.
+func RenderJSON(r io.Reader) (*Turtle, error) {
+	return RenderJSONWith(r, nil)
+}
+
+// RenderJSONWith is like RenderJSON but calls setup, unless it is nil,
+// with the new turtle before running any command, to set a budget for
+// instance.
+func RenderJSONWith(r io.Reader, setup func(t *Turtle)) (*Turtle, error) {
+	var t *Turtle
+	newTurtle := func(h streamItem) (err error) {
+		if t, err = newFromHeader(h); err == nil && setup != nil {
+			setup(t)
+		}
+		return err
+	}
+	err := decodeStream(r, func(it streamItem) error {
+		if t == nil {
+			if err := newTurtle(it); err != nil || it.Name == "" {
+				return err
+			}
+		}
+		if it.Name == "" {
+			return errors.New("gotuga: missing cmd")
+		}
+		return t.Apply(it.Command)
+	})
+	if t == nil && err == nil {
+		err = newTurtle(streamItem{})
+	}
+	return t, err
+}
+
+// newFromHeader creates the canvas described by a stream header; items that
+// are not headers give the default canvas.
+func newFromHeader(h streamItem) (*Turtle, error) {
+	w, ht := 500, 500
+	var bg color.Color = color.White
+	if h.Name == "" {
+		if h.Width > 0 {
+			w = h.Width
+		}
+		if h.Height > 0 {
+			ht = h.Height
+		}
+		if h.Background != "" {
+			c, err := hexcolor.Parse(h.Background)
+			if err != nil {
+				return nil, err
+			}
+			bg = c
+		}
+	}
+	if err := checkCanvasSize(w, ht); err != nil {
+		return nil, err
+	}
+	return New(w, ht, bg), nil
+}
+
+// decodeStream calls fn for every value of a JSON stream or array.
+func decodeStream(r io.Reader, fn func(it streamItem) error) error {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(br)
+	inArray := first == '['
+	if inArray {
+		dec.Token() // opening bracket
+	}
+	for i := 0; ; i++ {
+		if inArray && !dec.More() {
+			_, err := dec.Token() // closing bracket
+			return err
+		}
+		var it streamItem
+		if err := dec.Decode(&it); err != nil {
+			if !inArray && errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("stream item %d: %w", i, err)
+		}
+		if err := fn(it); err != nil {
+			return fmt.Errorf("stream item %d: %w", i, err)
+		}
+	}
+}
+
+// peekNonSpace returns the first byte after any JSON whitespace, leaving it
+// unread.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
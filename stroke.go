@@ -0,0 +1,52 @@
+package gotuga
+
+import (
+	"image"
+	"image/color"
+)
+
+// paint composites col over the canvas pixel (x, y) for the current
+// stroke. A translucent pen paints each pixel once per stroke, however
+// many segments or round joins cover it, so a stroke has an even tint
+// rather than darker blotches where its pieces overlap.
+func (t *Turtle) paint(x, y int, col color.NRGBA) {
+	t.paintCoverage(x, y, col, 255)
+}
+
+// paintCoverage is paint for a pixel the stroke covers only part of, cov
+// out of 255. Each pixel ends up painted by the largest coverage any piece
+// of the stroke gives it.
+func (t *Turtle) paintCoverage(x, y int, col color.NRGBA, cov uint8) {
+	f := 1.0
+	if col.A < 255 || cov < 255 {
+		if t.stroke == nil || t.stroke.Rect != t.canvas.Rect {
+			t.stroke = image.NewAlpha(t.canvas.Rect)
+		}
+		i := t.stroke.PixOffset(x, y)
+		prev := t.stroke.Pix[i]
+		if prev >= cov {
+			return
+		}
+		t.stroke.Pix[i] = cov
+		t.strokeRect = t.strokeRect.Union(image.Rect(x, y, x+1, y+1))
+		// Painting over what prev painted adds up to painting cov once.
+		f = float64(cov-prev) / float64(255-prev)
+	}
+	t.painted++
+	canvas := t.pixels()
+	over(canvas.Pix[canvas.PixOffset(x, y):][:4], col, f, t.blendLinear())
+}
+
+// endStroke ends the current stroke, so that what is drawn next paints
+// over it. Lifting or putting down the pen, changing its color or width,
+// and drawing anything other than lines end a stroke.
+func (t *Turtle) endStroke() {
+	t.joining = false
+	if t.strokeRect.Empty() {
+		return
+	}
+	for y := t.strokeRect.Min.Y; y < t.strokeRect.Max.Y; y++ {
+		clear(t.stroke.Pix[t.stroke.PixOffset(t.strokeRect.Min.X, y):t.stroke.PixOffset(t.strokeRect.Max.X, y)])
+	}
+	t.strokeRect = image.Rectangle{}
+}
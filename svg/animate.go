@@ -0,0 +1,143 @@
+package svg
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/atomicfile"
+)
+
+// AnimateOptions configures WriteAnimated. The zero value is usable.
+type AnimateOptions struct {
+	Speed float64 // logical units drawn per second, defaults to 200
+	Fade  float64 // seconds each fill takes to fade in, defaults to 0.3
+	Loop  bool    // start over, after a pause, once everything is drawn
+}
+
+// SaveAnimated writes t's drawing to a file as an animated SVG; see
+// WriteAnimated.
+func SaveAnimated(filename string, t *gotuga.Turtle, opts *AnimateOptions) error {
+	return atomicfile.Write(filename, t.SyncSaves(), func(w io.Writer) error { return WriteAnimated(w, t, opts) })
+}
+
+// WriteAnimated writes t's retained paths (see gotuga.Turtle.Paths) as an
+// SVG the size of its canvas in which the lines draw themselves one after
+// another, in the order the turtle drew them, and fills fade in when they
+// are reached. The animation is CSS on stroke-dashoffset, so the file is
+// small and plays in browsers with no script or raster frames. The turtle
+// keeps paths only with retention on, see gotuga.Turtle.SetRetainPaths.
+func WriteAnimated(w io.Writer, t *gotuga.Turtle, opts *AnimateOptions) error {
+	o := AnimateOptions{Speed: 200, Fade: 0.3}
+	if opts != nil {
+		o.Loop = opts.Loop
+		if opts.Speed > 0 {
+			o.Speed = opts.Speed
+		}
+		if opts.Fade > 0 {
+			o.Fade = opts.Fade
+		}
+	}
+	paths := t.Paths()
+
+	// Each path starts when the one before it ends.
+	starts := make([]float64, len(paths))
+	durs := make([]float64, len(paths))
+	dash := make([]float64, len(paths)) // stroke length on the canvas, plus a margin
+	total := 0.0
+	for i, p := range paths {
+		starts[i] = total
+		dash[i] = length(p.Points)*t.Scale() + 1
+		if p.Fill != nil {
+			durs[i] = o.Fade
+		} else {
+			durs[i] = math.Max(length(p.Points)/o.Speed, 0.01)
+		}
+		total += durs[i]
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", t.W, t.H, t.W, t.H)
+	if fill, opacity, ok := svgColor(t.Background()); ok {
+		fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s" fill-opacity="%s"/>`+"\n", fill, opacity)
+	}
+	bw.WriteString("<style>\n")
+	if o.Loop {
+		// One cycle covers the drawing and a pause of a quarter of it, so
+		// every element's keyframes are stretched to the cycle.
+		cycle := math.Max(total*1.25, 0.01)
+		for i, p := range paths {
+			a, b := 100*starts[i]/cycle, 100*(starts[i]+durs[i])/cycle
+			prop, from, to := "stroke-dashoffset", num(dash[i]), "0"
+			if p.Fill != nil {
+				prop, from, to = "opacity", "0", "1"
+			}
+			fmt.Fprintf(bw, "@keyframes p%d{0%%,%s%%{%s:%s}%s%%,100%%{%s:%s}}\n", i, num(a), prop, from, num(b), prop, to)
+			fmt.Fprintf(bw, "#p%d{animation:p%d %ss linear infinite}\n", i, i, num(cycle))
+		}
+	} else {
+		bw.WriteString("@keyframes draw{to{stroke-dashoffset:0}}\n@keyframes show{to{opacity:1}}\n")
+		for i, p := range paths {
+			name := "draw"
+			if p.Fill != nil {
+				name = "show"
+			}
+			fmt.Fprintf(bw, "#p%d{animation:%s %ss linear %ss forwards}\n", i, name, num(durs[i]), num(starts[i]))
+		}
+	}
+	bw.WriteString("</style>\n")
+
+	for i, p := range paths {
+		if len(p.Points) == 0 {
+			continue
+		}
+		var d strings.Builder
+		for j, pt := range p.Points {
+			x, y := t.CanvasPoint(pt[0], pt[1])
+			if j == 0 {
+				fmt.Fprintf(&d, "M%s %s", num(x), num(y))
+			} else {
+				fmt.Fprintf(&d, "L%s %s", num(x), num(y))
+			}
+		}
+		if p.Fill != nil {
+			fill, opacity, _ := svgColor(p.Fill)
+			fmt.Fprintf(bw, `<path id="p%d" d="%sZ" fill="%s" fill-opacity="%s" opacity="0"/>`+"\n", i, d.String(), fill, opacity)
+			continue
+		}
+		stroke, opacity, _ := svgColor(p.Color)
+		fmt.Fprintf(bw, `<path id="p%d" d="%s" fill="none" stroke="%s" stroke-opacity="%s" stroke-width="%s" stroke-linecap="round" stroke-linejoin="round" stroke-dasharray="%s" stroke-dashoffset="%[6]s"/>`+"\n",
+			i, d.String(), stroke, opacity, num(p.Width*t.Scale()), num(dash[i]))
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// length returns the length of a polyline.
+func length(pts [][2]float64) float64 {
+	l := 0.0
+	for i := 1; i < len(pts); i++ {
+		l += math.Hypot(pts[i][0]-pts[i-1][0], pts[i][1]-pts[i-1][1])
+	}
+	return l
+}
+
+// svgColor returns c as an SVG color and opacity, and whether it is
+// visible at all.
+func svgColor(c color.Color) (col, opacity string, ok bool) {
+	if c == nil {
+		return "none", "0", false
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B), num(float64(n.A) / 255), n.A > 0
+}
+
+// num formats a number compactly.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
@@ -0,0 +1,388 @@
+// Package svg reads SVG path data and traces it with a turtle, so existing
+// vector art can be re-stroked, animated as it is drawn, recorded or sent
+// to exporters. WriteAnimated goes the other way, writing a drawing as an
+// SVG that draws itself.
+//
+//	heart := "M 0 30 C 0 0 50 0 50 30 C 50 60 0 80 0 100 C 0 80 -50 60 -50 30 C -50 0 0 0 0 30 Z"
+//	if err := svg.Trace(t, heart, 2); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// SVG's y axis points down; traced drawings are flipped so they appear the
+// right way up.
+package svg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// Parse flattens SVG path data (the d attribute of a <path>) into
+// polylines, one per subpath, in SVG coordinates. Curves and arcs are split
+// into segments about a unit long. Every path command is supported.
+func Parse(d string) ([][][2]float64, error) {
+	p := &parser{s: d}
+	var out [][][2]float64
+	var cur [][2]float64
+	var x, y, startX, startY float64
+	var ctrlX, ctrlY float64 // last control point, for S and T
+	var prev byte
+	flush := func() {
+		if len(cur) > 1 {
+			out = append(out, cur)
+		}
+		cur = nil
+	}
+	lineTo := func(nx, ny float64) {
+		if len(cur) == 0 {
+			cur = [][2]float64{{x, y}}
+		}
+		cur = append(cur, [2]float64{nx, ny})
+		x, y = nx, ny
+	}
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		cmd := prev
+		if c := p.s[p.i]; isCommand(c) {
+			cmd = c
+			p.i++
+		} else if prev == 0 {
+			return nil, p.errorf("expected a command")
+		}
+		rel := cmd >= 'a'
+		ox, oy := 0.0, 0.0
+		if rel {
+			ox, oy = x, y
+		}
+		upper := cmd &^ 0x20
+		switch upper {
+		case 'Z':
+			if len(cur) > 0 {
+				lineTo(startX, startY)
+			}
+			flush()
+			x, y = startX, startY
+			prev = 0 // a command letter must follow
+			ctrlX, ctrlY = x, y
+			continue
+		case 'M':
+			nums, err := p.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			flush()
+			x, y = ox+nums[0], oy+nums[1]
+			startX, startY = x, y
+			// Further coordinate pairs are implicit line-tos.
+			cmd = 'L' | cmd&0x20
+		case 'L':
+			nums, err := p.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			lineTo(ox+nums[0], oy+nums[1])
+		case 'H':
+			nums, err := p.numbers(1)
+			if err != nil {
+				return nil, err
+			}
+			lineTo(ox+nums[0], y)
+		case 'V':
+			nums, err := p.numbers(1)
+			if err != nil {
+				return nil, err
+			}
+			if rel {
+				lineTo(x, y+nums[0])
+			} else {
+				lineTo(x, nums[0])
+			}
+		case 'C', 'S':
+			var c1x, c1y float64
+			var rest []float64
+			if upper == 'C' {
+				nums, err := p.numbers(6)
+				if err != nil {
+					return nil, err
+				}
+				c1x, c1y, rest = ox+nums[0], oy+nums[1], nums[2:]
+			} else {
+				nums, err := p.numbers(4)
+				if err != nil {
+					return nil, err
+				}
+				c1x, c1y = x, y
+				if pu := prev &^ 0x20; pu == 'C' || pu == 'S' {
+					c1x, c1y = 2*x-ctrlX, 2*y-ctrlY
+				}
+				rest = nums
+			}
+			c2x, c2y := ox+rest[0], oy+rest[1]
+			ex, ey := ox+rest[2], oy+rest[3]
+			x0, y0 := x, y
+			n := segments(math.Hypot(c1x-x0, c1y-y0) + math.Hypot(c2x-c1x, c2y-c1y) + math.Hypot(ex-c2x, ey-c2y))
+			for k := 1; k <= n; k++ {
+				s := float64(k) / float64(n)
+				a, b, c, d := (1-s)*(1-s)*(1-s), 3*(1-s)*(1-s)*s, 3*(1-s)*s*s, s*s*s
+				lineTo(a*x0+b*c1x+c*c2x+d*ex, a*y0+b*c1y+c*c2y+d*ey)
+			}
+			ctrlX, ctrlY = c2x, c2y
+		case 'Q', 'T':
+			var cx, cy, ex, ey float64
+			if upper == 'Q' {
+				nums, err := p.numbers(4)
+				if err != nil {
+					return nil, err
+				}
+				cx, cy, ex, ey = ox+nums[0], oy+nums[1], ox+nums[2], oy+nums[3]
+			} else {
+				nums, err := p.numbers(2)
+				if err != nil {
+					return nil, err
+				}
+				cx, cy = x, y
+				if pu := prev &^ 0x20; pu == 'Q' || pu == 'T' {
+					cx, cy = 2*x-ctrlX, 2*y-ctrlY
+				}
+				ex, ey = ox+nums[0], oy+nums[1]
+			}
+			x0, y0 := x, y
+			n := segments(math.Hypot(cx-x0, cy-y0) + math.Hypot(ex-cx, ey-cy))
+			for k := 1; k <= n; k++ {
+				s := float64(k) / float64(n)
+				a, b, c := (1-s)*(1-s), 2*(1-s)*s, s*s
+				lineTo(a*x0+b*cx+c*ex, a*y0+b*cy+c*ey)
+			}
+			ctrlX, ctrlY = cx, cy
+		case 'A':
+			nums, err := p.arcArgs()
+			if err != nil {
+				return nil, err
+			}
+			for _, pt := range arc(x, y, nums[0], nums[1], nums[2], nums[3] != 0, nums[4] != 0, ox+nums[5], oy+nums[6]) {
+				lineTo(pt[0], pt[1])
+			}
+		}
+		if upper != 'C' && upper != 'S' && upper != 'Q' && upper != 'T' {
+			ctrlX, ctrlY = x, y
+		}
+		prev = cmd
+	}
+	flush()
+	return out, nil
+}
+
+func isCommand(c byte) bool {
+	switch c &^ 0x20 {
+	case 'M', 'L', 'H', 'V', 'C', 'S', 'Q', 'T', 'A', 'Z':
+		return true
+	}
+	return false
+}
+
+// segments returns how many segments to split a curve of about the given
+// length into.
+func segments(length float64) int {
+	return int(math.Max(4, math.Min(1000, math.Ceil(length))))
+}
+
+// arc returns points along an SVG elliptical arc from (x0, y0) to (x, y),
+// excluding the start, following the SVG specification's conversion to
+// center parameterization.
+func arc(x0, y0, rx, ry, rotation float64, large, sweep bool, x, y float64) [][2]float64 {
+	if x0 == x && y0 == y {
+		return nil
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return [][2]float64{{x, y}}
+	}
+	sinPhi, cosPhi := math.Sincos(rotation * math.Pi / 180)
+	dx, dy := (x0-x)/2, (y0-y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+	// Scale up radii too small to reach the end point.
+	if l := x1*x1/(rx*rx) + y1*y1/(ry*ry); l > 1 {
+		rx, ry = rx*math.Sqrt(l), ry*math.Sqrt(l)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	k := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		k = -k
+	}
+	cx1, cy1 := k*rx*y1/ry, -k*ry*x1/rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (x0+x)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (y0+y)/2
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	t0 := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	dt := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && dt > 0 {
+		dt -= 2 * math.Pi
+	} else if sweep && dt < 0 {
+		dt += 2 * math.Pi
+	}
+	n := segments(math.Abs(dt) * math.Max(rx, ry))
+	pts := make([][2]float64, 0, n)
+	for i := 1; i < n; i++ {
+		s, c := math.Sincos(t0 + dt*float64(i)/float64(n))
+		pts = append(pts, [2]float64{cosPhi*rx*c - sinPhi*ry*s + cx, sinPhi*rx*c + cosPhi*ry*s + cy})
+	}
+	return append(pts, [2]float64{x, y}) // exactly at the end point
+}
+
+// parser reads numbers from path data.
+type parser struct {
+	s string
+	i int
+}
+
+func (p *parser) done() bool { return p.i >= len(p.s) }
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("svg: path data at offset %d: %s", p.i, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() {
+		switch p.s[p.i] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			p.i++
+		default:
+			return
+		}
+	}
+}
+
+// numbers reads n numbers.
+func (p *parser) numbers(n int) ([]float64, error) {
+	nums := make([]float64, n)
+	for k := range nums {
+		v, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		nums[k] = v
+	}
+	return nums, nil
+}
+
+// arcArgs reads an arc's seven arguments, whose two flags may be written
+// without separators, as in "a25 25 0 1050 50".
+func (p *parser) arcArgs() ([]float64, error) {
+	nums := make([]float64, 7)
+	for k := range nums {
+		if k == 3 || k == 4 {
+			p.skipSpace()
+			if p.done() || (p.s[p.i] != '0' && p.s[p.i] != '1') {
+				return nil, p.errorf("expected an arc flag")
+			}
+			nums[k] = float64(p.s[p.i] - '0')
+			p.i++
+			continue
+		}
+		v, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		nums[k] = v
+	}
+	return nums, nil
+}
+
+// number reads one number. Numbers may run together where unambiguous, as
+// in "1-2" or "0.5.5".
+func (p *parser) number() (float64, error) {
+	p.skipSpace()
+	start := p.i
+	if !p.done() && (p.s[p.i] == '+' || p.s[p.i] == '-') {
+		p.i++
+	}
+	digits, dot := false, false
+	for !p.done() {
+		c := p.s[p.i]
+		if c >= '0' && c <= '9' {
+			digits = true
+		} else if c == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+		p.i++
+	}
+	if digits && !p.done() && (p.s[p.i] == 'e' || p.s[p.i] == 'E') {
+		j := p.i + 1
+		if j < len(p.s) && (p.s[j] == '+' || p.s[j] == '-') {
+			j++
+		}
+		if j < len(p.s) && p.s[j] >= '0' && p.s[j] <= '9' {
+			p.i = j
+			for !p.done() && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+				p.i++
+			}
+		}
+	}
+	if !digits {
+		p.i = start
+		return 0, p.errorf("expected a number")
+	}
+	return strconv.ParseFloat(p.s[start:p.i], 64)
+}
+
+// Trace draws SVG path data with the turtle, scale logical units per SVG
+// unit, with the SVG origin at the turtle's position. The pen is lifted
+// between subpaths, and the turtle returns to where it started.
+func Trace(t *gotuga.Turtle, d string, scale float64) error {
+	paths, err := Parse(d)
+	if err != nil {
+		return err
+	}
+	TracePolylines(t, paths, scale)
+	return nil
+}
+
+// TracePolylines draws polylines from Parse like Trace does.
+func TracePolylines(t *gotuga.Turtle, paths [][][2]float64, scale float64) {
+	x0, y0 := t.Position()
+	for _, path := range paths {
+		pts := make([][2]float64, len(path))
+		for i, p := range path {
+			pts[i] = [2]float64{x0 + p[0]*scale, y0 - p[1]*scale}
+		}
+		t.DrawPolyline(pts)
+	}
+	t.JumpTo(x0, y0)
+}
+
+// ReadPaths returns the d attribute of every <path> element in an SVG
+// document, in document order. Transforms and other shapes are ignored.
+func ReadPaths(r io.Reader) ([]string, error) {
+	dec := xml.NewDecoder(r)
+	var ds []string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return ds, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("svg: %w", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "path" {
+			for _, a := range se.Attr {
+				if a.Name.Local == "d" {
+					ds = append(ds, a.Value)
+				}
+			}
+		}
+	}
+}
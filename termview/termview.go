@@ -0,0 +1,258 @@
+// Package termview shows a turtle's canvas live in the terminal while a
+// drawing program runs, for machines reached only over SSH. Each character
+// cell shows two pixels of a scaled-down canvas using the upper half block
+// and 24-bit color. It is a separate module so that the core package stays
+// dependency-free.
+//
+// Controls: Space pauses and resumes, S executes a single command while
+// paused, and Q or Esc quits.
+package termview
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Options configures a View. The zero value is usable.
+type Options struct {
+	Delay time.Duration // pause after every command, to slow drawing down
+	FPS   int           // terminal refreshes per second, defaults to 15
+
+	Screen tcell.Screen // defaults to the terminal
+}
+
+// View displays a turtle's canvas in the terminal and lets the user pause
+// and step while the drawing program runs.
+type View struct {
+	t    *gotuga.Turtle
+	opts Options
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	frame    *image.RGBA // latest copy of the canvas, guarded by mu
+	dirty    bool
+	paused   bool
+	stepping bool
+	done     bool // the drawing program has returned
+	lastCopy time.Time
+}
+
+// New creates a view of t. Nothing is shown until Run is called.
+func New(t *gotuga.Turtle, opts *Options) *View {
+	v := &View{t: t}
+	if opts != nil {
+		v.opts = *opts
+	}
+	if v.opts.FPS <= 0 {
+		v.opts.FPS = 15
+	}
+	v.cond = sync.NewCond(&v.mu)
+	v.frame = image.NewRGBA(t.Image().Bounds())
+	return v
+}
+
+// Run takes over the terminal and calls draw on a separate goroutine. It
+// blocks until the user quits, which they may do before draw returns; the
+// drawing then carries on without the view. draw may be nil.
+func (v *View) Run(draw func(t *gotuga.Turtle)) error {
+	s := v.opts.Screen
+	if s == nil {
+		var err error
+		if s, err = tcell.NewScreen(); err != nil {
+			return err
+		}
+	}
+	if err := s.Init(); err != nil {
+		return err
+	}
+	defer s.Fini()
+
+	v.capture()
+	stop := v.t.Observe(func(gotuga.Command) {
+		v.afterCommand()
+	})
+	defer stop()
+
+	go func() {
+		if draw != nil {
+			draw(v.t)
+		}
+		v.capture()
+		v.mu.Lock()
+		v.done = true
+		v.mu.Unlock()
+	}()
+
+	quit := make(chan struct{})
+	defer close(quit)
+	events := make(chan tcell.Event)
+	go func() {
+		for {
+			ev := s.PollEvent()
+			if ev == nil {
+				return
+			}
+			select {
+			case events <- ev:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(time.Second / time.Duration(v.opts.FPS))
+	defer ticker.Stop()
+	v.render(s)
+	for {
+		select {
+		case ev := <-events:
+			switch ev := ev.(type) {
+			case *tcell.EventKey:
+				if v.handleKey(ev) {
+					v.release()
+					return nil
+				}
+				v.render(s)
+			case *tcell.EventResize:
+				s.Sync()
+				v.render(s)
+			}
+		case <-ticker.C:
+			v.mu.Lock()
+			dirty := v.dirty
+			v.mu.Unlock()
+			if dirty {
+				v.render(s)
+			}
+		}
+	}
+}
+
+// handleKey applies a key press and reports whether it quits.
+func (v *View) handleKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return true
+	case tcell.KeyRune:
+	default:
+		return false
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	switch ev.Rune() {
+	case 'q', 'Q':
+		return true
+	case ' ':
+		v.paused = !v.paused
+		v.cond.Broadcast()
+	case 's', 'S':
+		if v.paused {
+			v.stepping = true
+			v.cond.Broadcast()
+		}
+	}
+	return false
+}
+
+// release lets a drawing goroutine blocked on pause finish.
+func (v *View) release() {
+	v.mu.Lock()
+	v.paused = false
+	v.cond.Broadcast()
+	v.mu.Unlock()
+}
+
+// afterCommand runs on the drawing goroutine after every command.
+func (v *View) afterCommand() {
+	v.mu.Lock()
+	throttled := time.Since(v.lastCopy) < time.Second/time.Duration(v.opts.FPS)
+	paused := v.paused
+	v.mu.Unlock()
+	if !throttled || v.opts.Delay > 0 || paused {
+		v.capture()
+	}
+	if v.opts.Delay > 0 {
+		time.Sleep(v.opts.Delay)
+	}
+
+	v.mu.Lock()
+	for v.paused && !v.stepping {
+		v.cond.Wait()
+	}
+	v.stepping = false
+	v.mu.Unlock()
+}
+
+// capture copies the canvas into the frame shown by the view.
+func (v *View) capture() {
+	v.mu.Lock()
+	if img := v.t.Image(); img.Bounds() != v.frame.Bounds() {
+		v.frame = image.NewRGBA(img.Bounds()) // the canvas was expanded
+	}
+	copy(v.frame.Pix, v.t.Image().Pix)
+	v.dirty = true
+	v.lastCopy = time.Now()
+	v.mu.Unlock()
+}
+
+// render draws the latest frame, scaled to fit above a status line.
+func (v *View) render(s tcell.Screen) {
+	v.mu.Lock()
+	frame := v.frame
+	v.dirty = false
+	status := "drawing"
+	switch {
+	case v.done:
+		status = "done"
+	case v.paused:
+		status = "paused, s to step"
+	}
+	v.mu.Unlock()
+
+	s.Clear()
+	cols, rows := s.Size()
+	rows-- // status line
+	b := frame.Bounds()
+	if cols > 0 && rows > 0 && !b.Empty() {
+		// Each cell is one pixel wide and two high.
+		f := min(float64(cols)/float64(b.Dx()), float64(2*rows)/float64(b.Dy()))
+		w, h := max(1, int(float64(b.Dx())*f)), max(1, int(float64(b.Dy())*f))
+		x0, y0 := (cols-w)/2, (rows-(h+1)/2)/2
+		for cy := 0; cy < (h+1)/2; cy++ {
+			for cx := 0; cx < w; cx++ {
+				top := average(frame, b, cx, 2*cy, w, h)
+				bottom := average(frame, b, cx, 2*cy+1, w, h)
+				style := tcell.StyleDefault.Foreground(top).Background(bottom)
+				s.SetContent(x0+cx, y0+cy, '▀', nil, style)
+			}
+		}
+	}
+	for i, r := range " " + status + " · space pause · q quit" {
+		s.SetContent(i, rows, r, nil, tcell.StyleDefault.Reverse(true))
+	}
+	s.Show()
+}
+
+// average returns the mean color of the canvas pixels that make up pixel
+// (x, y) of the canvas scaled to w×h, over black where transparent. Pixels
+// below the scaled canvas are black.
+func average(img *image.RGBA, b image.Rectangle, x, y, w, h int) tcell.Color {
+	if y >= h {
+		return tcell.ColorBlack
+	}
+	sx0, sx1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+	sy0, sy1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+	sx1, sy1 = max(sx1, sx0+1), max(sy1, sy0+1)
+	var r, g, bl, n int
+	for sy := sy0; sy < sy1; sy++ {
+		for sx := sx0; sx < sx1; sx++ {
+			p := img.Pix[img.PixOffset(sx, sy):]
+			r, g, bl, n = r+int(p[0]), g+int(p[1]), bl+int(p[2]), n+1
+		}
+	}
+	return tcell.NewRGBColor(int32(r/n), int32(g/n), int32(bl/n))
+}
@@ -0,0 +1,12 @@
+module github.com/Z6dev/GoTuga/text
+
+go 1.24.5
+
+require (
+	github.com/Z6dev/GoTuga v0.0.0
+	golang.org/x/image v0.26.0
+)
+
+require golang.org/x/text v0.24.0 // indirect
+
+replace github.com/Z6dev/GoTuga => ../
@@ -0,0 +1,123 @@
+// Package text writes text on a turtle's canvas with TrueType and OpenType
+// fonts, and measures it first so it can be centered and boxed.
+//
+//	f := text.Default(24)
+//	w, h := f.MeasureText("Hello")
+//	t.Rect(w+8, h+8) // a box around it
+//	text.Write(t, f, "Hello", text.Left)
+//
+// Sizes and measurements are in logical units; a 24-unit font is 24 pixels
+// high on a canvas of scale 1, and scaled up pixel by pixel on others. Text
+// is drawn in the pen color as an image stamped on the canvas (see
+// gotuga.Turtle.StampImage), so it is not part of the turtle's retained
+// paths.
+package text
+
+import (
+	"image"
+	"math"
+	"os"
+	"strings"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font is a font face at one size.
+type Font struct {
+	face    font.Face
+	ascent  float64 // above the baseline
+	descent float64 // below the baseline
+	line    float64 // baseline to baseline
+}
+
+// Default returns the bundled Go Regular font at the given size.
+func Default(size float64) *Font {
+	f, err := ParseFont(goregular.TTF, size)
+	if err != nil {
+		panic("text: bundled font: " + err.Error())
+	}
+	return f
+}
+
+// LoadFont reads a TrueType or OpenType font file at the given size.
+func LoadFont(path string, size float64) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFont(data, size)
+}
+
+// ParseFont parses TrueType or OpenType font data at the given size.
+func ParseFont(data []byte, size float64) (*Font, error) {
+	ft, err := opentype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	face, err := opentype.NewFace(ft, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		return nil, err
+	}
+	m := face.Metrics()
+	return &Font{
+		face:    face,
+		ascent:  float26(m.Ascent),
+		descent: float26(m.Descent),
+		line:    float26(m.Height),
+	}, nil
+}
+
+func float26(v fixed.Int26_6) float64 { return float64(v) / 64 }
+
+// MeasureText returns the width of s and its height from the top of the
+// first line to the bottom of the last. Lines are separated by "\n".
+func (f *Font) MeasureText(s string) (w, h float64) {
+	lines := strings.Split(s, "\n")
+	for _, l := range lines {
+		w = math.Max(w, float26(font.MeasureString(f.face, l)))
+	}
+	return w, f.ascent + f.descent + float64(len(lines)-1)*f.line
+}
+
+// Ascent returns how far the font rises above the baseline.
+func (f *Font) Ascent() float64 { return f.ascent }
+
+// Descent returns how far the font drops below the baseline.
+func (f *Font) Descent() float64 { return f.descent }
+
+// Align places text horizontally relative to the turtle.
+type Align int
+
+const (
+	Left   Align = iota // text starts at the turtle
+	Center              // text is centered on the turtle
+	Right               // text ends at the turtle
+)
+
+// Write draws s in the pen color with the first line's baseline at the
+// turtle's position. Lines are separated by "\n" and aligned with each
+// other the same way. The turtle does not move.
+func Write(t *gotuga.Turtle, f *Font, s string, align Align) {
+	w, h := f.MeasureText(s)
+	if w == 0 {
+		return
+	}
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(w)), int(math.Ceil(h))))
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(t.Color()), Face: f.face}
+	for i, l := range strings.Split(s, "\n") {
+		lw := float26(font.MeasureString(f.face, l))
+		x := (w - lw) * float64(align) / 2
+		d.Dot = fixed.Point26_6{X: fixed.Int26_6(x * 64), Y: fixed.Int26_6((f.ascent + float64(i)*f.line) * 64)}
+		d.DrawString(l)
+	}
+	x0, y0 := t.Position()
+	cx := x0 - w*float64(align)/2 + float64(img.Rect.Dx())/2
+	cy := y0 + f.ascent - float64(img.Rect.Dy())/2
+	t.JumpTo(cx, cy)
+	t.StampImage(img)
+	t.JumpTo(x0, y0)
+}
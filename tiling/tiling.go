@@ -0,0 +1,174 @@
+// Package tiling fills regions of a turtle's canvas with repeated tiles:
+// Truchet arcs, hexagon grids and rhombus (tumbling block) tilings, or any
+// tile of your own drawn with the turtle.
+//
+//	r := tiling.Rect{MinX: -200, MinY: -200, MaxX: 200, MaxY: 200}
+//	tiling.Truchet(t, r, 20, rand.New(rand.NewPCG(1, 0)))
+//
+// Tiles are laid out from the region's lower-left corner and cover it
+// completely, so those along the top and right edges may overhang it.
+package tiling
+
+import (
+	"image/color"
+	"math"
+	"math/rand/v2"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/Z6dev/GoTuga/internal/random"
+)
+
+// Rect is a region in logical coordinates.
+type Rect struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Tile draws tile (i, j) of a tiling, i counting columns from the left and
+// j rows from the bottom. The turtle is at the tile's origin facing east,
+// and is put back there afterwards.
+type Tile func(t *gotuga.Turtle, i, j int)
+
+// Grid stamps tile over r on a grid of w×h cells; each tile's origin is its
+// lower-left corner.
+func Grid(t *gotuga.Turtle, r Rect, w, h float64, tile Tile) {
+	cols := int(math.Ceil((r.MaxX - r.MinX) / w))
+	rows := int(math.Ceil((r.MaxY - r.MinY) / h))
+	stamp(t, func(yield func(x, y float64, i, j int)) {
+		for j := 0; j < rows; j++ {
+			for i := 0; i < cols; i++ {
+				yield(r.MinX+float64(i)*w, r.MinY+float64(j)*h, i, j)
+			}
+		}
+	}, tile)
+}
+
+// HexGrid stamps tile over r on a grid of pointy-top hexagons with the given
+// circumradius; each tile's origin is its hexagon's center. Odd rows are
+// shifted right by half a hexagon.
+func HexGrid(t *gotuga.Turtle, r Rect, size float64, tile Tile) {
+	w := math.Sqrt(3) * size // center spacing within a row
+	h := 1.5 * size          // row spacing
+	cols := int(math.Ceil((r.MaxX-r.MinX)/w)) + 1
+	rows := int(math.Ceil((r.MaxY-r.MinY)/h)) + 1
+	stamp(t, func(yield func(x, y float64, i, j int)) {
+		for j := 0; j < rows; j++ {
+			shift := float64(j%2) * w / 2
+			for i := 0; i < cols; i++ {
+				yield(r.MinX+float64(i)*w+shift, r.MinY+float64(j)*h, i, j)
+			}
+		}
+	}, tile)
+}
+
+// stamp runs tile at every position produced by each, restoring the
+// turtle's position, heading and pen between tiles.
+func stamp(t *gotuga.Turtle, each func(yield func(x, y float64, i, j int)), tile Tile) {
+	x0, y0 := t.Position()
+	heading := t.Heading()
+	down := t.IsDown()
+	each(func(x, y float64, i, j int) {
+		t.JumpTo(x, y)
+		t.SetHeading(0)
+		tile(t, i, j)
+		if !down {
+			t.PenUp()
+		} else if !t.IsDown() {
+			t.PenDown()
+		}
+	})
+	t.JumpTo(x0, y0)
+	t.SetHeading(heading)
+}
+
+// Truchet fills r with square Truchet tiles of the given size: each holds
+// two quarter-circle arcs joining the midpoints of its sides, in one of the
+// two orientations at random. A nil rng uses the unseeded global source.
+func Truchet(t *gotuga.Turtle, r Rect, size float64, rng *rand.Rand) {
+	Grid(t, r, size, size, func(t *gotuga.Turtle, i, j int) {
+		x, y := t.Position()
+		half := size / 2
+		if random.IntN(rng, 2) == 0 {
+			arc(t, x, y, half, 0, 90)
+			arc(t, x+size, y+size, half, 180, 270)
+		} else {
+			arc(t, x+size, y, half, 90, 180)
+			arc(t, x, y+size, half, 270, 360)
+		}
+	})
+}
+
+// Hexagons fills r with outlined pointy-top hexagons of the given
+// circumradius.
+func Hexagons(t *gotuga.Turtle, r Rect, size float64) {
+	HexGrid(t, r, size, func(t *gotuga.Turtle, i, j int) {
+		x, y := t.Position()
+		hexagon(t, x, y, size)
+	})
+}
+
+// Rhombille fills r with a rhombus tiling, each hexagon of the given
+// circumradius split into three rhombi that read as a cube. The rhombi are
+// filled with the three shades (top, left, right) when they are not nil,
+// and outlined with the pen.
+func Rhombille(t *gotuga.Turtle, r Rect, size float64, shades [3]color.Color) {
+	HexGrid(t, r, size, func(t *gotuga.Turtle, i, j int) {
+		x, y := t.Position()
+		// Hexagon corners, counterclockwise from the top.
+		var cx, cy [6]float64
+		for k := range cx {
+			a := (90 + 60*float64(k)) * math.Pi / 180
+			cx[k], cy[k] = x+size*math.Cos(a), y+size*math.Sin(a)
+		}
+		faces := [3][4]int{{0, 1, -1, 5}, {1, 2, 3, -1}, {-1, 3, 4, 5}}
+		for f, face := range faces {
+			var px, py [4]float64
+			for k, c := range face {
+				if c < 0 {
+					px[k], py[k] = x, y
+				} else {
+					px[k], py[k] = cx[c], cy[c]
+				}
+			}
+			polygon(t, px[:], py[:], shades[f])
+		}
+	})
+}
+
+// arc draws part of a circle around (cx, cy), from angle a0 to a1 in
+// degrees counterclockwise from east.
+func arc(t *gotuga.Turtle, cx, cy, r, a0, a1 float64) {
+	n := int(math.Max(4, math.Abs(a1-a0)*math.Pi/180*r/3))
+	for k := 0; k <= n; k++ {
+		a := (a0 + (a1-a0)*float64(k)/float64(n)) * math.Pi / 180
+		x, y := cx+r*math.Cos(a), cy+r*math.Sin(a)
+		if k == 0 {
+			t.JumpTo(x, y)
+		} else {
+			t.GoTo(x, y)
+		}
+	}
+}
+
+func hexagon(t *gotuga.Turtle, x, y, size float64) {
+	var px, py [6]float64
+	for k := range px {
+		a := (90 + 60*float64(k)) * math.Pi / 180
+		px[k], py[k] = x+size*math.Cos(a), y+size*math.Sin(a)
+	}
+	polygon(t, px[:], py[:], nil)
+}
+
+// polygon outlines a closed polygon, filling it first when fill is set.
+func polygon(t *gotuga.Turtle, xs, ys []float64, fill color.Color) {
+	t.JumpTo(xs[0], ys[0])
+	if fill != nil {
+		t.FillColor(fill)
+		t.BeginFill()
+	}
+	for k := 1; k <= len(xs); k++ {
+		t.GoTo(xs[k%len(xs)], ys[k%len(ys)])
+	}
+	if fill != nil {
+		t.EndFill()
+	}
+}
@@ -0,0 +1,36 @@
+package gotuga
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// SetTrace writes a line to w after every top-level command, with the
+// command, where it left the turtle and its heading, such as
+//
+//	FORWARD 100 → (70.7, 70.7) heading 45°
+//
+// so students and bug reporters can show exactly what the turtle did. A
+// nil w stops tracing. Write errors are ignored.
+func (t *Turtle) SetTrace(w io.Writer) { t.trace = w }
+
+// writeTrace writes the trace line for c.
+func (t *Turtle) writeTrace(c Command) {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(c.Name))
+	if (c.Name == "color" || c.Name == "fillcolor") && len(c.Args) == 4 {
+		b.WriteString(" " + hexColor(argColor(c.Args)))
+	} else {
+		for _, a := range c.Args {
+			fmt.Fprintf(&b, " %g", a)
+		}
+	}
+	heading := math.Mod(t.headingDeg, 360)
+	if heading < 0 {
+		heading += 360
+	}
+	fmt.Fprintf(&b, " → %s heading %s°\n", coords(t.x, t.y), tenths(heading))
+	io.WriteString(t.trace, b.String())
+}
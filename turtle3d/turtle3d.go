@@ -0,0 +1,242 @@
+// Package turtle3d is a turtle that moves through three dimensions and
+// draws through a camera onto an ordinary gotuga canvas.
+//
+//	t := gotuga.New(600, 600, color.White)
+//	tt := turtle3d.New(t, turtle3d.DefaultCamera())
+//	tt.Camera.Eye = turtle3d.Vec3{X: 300, Y: 400, Z: 800}
+//	for i := 0; i < 360; i++ { // a coiling spiral
+//		tt.Forward(10)
+//		tt.Yaw(5)
+//		tt.Pitch(0.5)
+//	}
+//
+// World coordinates extend the 2D turtle's: x to the right, y up and z
+// towards the viewer. A new turtle is at the origin heading along +x with
+// its left side towards +y and its back up towards +z, so with the default
+// camera Forward and Yaw draw just like Forward and Left in 2D.
+//
+// The 3D turtle moves the 2D turtle with GoTo and shares its pen, so pen
+// color and width, fills, recording and real-time mode all apply as usual.
+package turtle3d
+
+import (
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// Vec3 is a point or direction in world coordinates.
+type Vec3 struct{ X, Y, Z float64 }
+
+func (v Vec3) add(w Vec3) Vec3      { return Vec3{v.X + w.X, v.Y + w.Y, v.Z + w.Z} }
+func (v Vec3) sub(w Vec3) Vec3      { return Vec3{v.X - w.X, v.Y - w.Y, v.Z - w.Z} }
+func (v Vec3) scale(k float64) Vec3 { return Vec3{v.X * k, v.Y * k, v.Z * k} }
+func (v Vec3) dot(w Vec3) float64   { return v.X*w.X + v.Y*w.Y + v.Z*w.Z }
+func (v Vec3) cross(w Vec3) Vec3 {
+	return Vec3{v.Y*w.Z - v.Z*w.Y, v.Z*w.X - v.X*w.Z, v.X*w.Y - v.Y*w.X}
+}
+func (v Vec3) unit() Vec3            { return v.scale(1 / math.Sqrt(v.dot(v))) }
+func lerp(a, b Vec3, f float64) Vec3 { return a.add(b.sub(a).scale(f)) }
+
+// Camera projects world coordinates onto the 2D turtle's logical plane.
+type Camera struct {
+	Eye, Target Vec3 // where the camera is and what it looks at
+	Up          Vec3 // which way is up on the canvas
+
+	// Focal is the perspective focal length: things at that distance in
+	// front of the eye are drawn at their true size, nearer ones larger.
+	// Zero gives an orthographic projection with everything at true size.
+	Focal float64
+}
+
+// DefaultCamera looks at the origin down the z axis from 1000 units away,
+// with perspective, so the plane z = 0 is drawn as the 2D turtle would.
+func DefaultCamera() Camera {
+	return Camera{Eye: Vec3{0, 0, 1000}, Up: Vec3{0, 1, 0}, Focal: 1000}
+}
+
+// Project returns where p appears on the canvas, in the 2D turtle's
+// logical coordinates, and its depth in front of the eye. Points with a
+// depth of zero or less are behind the camera.
+func (c Camera) Project(p Vec3) (x, y, depth float64) {
+	f := c.Target.sub(c.Eye).unit()
+	r := f.cross(c.Up).unit()
+	u := r.cross(f)
+	d := p.sub(c.Eye)
+	x, y, depth = d.dot(r), d.dot(u), d.dot(f)
+	if c.Focal > 0 {
+		x, y = x*c.Focal/depth, y*c.Focal/depth
+	}
+	return x, y, depth
+}
+
+// near returns the smallest depth drawn: lines are cut off closer to the
+// eye than this.
+func (c Camera) near() float64 {
+	if c.Focal > 0 {
+		return c.Focal * 1e-3
+	}
+	return math.Inf(-1)
+}
+
+// Turtle is a 3D turtle drawing through a camera with a 2D turtle.
+type Turtle struct {
+	T      *gotuga.Turtle
+	Camera Camera
+
+	pos     Vec3
+	h, l, u Vec3 // heading, left and up: the turtle's orientation
+	stack   []state
+}
+
+type state struct {
+	pos, h, l, u Vec3
+	down         bool
+}
+
+// New returns a 3D turtle at the origin drawing with t through cam.
+func New(t *gotuga.Turtle, cam Camera) *Turtle {
+	tt := &Turtle{T: t, Camera: cam}
+	tt.Reset()
+	return tt
+}
+
+// Reset returns the turtle to the origin in its starting orientation
+// without drawing.
+func (t *Turtle) Reset() {
+	t.pos = Vec3{}
+	t.h, t.l, t.u = Vec3{1, 0, 0}, Vec3{0, 1, 0}, Vec3{0, 0, 1}
+	t.sync()
+}
+
+// Position returns the turtle's position.
+func (t *Turtle) Position() Vec3 { return t.pos }
+
+// Orientation returns the turtle's heading, left and up directions, unit
+// vectors at right angles to each other.
+func (t *Turtle) Orientation() (heading, left, up Vec3) { return t.h, t.l, t.u }
+
+// PenUp stops drawing.
+func (t *Turtle) PenUp() { t.T.PenUp() }
+
+// PenDown starts drawing.
+func (t *Turtle) PenDown() { t.T.PenDown() }
+
+// IsDown reports whether the pen is down.
+func (t *Turtle) IsDown() bool { return t.T.IsDown() }
+
+// Forward moves d along the heading.
+func (t *Turtle) Forward(d float64) { t.GoTo(t.pos.add(t.h.scale(d))) }
+
+// Backward moves d against the heading.
+func (t *Turtle) Backward(d float64) { t.Forward(-d) }
+
+// GoTo moves to p in a straight line, drawing if the pen is down.
+func (t *Turtle) GoTo(p Vec3) {
+	if t.T.IsDown() {
+		t.line(t.pos, p)
+	}
+	t.pos = p
+	t.sync()
+}
+
+// Yaw turns left by deg degrees around the up direction.
+func (t *Turtle) Yaw(deg float64) { t.h, t.l = rotate(t.h, t.l, deg) }
+
+// Pitch tilts the nose up by deg degrees around the left direction.
+func (t *Turtle) Pitch(deg float64) { t.h, t.u = rotate(t.h, t.u, deg) }
+
+// Roll banks by deg degrees around the heading, lifting the left side.
+func (t *Turtle) Roll(deg float64) { t.l, t.u = rotate(t.l, t.u, deg) }
+
+// rotate turns the pair of directions a, b by deg degrees from a towards b.
+func rotate(a, b Vec3, deg float64) (Vec3, Vec3) {
+	s, c := math.Sincos(deg * math.Pi / 180)
+	return a.scale(c).add(b.scale(s)).unit(), b.scale(c).sub(a.scale(s)).unit()
+}
+
+// Push saves the turtle's position, orientation and pen state.
+func (t *Turtle) Push() {
+	t.stack = append(t.stack, state{t.pos, t.h, t.l, t.u, t.T.IsDown()})
+}
+
+// Pop returns to the last saved state without drawing. Popping an empty
+// stack does nothing.
+func (t *Turtle) Pop() {
+	if len(t.stack) == 0 {
+		return
+	}
+	s := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	t.pos, t.h, t.l, t.u = s.pos, s.h, s.l, s.u
+	t.sync()
+	if s.down != t.T.IsDown() {
+		if s.down {
+			t.T.PenDown()
+		} else {
+			t.T.PenUp()
+		}
+	}
+}
+
+// Interpret draws an expanded 3D L-system string, such as one from
+// lsystem.System.Expand, with the usual symbols: F draws and f moves step
+// forward; + and - yaw, & and ^ pitch down and up, \ and / roll, each by
+// angle degrees; | turns around; [ and ] push and pop. Other symbols are
+// ignored.
+func (t *Turtle) Interpret(symbols string, step, angle float64) {
+	for _, r := range symbols {
+		switch r {
+		case 'F':
+			t.Forward(step)
+		case 'f':
+			t.pos = t.pos.add(t.h.scale(step))
+			t.sync()
+		case '+':
+			t.Yaw(angle)
+		case '-':
+			t.Yaw(-angle)
+		case '&':
+			t.Pitch(-angle)
+		case '^':
+			t.Pitch(angle)
+		case '\\':
+			t.Roll(angle)
+		case '/':
+			t.Roll(-angle)
+		case '|':
+			t.Yaw(180)
+		case '[':
+			t.Push()
+		case ']':
+			t.Pop()
+		}
+	}
+}
+
+// line draws a to b, cut off where it passes behind the camera.
+func (t *Turtle) line(a, b Vec3) {
+	near := t.Camera.near()
+	_, _, da := t.Camera.Project(a)
+	_, _, db := t.Camera.Project(b)
+	if da < near && db < near {
+		return
+	}
+	if da < near {
+		a = lerp(a, b, (near-da)/(db-da))
+	} else if db < near {
+		b = lerp(a, b, (near-da)/(db-da))
+	}
+	ax, ay, _ := t.Camera.Project(a)
+	bx, by, _ := t.Camera.Project(b)
+	t.T.JumpTo(ax, ay)
+	t.T.GoTo(bx, by)
+}
+
+// sync moves the 2D turtle, without drawing, to where the 3D turtle
+// appears, if it is in front of the camera.
+func (t *Turtle) sync() {
+	if x, y, d := t.Camera.Project(t.pos); d >= t.Camera.near() {
+		t.T.JumpTo(x, y)
+	}
+}
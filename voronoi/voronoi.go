@@ -0,0 +1,204 @@
+// Package voronoi computes Delaunay triangulations and Voronoi diagrams of
+// points in logical coordinates and draws them with a turtle's current pen.
+//
+//	pts := []voronoi.Point{{-100, 20}, {50, 80}, {120, -60}, {-30, -90}}
+//	voronoi.DrawVoronoi(t, pts, voronoi.Rect{MinX: -200, MinY: -150, MaxX: 200, MaxY: 150})
+//	voronoi.DrawDelaunay(t, pts)
+package voronoi
+
+import (
+	"math"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// Point is a point in logical coordinates.
+type Point struct{ X, Y float64 }
+
+// Rect bounds a Voronoi diagram, whose outer cells are otherwise infinite.
+type Rect struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Triangulate returns the Delaunay triangulation of pts as triples of
+// indices into pts, each listed counterclockwise. Duplicate points are
+// ignored.
+func Triangulate(pts []Point) [][3]int {
+	if len(pts) < 3 {
+		return nil
+	}
+	// Bowyer–Watson, starting from a triangle enclosing every point.
+	minX, minY, maxX, maxY := bbox(pts)
+	d := math.Max(maxX-minX, maxY-minY)*10 + 1
+	cx, cy := (minX+maxX)/2, (minY+maxY)/2
+	all := append(append([]Point(nil), pts...),
+		Point{cx - 2*d, cy - d}, Point{cx + 2*d, cy - d}, Point{cx, cy + 2*d})
+	n := len(pts)
+	tris := []triangle{newTriangle(all, n, n+1, n+2)}
+
+	for i, p := range pts {
+		var keep []triangle
+		edges := make(map[[2]int]int)
+		for _, tr := range tris {
+			if !tr.encloses(p) {
+				keep = append(keep, tr)
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				a, b := tr.v[k], tr.v[(k+1)%3]
+				if a > b {
+					a, b = b, a
+				}
+				edges[[2]int{a, b}]++
+			}
+		}
+		if len(keep) == len(tris) {
+			continue // a duplicate of an earlier point
+		}
+		for e, count := range edges {
+			if count == 1 {
+				keep = append(keep, newTriangle(all, e[0], e[1], i))
+			}
+		}
+		tris = keep
+	}
+
+	var out [][3]int
+	for _, tr := range tris {
+		if tr.v[0] < n && tr.v[1] < n && tr.v[2] < n {
+			out = append(out, tr.v)
+		}
+	}
+	return out
+}
+
+type triangle struct {
+	v      [3]int
+	cx, cy float64 // circumcenter
+	r2     float64 // circumradius squared
+}
+
+// newTriangle makes a counterclockwise triangle with its circumcircle.
+func newTriangle(pts []Point, a, b, c int) triangle {
+	pa, pb, pc := pts[a], pts[b], pts[c]
+	if (pb.X-pa.X)*(pc.Y-pa.Y)-(pb.Y-pa.Y)*(pc.X-pa.X) < 0 {
+		b, c = c, b
+		pb, pc = pc, pb
+	}
+	d := 2 * (pa.X*(pb.Y-pc.Y) + pb.X*(pc.Y-pa.Y) + pc.X*(pa.Y-pb.Y))
+	tr := triangle{v: [3]int{a, b, c}}
+	if d == 0 {
+		tr.r2 = math.Inf(1) // collinear: encloses everything, so it is replaced
+		return tr
+	}
+	sa, sb, sc := pa.X*pa.X+pa.Y*pa.Y, pb.X*pb.X+pb.Y*pb.Y, pc.X*pc.X+pc.Y*pc.Y
+	tr.cx = (sa*(pb.Y-pc.Y) + sb*(pc.Y-pa.Y) + sc*(pa.Y-pb.Y)) / d
+	tr.cy = (sa*(pc.X-pb.X) + sb*(pa.X-pc.X) + sc*(pb.X-pa.X)) / d
+	tr.r2 = (pa.X-tr.cx)*(pa.X-tr.cx) + (pa.Y-tr.cy)*(pa.Y-tr.cy)
+	return tr
+}
+
+func (tr triangle) encloses(p Point) bool {
+	dx, dy := p.X-tr.cx, p.Y-tr.cy
+	return dx*dx+dy*dy <= tr.r2*(1+1e-12)
+}
+
+// Cells returns the Voronoi cell of every point, clipped to bounds, as a
+// counterclockwise polygon. Cells of points outside bounds, or duplicates
+// of earlier points, are empty.
+func Cells(pts []Point, bounds Rect) [][]Point {
+	cells := make([][]Point, len(pts))
+	for i, p := range pts {
+		cell := []Point{
+			{bounds.MinX, bounds.MinY}, {bounds.MaxX, bounds.MinY},
+			{bounds.MaxX, bounds.MaxY}, {bounds.MinX, bounds.MaxY},
+		}
+		for j, q := range pts {
+			if j == i || len(cell) == 0 {
+				continue
+			}
+			if q == p {
+				if j < i {
+					cell = nil
+				}
+				continue
+			}
+			// Keep the half-plane closer to p than to q.
+			nx, ny := q.X-p.X, q.Y-p.Y
+			c := (q.X*q.X + q.Y*q.Y - p.X*p.X - p.Y*p.Y) / 2
+			cell = clipHalfPlane(cell, nx, ny, c)
+		}
+		if p.X < bounds.MinX || p.X > bounds.MaxX || p.Y < bounds.MinY || p.Y > bounds.MaxY {
+			cell = nil
+		}
+		cells[i] = cell
+	}
+	return cells
+}
+
+// clipHalfPlane keeps the part of a convex polygon where nx·x + ny·y <= c.
+func clipHalfPlane(poly []Point, nx, ny, c float64) []Point {
+	var out []Point
+	for k := range poly {
+		a, b := poly[k], poly[(k+1)%len(poly)]
+		da := nx*a.X + ny*a.Y - c
+		db := nx*b.X + ny*b.Y - c
+		if da <= 0 {
+			out = append(out, a)
+		}
+		if (da < 0) != (db < 0) && da != db {
+			s := da / (da - db)
+			out = append(out, Point{a.X + s*(b.X-a.X), a.Y + s*(b.Y-a.Y)})
+		}
+	}
+	return out
+}
+
+// DrawDelaunay draws every edge of the Delaunay triangulation of pts once.
+// The turtle ends where it started.
+func DrawDelaunay(t *gotuga.Turtle, pts []Point) {
+	drawn := make(map[[2]int]bool)
+	x0, y0 := t.Position()
+	for _, tr := range Triangulate(pts) {
+		for k := 0; k < 3; k++ {
+			a, b := tr[k], tr[(k+1)%3]
+			if a > b {
+				a, b = b, a
+			}
+			if drawn[[2]int{a, b}] {
+				continue
+			}
+			drawn[[2]int{a, b}] = true
+			t.JumpTo(pts[a].X, pts[a].Y)
+			t.GoTo(pts[b].X, pts[b].Y)
+		}
+	}
+	t.JumpTo(x0, y0)
+}
+
+// DrawVoronoi outlines the Voronoi cell of every point, clipped to bounds.
+// The turtle ends where it started.
+func DrawVoronoi(t *gotuga.Turtle, pts []Point, bounds Rect) {
+	x0, y0 := t.Position()
+	for _, cell := range Cells(pts, bounds) {
+		if len(cell) == 0 {
+			continue
+		}
+		t.JumpTo(cell[0].X, cell[0].Y)
+		for k := 1; k <= len(cell); k++ {
+			p := cell[k%len(cell)]
+			t.GoTo(p.X, p.Y)
+		}
+	}
+	t.JumpTo(x0, y0)
+}
+
+func bbox(pts []Point) (minX, minY, maxX, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, p := range pts {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	return
+}
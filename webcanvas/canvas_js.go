@@ -0,0 +1,86 @@
+//go:build js && wasm
+
+package webcanvas
+
+import (
+	"errors"
+	"syscall/js"
+	"time"
+
+	gotuga "github.com/Z6dev/GoTuga"
+)
+
+// Options configures how the canvas element is updated. The zero value is usable.
+type Options struct {
+	// Delay is slept after every command so drawing can be watched.
+	Delay time.Duration
+	// MaxFPS limits how often the element is repainted. Zero means 60.
+	MaxFPS int
+}
+
+// Canvas mirrors a turtle's canvas into an HTML canvas element.
+type Canvas struct {
+	t    *gotuga.Turtle
+	opts Options
+	el   js.Value
+	ctx  js.Value
+	buf  js.Value // Uint8ClampedArray sized to the canvas
+	data js.Value // ImageData wrapping buf
+	w, h int      // canvas size buf was made for
+	last time.Time
+	stop func()
+}
+
+// Attach mirrors t into the <canvas> element with the given id, resizing the
+// element to the turtle's canvas. The element is repainted as commands run;
+// call Flush after drawing to show the final state.
+func Attach(t *gotuga.Turtle, id string, opts *Options) (*Canvas, error) {
+	el := js.Global().Get("document").Call("getElementById", id)
+	if el.IsNull() || el.IsUndefined() {
+		return nil, errors.New("webcanvas: no element with id " + id)
+	}
+	c := &Canvas{t: t}
+	if opts != nil {
+		c.opts = *opts
+	}
+	c.el = el
+	c.ctx = el.Call("getContext", "2d")
+	c.Flush()
+	c.stop = t.Observe(func(gotuga.Command) { c.afterCommand() })
+	return c, nil
+}
+
+func (c *Canvas) afterCommand() {
+	interval := time.Second / 60
+	if c.opts.MaxFPS > 0 {
+		interval = time.Second / time.Duration(c.opts.MaxFPS)
+	}
+	if c.opts.Delay > 0 || time.Since(c.last) >= interval {
+		c.Flush()
+		// Yield to the browser so it can paint the new frame.
+		time.Sleep(max(c.opts.Delay, time.Millisecond))
+	}
+}
+
+// Flush repaints the element with the current canvas.
+func (c *Canvas) Flush() {
+	if c.w != c.t.W || c.h != c.t.H {
+		// First flush, or the canvas was expanded.
+		c.w, c.h = c.t.W, c.t.H
+		c.el.Set("width", c.w)
+		c.el.Set("height", c.h)
+		c.buf = js.Global().Get("Uint8ClampedArray").New(len(c.t.Image().Pix))
+		c.data = js.Global().Get("ImageData").New(c.buf, c.w, c.h)
+	}
+	js.CopyBytesToJS(c.buf, c.t.Image().Pix)
+	c.ctx.Call("putImageData", c.data, 0, 0)
+	c.last = time.Now()
+}
+
+// Close stops mirroring the turtle.
+func (c *Canvas) Close() {
+	if c.stop != nil {
+		c.stop()
+		c.stop = nil
+	}
+}
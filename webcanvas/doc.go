@@ -0,0 +1,15 @@
+// Package webcanvas mirrors a turtle's canvas into an HTML <canvas> element
+// when the program is compiled to WebAssembly (GOOS=js GOARCH=wasm).
+//
+// Build the program and serve it next to wasm_exec.js (shipped with Go under
+// lib/wasm) and gotuga.js from this directory:
+//
+//	GOOS=js GOARCH=wasm go build -o main.wasm .
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//
+// then load it from a page containing <canvas id="turtle"></canvas> with:
+//
+//	<script src="wasm_exec.js"></script>
+//	<script src="gotuga.js"></script>
+//	<script>runGoTuga("main.wasm")</script>
+package webcanvas
@@ -0,0 +1,6 @@
+// Loads and runs a GoTuga WebAssembly program. Requires wasm_exec.js.
+async function runGoTuga(wasmURL) {
+  const go = new Go();
+  const result = await WebAssembly.instantiateStreaming(fetch(wasmURL), go.importObject);
+  await go.run(result.instance);
+}
@@ -0,0 +1,184 @@
+package window
+
+import (
+	"image"
+	"time"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Key repeat timing, in ticks (1/60 s): a held key repeats after
+// repeatDelay ticks, then every repeatInterval ticks.
+const (
+	repeatDelay    = 15
+	repeatInterval = 3
+)
+
+// OnKey makes fn run whenever key is pressed, repeating while it is held,
+// like onkey in Python's turtle. A nil fn removes the binding.
+func (w *Window) OnKey(key ebiten.Key, fn func(t *gotuga.Turtle)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if fn == nil {
+		delete(w.keys, key)
+		return
+	}
+	w.keys[key] = fn
+}
+
+func (w *Window) bound(key ebiten.Key) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.keys[key]
+	return ok
+}
+
+// dispatchKeys queues the handlers of keys pressed or repeating this tick.
+func (w *Window) dispatchKeys() {
+	w.mu.Lock()
+	var due []func(t *gotuga.Turtle)
+	for k, fn := range w.keys {
+		d := inpututil.KeyPressDuration(k)
+		if d == 1 || (d > repeatDelay && (d-repeatDelay)%repeatInterval == 0) {
+			due = append(due, fn)
+		}
+	}
+	w.mu.Unlock()
+	for _, fn := range due {
+		w.post(func() { fn(w.t) })
+	}
+}
+
+type mouseHandlers struct {
+	click, drag, release func(x, y float64)
+}
+
+// OnClick makes fn run when the left mouse button is pressed over the
+// window, with the cursor position in the turtle's logical coordinates.
+// A nil fn removes the handler.
+func (w *Window) OnClick(fn func(x, y float64)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mouse.click = fn
+}
+
+// OnDrag makes fn run whenever the mouse moves with the left button held,
+// with the cursor position in logical coordinates.
+func (w *Window) OnDrag(fn func(x, y float64)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mouse.drag = fn
+}
+
+// OnRelease makes fn run when the left mouse button is released, with the
+// cursor position in logical coordinates.
+func (w *Window) OnRelease(fn func(x, y float64)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mouse.release = fn
+}
+
+// dispatchMouse queues the mouse handlers for this tick's button changes and
+// drag motion.
+func (w *Window) dispatchMouse() {
+	w.mu.Lock()
+	h := w.mouse
+	w.mu.Unlock()
+
+	cx, cy := ebiten.CursorPosition()
+	x, y := w.logical(cx, cy)
+	call := func(fn func(x, y float64)) {
+		if fn != nil {
+			w.post(func() { fn(x, y) })
+		}
+	}
+	switch {
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		w.dragging = true
+		w.lastDrag = image.Pt(cx, cy)
+		call(h.click)
+	case inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft):
+		w.dragging = false
+		call(h.release)
+	case w.dragging && image.Pt(cx, cy) != w.lastDrag:
+		w.lastDrag = image.Pt(cx, cy)
+		call(h.drag)
+	}
+}
+
+// logical converts a cursor position on screen into the turtle's logical
+// coordinates (origin at the canvas center, +y up).
+func (w *Window) logical(cx, cy int) (float64, float64) {
+	ox, oy := w.origin()
+	px := (float64(cx) - ox) / w.zoom
+	py := (float64(cy) - oy) / w.zoom
+	return px - float64(w.t.W)/2, float64(w.t.H)/2 - py
+}
+
+// OnTimer makes fn run every interval while the window is open, the first
+// time one interval after the call. Ticks are skipped while the drawing
+// program is too busy to keep up.
+func (w *Window) OnTimer(interval time.Duration, fn func(t *gotuga.Turtle)) {
+	if interval <= 0 || fn == nil {
+		return
+	}
+	go func() {
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				w.post(func() { fn(w.t) })
+			case <-w.closed:
+				return
+			}
+		}
+	}()
+}
+
+// post queues fn to run on the drawing goroutine. Events are dropped when
+// the drawing program falls too far behind.
+func (w *Window) post(fn func()) {
+	select {
+	case w.events <- fn:
+	default:
+	}
+}
+
+// drainEvents runs queued handlers between the commands of the drawing
+// program. Commands issued by a handler do not run further handlers.
+func (w *Window) drainEvents() {
+	if w.handling {
+		return
+	}
+	for {
+		select {
+		case fn := <-w.events:
+			w.handle(fn)
+		default:
+			return
+		}
+	}
+}
+
+// loop runs handlers once the drawing program has returned, until the
+// window is closed, like Python turtle's mainloop.
+func (w *Window) loop() {
+	for {
+		select {
+		case fn := <-w.events:
+			w.handle(fn)
+			w.capture()
+		case <-w.closed:
+			return
+		}
+	}
+}
+
+func (w *Window) handle(fn func()) {
+	w.handling = true
+	defer func() { w.handling = false }()
+	fn()
+}
@@ -0,0 +1,19 @@
+module github.com/Z6dev/GoTuga/window
+
+go 1.24.5
+
+require (
+	github.com/Z6dev/GoTuga v0.0.0
+	github.com/hajimehoshi/ebiten/v2 v2.8.8
+)
+
+require (
+	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
+	github.com/ebitengine/hideconsole v1.0.0 // indirect
+	github.com/ebitengine/purego v0.8.0 // indirect
+	github.com/jezek/xgb v1.1.1 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.25.0 // indirect
+)
+
+replace github.com/Z6dev/GoTuga => ../
@@ -0,0 +1,249 @@
+// Package window shows a turtle's canvas live in a desktop window while a
+// drawing program runs. It is a separate module so that programs which only
+// render to files do not pull in the windowing dependencies.
+//
+// Controls: Space pauses and resumes, S executes a single command while
+// paused, +/- zoom and 0 resets the zoom. Arrow keys drive the turtle unless
+// rebound with OnKey; a key bound with OnKey overrides its built-in control.
+package window
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	gotuga "github.com/Z6dev/GoTuga"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Options configures a Window. The zero value is usable.
+type Options struct {
+	Title string        // window title, defaults to "GoTuga"
+	Zoom  float64       // initial zoom factor, defaults to 1
+	Delay time.Duration // pause after every command, to slow drawing down
+
+	NoArrowKeys bool // disable the default arrow-key bindings
+}
+
+// Window displays a turtle's canvas and lets the user pause, step and zoom
+// while the drawing program runs.
+type Window struct {
+	t    *gotuga.Turtle
+	opts Options
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	frame    *image.RGBA // latest copy of the canvas, guarded by mu
+	dirty    bool
+	paused   bool
+	stepping bool
+	lastCopy time.Time
+	keys     map[ebiten.Key]func(t *gotuga.Turtle) // guarded by mu
+	mouse    mouseHandlers                         // guarded by mu
+
+	events   chan func()   // handlers waiting to run on the drawing goroutine
+	closed   chan struct{} // closed when the window is closed
+	handling bool          // a handler is running; drawing goroutine only
+	detach   func()        // removes the observer; drawing goroutine only
+	detached chan struct{} // closed once it has
+
+	img      *ebiten.Image
+	zoom     float64
+	screen   image.Point // size of the window's drawing area
+	dragging bool
+	lastDrag image.Point
+}
+
+// New creates a window for t. Nothing is shown until Run is called.
+func New(t *gotuga.Turtle, opts *Options) *Window {
+	w := &Window{t: t}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Title == "" {
+		w.opts.Title = "GoTuga"
+	}
+	if w.opts.Zoom <= 0 {
+		w.opts.Zoom = 1
+	}
+	w.zoom = w.opts.Zoom
+	w.cond = sync.NewCond(&w.mu)
+	w.frame = image.NewRGBA(t.Image().Bounds())
+	w.keys = make(map[ebiten.Key]func(t *gotuga.Turtle))
+	w.events = make(chan func(), 64)
+	w.closed = make(chan struct{})
+	w.detached = make(chan struct{})
+	if !w.opts.NoArrowKeys {
+		w.keys[ebiten.KeyArrowUp] = func(t *gotuga.Turtle) { t.Forward(10) }
+		w.keys[ebiten.KeyArrowDown] = func(t *gotuga.Turtle) { t.Backward(10) }
+		w.keys[ebiten.KeyArrowLeft] = func(t *gotuga.Turtle) { t.Left(15) }
+		w.keys[ebiten.KeyArrowRight] = func(t *gotuga.Turtle) { t.Right(15) }
+	}
+	return w
+}
+
+// Run opens the window and calls draw on a separate goroutine. It must be
+// called from the main goroutine and blocks until the window is closed and
+// draw has run another command or returned.
+//
+// Event handlers run on the drawing goroutine: between commands while draw
+// is running, and in an event loop once it has returned, so they never race
+// with the drawing program. draw may be nil for purely interactive programs.
+func (w *Window) Run(draw func(t *gotuga.Turtle)) error {
+	w.capture()
+	// The observer is removed on the drawing goroutine, which runs the
+	// turtle's observers, at its first command after the window closes or
+	// once it has finished.
+	stop := w.t.Observe(func(gotuga.Command) {
+		w.afterCommand()
+	})
+	w.detach = sync.OnceFunc(func() {
+		stop()
+		close(w.detached)
+	})
+
+	go func() {
+		defer w.detach()
+		if draw != nil {
+			draw(w.t)
+		}
+		w.capture()
+		w.loop()
+	}()
+
+	ebiten.SetWindowTitle(w.opts.Title)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetWindowSize(w.windowSize())
+	err := ebiten.RunGame(w)
+	close(w.closed)
+
+	// Release a drawing goroutine blocked on pause so it can finish.
+	w.mu.Lock()
+	w.paused = false
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	<-w.detached
+	return err
+}
+
+// afterCommand runs on the drawing goroutine after every command.
+func (w *Window) afterCommand() {
+	select {
+	case <-w.closed:
+		w.detach()
+		return
+	default:
+	}
+	w.mu.Lock()
+	throttled := time.Since(w.lastCopy) < time.Second/60
+	w.mu.Unlock()
+	if !throttled || w.opts.Delay > 0 || w.isPaused() {
+		w.capture()
+	}
+	if w.opts.Delay > 0 {
+		time.Sleep(w.opts.Delay)
+	}
+
+	w.mu.Lock()
+	for w.paused && !w.stepping {
+		w.cond.Wait()
+	}
+	w.stepping = false
+	w.mu.Unlock()
+
+	w.drainEvents()
+}
+
+// capture copies the canvas into the frame shown by the window.
+func (w *Window) capture() {
+	w.mu.Lock()
+	if img := w.t.Image(); img.Bounds() != w.frame.Bounds() {
+		w.frame = image.NewRGBA(img.Bounds()) // the canvas was expanded
+	}
+	copy(w.frame.Pix, w.t.Image().Pix)
+	w.dirty = true
+	w.lastCopy = time.Now()
+	w.mu.Unlock()
+}
+
+func (w *Window) isPaused() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.paused
+}
+
+func (w *Window) windowSize() (int, int) {
+	b := w.frame.Bounds()
+	return int(float64(b.Dx()) * w.zoom), int(float64(b.Dy()) * w.zoom)
+}
+
+// Update implements ebiten.Game.
+func (w *Window) Update() error {
+	w.dispatchKeys()
+	w.dispatchMouse()
+	w.handleControls()
+	return nil
+}
+
+// handleControls applies the built-in pause, step and zoom keys.
+func (w *Window) handleControls() {
+	pressed := func(keys ...ebiten.Key) bool {
+		for _, k := range keys {
+			if !w.bound(k) && inpututil.IsKeyJustPressed(k) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case pressed(ebiten.KeySpace):
+		w.mu.Lock()
+		w.paused = !w.paused
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	case pressed(ebiten.KeyS):
+		w.mu.Lock()
+		w.stepping = true
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	case pressed(ebiten.KeyEqual, ebiten.KeyNumpadAdd):
+		w.zoom *= 1.25
+	case pressed(ebiten.KeyMinus, ebiten.KeyNumpadSubtract):
+		w.zoom /= 1.25
+	case pressed(ebiten.Key0):
+		w.zoom = w.opts.Zoom
+	}
+}
+
+// Draw implements ebiten.Game.
+func (w *Window) Draw(screen *ebiten.Image) {
+	w.mu.Lock()
+	if w.img == nil {
+		w.img = ebiten.NewImage(w.frame.Bounds().Dx(), w.frame.Bounds().Dy())
+	}
+	if w.dirty {
+		w.img.WritePixels(w.frame.Pix)
+		w.dirty = false
+	}
+	w.mu.Unlock()
+
+	ox, oy := w.origin()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(w.zoom, w.zoom)
+	op.GeoM.Translate(ox, oy)
+	screen.DrawImage(w.img, op)
+}
+
+// origin returns where the top-left corner of the canvas is drawn on screen.
+func (w *Window) origin() (float64, float64) {
+	b := w.frame.Bounds()
+	return (float64(w.screen.X) - float64(b.Dx())*w.zoom) / 2,
+		(float64(w.screen.Y) - float64(b.Dy())*w.zoom) / 2
+}
+
+// Layout implements ebiten.Game.
+func (w *Window) Layout(outsideWidth, outsideHeight int) (int, int) {
+	w.screen = image.Pt(outsideWidth, outsideHeight)
+	return outsideWidth, outsideHeight
+}